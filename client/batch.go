@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+)
+
+// Batch queues commands so that they can be written back-to-back, without waiting for the
+// reply to one before sending the next. A batch of independent commands then costs a single
+// round trip, rather than one each.
+type Batch struct {
+	c     Client
+	calls []*BatchCall
+}
+
+// BatchCall is one command queued on a Batch. Once the batch has run, Reply holds the reply
+// to the command and Err is nil, or Err says why it failed. An error code in the reply is
+// reported through Err, as the error it stands for, as well as in Reply.
+type BatchCall struct {
+	Set     CommandSet
+	Command Command
+	Reply   *Reply
+	Err     error
+	data    []byte
+}
+
+func NewBatch(c Client) *Batch {
+	return &Batch{c: c}
+}
+
+// Add queues a command, returning the call that will hold its outcome.
+func (b *Batch) Add(set CommandSet, cmd Command, data []byte) *BatchCall {
+	bc := &BatchCall{Set: set, Command: cmd, data: data}
+	b.calls = append(b.calls, bc)
+	return bc
+}
+
+// add queues the command whose body is built by s. A failure to build it becomes the
+// call's error, and the command is not sent.
+func (b *Batch) add(set CommandSet, cmd Command, s S) *BatchCall {
+	bc := b.Add(set, cmd, s.Marshal())
+	bc.Err = s.Err()
+	return bc
+}
+
+// Calls returns the commands queued, in the order they were added.
+func (b *Batch) Calls() []*BatchCall {
+	return b.calls
+}
+
+func (b *Batch) Run() error {
+	return b.RunContext(context.Background())
+}
+
+// RunContext sends every queued command, then collects their replies in order. It returns
+// the first of the calls' errors, if there was one; each call holds its own. If ctx is
+// cancelled or expires, calls still awaiting their replies fail with ctx.Err().
+func (b *Batch) RunContext(ctx context.Context) error {
+	type sent struct {
+		id Id
+		ch <-chan *Reply
+	}
+	replies := make([]sent, len(b.calls))
+	for i, bc := range b.calls {
+		if bc.Err != nil {
+			continue
+		}
+		id, ch, err := b.c.SendContext(ctx, bc.Set, bc.Command, bc.data)
+		if err != nil {
+			b.c.Dispose(id)
+			bc.Err = err
+			continue
+		}
+		replies[i] = sent{id: id, ch: ch}
+	}
+	var first error
+	for i, bc := range b.calls {
+		if r := replies[i]; r.ch != nil {
+			bc.Reply, bc.Err = b.await(ctx, r.ch)
+			b.c.Dispose(r.id)
+		}
+		if first == nil {
+			first = bc.Err
+		}
+	}
+	return first
+}
+
+func (b *Batch) await(ctx context.Context, ch <-chan *Reply) (*Reply, error) {
+	var r *Reply
+	var ok bool
+	select {
+	case r, ok = <-ch:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.c.Done():
+		// A reply that arrived before the connection ended still counts
+		select {
+		case r, ok = <-ch:
+		default:
+		}
+	}
+	if !ok {
+		return nil, b.c.Err()
+	}
+	if r.ErrCode != 0 {
+		return r, lookupError(r.ErrCode)
+	}
+	return r, nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchPipelines(t *testing.T) {
+	here, there := net.Pipe()
+	// Hold every reply until the whole batch has arrived, then answer the last command first
+	const n = 3
+	var held []func()
+	go agent(t, there, func(p packet, reply func(uint16, []byte)) {
+		data := p.Data
+		held = append(held, func() {
+			if data[0] == 2 {
+				reply(uint16(ErrInvalidObject.(JdwpError).Code), nil)
+			} else {
+				reply(0, data)
+			}
+		})
+		if len(held) == n {
+			for i := n - 1; i >= 0; i-- {
+				held[i]()
+			}
+		}
+	})
+	c, err := New(here, WithLogger(NopLogger))
+	require.NoError(t, err)
+	defer c.Close()
+
+	b := NewBatch(c)
+	for i := 0; i < n; i++ {
+		b.Add(VirtualMachine, VirtualMachineVersion, []byte{uint8(i + 1)})
+	}
+	err = b.Run()
+	assert.Equal(t, ErrInvalidObject, err)
+
+	calls := b.Calls()
+	require.Len(t, calls, n)
+	assert.NoError(t, calls[0].Err)
+	assert.Equal(t, []byte{1}, calls[0].Reply.Data)
+	assert.Equal(t, ErrInvalidObject, calls[1].Err)
+	assert.Equal(t, ErrInvalidObject.(JdwpError).Code, calls[1].Reply.ErrCode)
+	assert.NoError(t, calls[2].Err)
+	assert.Equal(t, []byte{3}, calls[2].Reply.Data)
+	assert.Equal(t, 0, pending(c.(*client)))
+}
+
+func TestBatchContext(t *testing.T) {
+	c := silentVM(t)
+	defer c.Close()
+
+	b := NewBatch(c)
+	version := b.Add(VirtualMachine, VirtualMachineVersion, nil)
+	// A body that cannot be built is never sent
+	unbuilt := b.add(VirtualMachine, VirtualMachineVersion, IDSizes{ObjectIDSize: 4}.Seq().ObjectId(1<<40))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := b.RunContext(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, context.DeadlineExceeded, version.Err)
+	assert.Nil(t, version.Reply)
+	assert.Error(t, unbuilt.Err)
+	assert.Nil(t, unbuilt.Reply)
+	assert.Equal(t, 0, pending(c))
+}
+
+func TestBatchRepliesBeforeDisconnect(t *testing.T) {
+	const n = 3
+	for i := 0; i < 100; i++ {
+		here, there := net.Pipe()
+		// Answer the whole batch, then drop the connection
+		var held []func()
+		go agent(t, there, func(p packet, reply func(uint16, []byte)) {
+			data := p.Data
+			held = append(held, func() { reply(0, data) })
+			if len(held) == n {
+				for _, h := range held {
+					h()
+				}
+				there.Close()
+			}
+		})
+		c, err := New(here, WithLogger(NopLogger))
+		require.NoError(t, err)
+
+		b := NewBatch(c)
+		for j := 0; j < n; j++ {
+			b.Add(VirtualMachine, VirtualMachineVersion, []byte{uint8(j + 1)})
+		}
+		assert.NoError(t, b.Run())
+		c.Close()
+	}
+}
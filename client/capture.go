@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Direction says which way a captured packet travelled.
+type Direction string
+
+const (
+	Outgoing = Direction("out") // a command sent by the client
+	Incoming = Direction("in")  // a reply, event or command sent by the VM
+)
+
+// CaptureEntry is one packet in a capture. Captures are written as one JSON object per line.
+type CaptureEntry struct {
+	Time       time.Time  `json:"time"`
+	Direction  Direction  `json:"dir"`
+	Length     uint32     `json:"length"`
+	Id         Id         `json:"id"`
+	Flags      uint8      `json:"flags"`
+	CommandSet CommandSet `json:"set,omitempty"`     // for commands
+	Command    Command    `json:"command,omitempty"` // for commands
+	ErrCode    uint16     `json:"errCode,omitempty"` // for replies
+	Packet     []byte     `json:"packet"`            // the raw bytes, header included
+}
+
+func (e *CaptureEntry) IsReply() bool {
+	return e.Flags&FlagReply != 0
+}
+
+// Data is the body of the packet, following the header.
+func (e *CaptureEntry) Data() []byte {
+	if len(e.Packet) < HeaderLength {
+		return nil
+	}
+	return e.Packet[HeaderLength:]
+}
+
+// WithCapture records every packet sent or received to w.
+func WithCapture(w io.Writer) Option {
+	return func(c *client) {
+		c.capture = &capture{enc: json.NewEncoder(w)}
+	}
+}
+
+type capture struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (c *capture) record(dir Direction, packet []byte) error {
+	if c == nil {
+		return nil
+	}
+	e := CaptureEntry{
+		Time:      time.Now(),
+		Direction: dir,
+		Length:    binary.BigEndian.Uint32(packet[0:]),
+		Id:        Id(binary.BigEndian.Uint32(packet[4:])),
+		Flags:     packet[8],
+		Packet:    packet,
+	}
+	if e.IsReply() {
+		e.ErrCode = binary.BigEndian.Uint16(packet[9:])
+	} else {
+		e.CommandSet = CommandSet(packet[9])
+		e.Command = Command(packet[10])
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enc.Encode(&e)
+}
+
+// record adds a packet to the client's capture, if it has one.
+func (c *client) record(dir Direction, packet []byte) {
+	if err := c.capture.record(dir, packet); err != nil {
+		c.log.Errorf("trouble writing packet capture: %v", err)
+	}
+}
+
+// CaptureReader reads back the entries written by WithCapture.
+type CaptureReader struct {
+	dec *json.Decoder
+}
+
+func NewCaptureReader(r io.Reader) *CaptureReader {
+	return &CaptureReader{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next entry in the capture, or io.EOF when there are no more.
+func (r *CaptureReader) Next() (*CaptureEntry, error) {
+	var e CaptureEntry
+	if err := r.dec.Decode(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
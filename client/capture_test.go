@@ -0,0 +1,66 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureRecordsEveryPacket(t *testing.T) {
+	here, there := net.Pipe()
+	a := &testAgent{t: t, conn: there}
+	go a.serve(func(p packet, reply func(uint16, []byte)) {
+		a.event(0x40000000, []byte{0, 0, 0, 0, 0})
+		reply(uint16(ErrVmDead.(JdwpError).Code), nil)
+	})
+	var capture bytes.Buffer
+	c, err := New(here, WithCapture(&capture))
+	if !assert.Nil(t, err) {
+		return
+	}
+	r, err := c.Call(VirtualMachine, VirtualMachineVersion, []byte{1, 2})
+	assert.Nil(t, err)
+	<-c.Events()
+	c.Close()
+
+	entries := []*CaptureEntry{}
+	cr := NewCaptureReader(&capture)
+	for {
+		e, err := cr.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.Nil(t, err) {
+			return
+		}
+		entries = append(entries, e)
+	}
+	if !assert.Len(t, entries, 5) {
+		return
+	}
+	idSizes, version, event, reply := entries[0], entries[2], entries[3], entries[4]
+
+	assert.Equal(t, Outgoing, idSizes.Direction)
+	assert.Equal(t, VirtualMachine, idSizes.CommandSet)
+	assert.Equal(t, VirtualMachineIDSizes, idSizes.Command)
+	assert.Equal(t, Incoming, entries[1].Direction)
+	assert.Equal(t, idSizes.Id, entries[1].Id)
+
+	assert.Equal(t, Outgoing, version.Direction)
+	assert.Equal(t, []byte{0, 0, 0, 13, 0, 0, 0, 2, 0, 1, 1, 1, 2}, version.Packet)
+	assert.Equal(t, []byte{1, 2}, version.Data())
+
+	assert.Equal(t, Incoming, event.Direction)
+	assert.False(t, event.IsReply())
+	assert.Equal(t, EventCommandSet, event.CommandSet)
+	assert.Equal(t, CompositeCommands, event.Command)
+
+	assert.Equal(t, Incoming, reply.Direction)
+	assert.True(t, reply.IsReply())
+	assert.Equal(t, r.Id, reply.Id)
+	assert.Equal(t, uint16(112), reply.ErrCode)
+	assert.False(t, reply.Time.Before(version.Time))
+}
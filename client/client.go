@@ -1,22 +1,30 @@
 package client
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
-	"net"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/sirupsen/logrus"
 )
 
 type Client interface {
 	io.Closer
 	Events() <-chan *Event
+	Commands() <-chan *VMCommand
 	Send(CommandSet, Command, []byte) (Id, <-chan *Reply, error)
+	SendContext(context.Context, CommandSet, Command, []byte) (Id, <-chan *Reply, error)
 	Dispose(Id)
 	Call(CommandSet, Command, []byte) (*Reply, error)
+	CallContext(context.Context, CommandSet, Command, []byte) (*Reply, error)
+	IDSizes() IDSizes
+	// Done is closed once the connection has ended, whether through Close or because it dropped.
+	Done() <-chan struct{}
+	// Err is nil until Done is closed; after that it is a *DisconnectedError saying why.
+	Err() error
 }
 
 type CommandSet uint8
@@ -24,79 +32,259 @@ type Command uint8
 type Id uint32
 
 type client struct {
-	conn      net.Conn
-	close     chan struct{}
-	wg        sync.WaitGroup
-	e         chan *Event
-	id        Id
-	responses sync.Map
+	conn             io.ReadWriteCloser
+	handshakeTimeout time.Duration
+	close            chan struct{}
+	closeOnce        sync.Once
+	done             chan struct{}
+	doneOnce         sync.Once
+	err              error
+	wg               sync.WaitGroup
+	e                chan *Event
+	events           *packetQueue
+	cmds             chan *VMCommand
+	commands         *packetQueue
+	queueSize        int
+	overflow         OverflowPolicy
+	diag             Diagnostics
+	log              Logger
+	observers        observers
+	policy           Policy
+	maxPacket        uint32
+	watch            *watchdog
+	reg              *Registry
+	capture          *capture
+	id               uint32 // accessed atomically
+	writing          sync.Mutex
+	responses        sync.Map // Id to *awaiting
+	sizes            IDSizes
 }
 
 var _ Client = &client{}
 
-func Dial(network string, address string) (Client, error) {
-	conn, err := net.Dial(network, address)
-	if err != nil {
-		return nil, err
+var (
+	// ErrDisconnected matches, using errors.Is, every *DisconnectedError.
+	ErrDisconnected = errors.New("jdwp connection ended")
+	// ErrClosed is the Cause of a disconnection brought about by calling Close.
+	ErrClosed = errors.New("jdwp client closed")
+)
+
+// DisconnectedError is returned by every call in flight, or attempted, once the connection
+// has ended; and by Err.
+type DisconnectedError struct {
+	Cause error // ErrClosed, or whatever error ended the connection
+}
+
+func (e *DisconnectedError) Error() string {
+	return fmt.Sprintf("jdwp connection ended: %v", e.Cause)
+}
+
+func (e *DisconnectedError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *DisconnectedError) Is(target error) bool {
+	return target == ErrDisconnected
+}
+
+// Option configures a Client as it is constructed.
+type Option func(*client)
+
+// WithEventQueue bounds the number of events held for a slow consumer of Events, and sets
+// what happens to further events once that many are waiting. The same bound applies
+// separately to Commands. The default is DefaultEventQueueSize, dropping the oldest.
+func WithEventQueue(size int, policy OverflowPolicy) Option {
+	return func(c *client) {
+		c.queueSize = size
+		c.overflow = policy
 	}
-	return New(conn)
 }
 
-func DialTimeout(network string, address string, timeout time.Duration) (Client, error) {
-	conn, err := net.DialTimeout(network, address, timeout)
-	if err != nil {
-		return nil, err
+// Diagnostics is told about packets that the client receives but cannot route.
+type Diagnostics interface {
+	// OrphanReply is called with a reply that no caller is waiting for: one that arrives
+	// after its command was disposed of, a duplicate, or one matching no command at all.
+	OrphanReply(*Reply)
+}
+
+// WithDiagnostics replaces the default Diagnostics, which logs a warning to the client's Logger.
+func WithDiagnostics(d Diagnostics) Option {
+	return func(c *client) {
+		c.diag = d
 	}
-	return New(conn)
 }
 
-func New(conn net.Conn) (Client, error) {
+type logDiagnostics struct {
+	log Logger
+}
+
+func (d logDiagnostics) OrphanReply(r *Reply) {
+	d.log.Warnf("jdwp reply received for no pending command: %+v", *r)
+}
+
+func Dial(network string, address string, opts ...Option) (Client, error) {
+	return Open(&NetTransport{Network: network, Address: address}, opts...)
+}
+
+func DialTimeout(network string, address string, timeout time.Duration, opts ...Option) (Client, error) {
+	return Open(&NetTransport{Network: network, Address: address, Timeout: timeout}, opts...)
+}
+
+// New runs the protocol over conn, which is typically a net.Conn but may be any stream. Where
+// the stream supports deadlines, as a net.Conn does, they are used to bound the handshake and to
+// abandon cancelled writes; otherwise, the stream is closed to the same effect.
+func New(conn io.ReadWriteCloser, opts ...Option) (Client, error) {
 	c := &client{
-		conn:  conn,
-		close: make(chan struct{}),
-		e:     make(chan *Event),
+		conn:             conn,
+		handshakeTimeout: DefaultHandshakeTimeout,
+		close:            make(chan struct{}),
+		done:             make(chan struct{}),
+		e:                make(chan *Event),
+		cmds:             make(chan *VMCommand),
+		queueSize:        DefaultEventQueueSize,
+		overflow:         DropOldest,
+		log:              DefaultLogger,
+		maxPacket:        DefaultMaxPacketSize,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	if c.diag == nil {
+		c.diag = logDiagnostics{c.log}
+	}
+	c.events = newPacketQueue("events", c.queueSize, c.overflow, c.log)
+	c.commands = newPacketQueue("commands", c.queueSize, c.overflow, c.log)
 	err := c.Handshake()
 	if err != nil {
 		c.Close()
 		return nil, err
 	}
-	c.wg.Add(1)
+	// Replies are delivered by the reader; events are queued and delivered separately, so
+	// that a consumer still busy with one event can Call the VM without stalling the reader.
+	c.wg.Add(3)
 	go c.read()
+	go func() {
+		defer c.wg.Done()
+		defer close(c.e)
+		c.events.deliver(c.close, func(e interface{}) bool {
+			select {
+			case c.e <- e.(*Event):
+				return true
+			case <-c.close:
+				return false
+			}
+		})
+	}()
+	go func() {
+		defer c.wg.Done()
+		defer close(c.cmds)
+		c.commands.deliver(c.close, func(cmd interface{}) bool {
+			select {
+			case c.cmds <- cmd.(*VMCommand):
+				return true
+			case <-c.close:
+				return false
+			}
+		})
+	}()
+	if err := c.negotiateIDSizes(); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if c.watch != nil {
+		c.wg.Add(1)
+		go c.watch.run()
+	}
 	return c, nil
 }
 
-func (c *client) Close() error {
-	close(c.close)
-	err := c.conn.Close()
-	c.wg.Wait()
-	return err
-}
-
-const Handshake = "JDWP-Handshake"
-
-func (c *client) Handshake() error {
-	handshake := []byte(Handshake)
-	n, err := c.conn.Write(handshake)
+// negotiateIDSizes asks the VM how wide its IDs are; every later command depends on the answer.
+func (c *client) negotiateIDSizes() error {
+	r, err := c.Call(VirtualMachine, VirtualMachineIDSizes, []byte{})
 	if err != nil {
 		return err
-	} else if n != len(handshake) {
-		return fmt.Errorf("insufficient bytes of handshake written: %d/%d", n, len(handshake))
 	}
-	// Read the response
-	resp := make([]byte, len(handshake))
-	n, err = io.ReadFull(c.conn, resp)
-	if err != nil {
+	if r.ErrCode != 0 {
+		return lookupError(r.ErrCode)
+	}
+	var sizes IDSizes
+	if err := Parse(r.Data, &sizes); err != nil {
 		return err
-	} else if n != len(handshake) {
-		return fmt.Errorf("insufficient bytes of handshake written: %d/%d", n, len(handshake))
 	}
-	if string(resp[:n]) != Handshake {
-		return fmt.Errorf("insufficient bytes of handshake written: %d/%d", n, len(handshake))
+	if err := sizes.validate(); err != nil {
+		return err
 	}
+	c.sizes = sizes
 	return nil
 }
 
+func (c *client) IDSizes() IDSizes {
+	return c.sizes
+}
+
+// Close shuts down the connection. Any callers still waiting on a reply are woken:
+// Call fails with a *DisconnectedError whose Cause is ErrClosed, and the channels handed
+// out by Send are closed. Events not yet consumed are discarded. With a suspend budget, the
+// VM is first resumed and the client's event requests cleared.
+func (c *client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		if c.watch != nil && c.sizes.ObjectIDSize != 0 {
+			c.watch.halt()
+			if c.Err() == nil {
+				c.watch.releaseAll()
+			}
+		}
+		err = c.terminate(ErrClosed)
+		close(c.close)
+		c.wg.Wait()
+	})
+	return err
+}
+
+// terminate ends the connection, recording the first reason given for doing so. It returns
+// the error from closing the underlying connection, if this call was the one to close it.
+func (c *client) terminate(cause error) error {
+	var err error
+	c.doneOnce.Do(func() {
+		c.err = &DisconnectedError{Cause: cause}
+		close(c.done)
+		err = c.conn.Close()
+	})
+	return err
+}
+
+func (c *client) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *client) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// failPending closes the channel of every command still awaiting a reply. Only the reader
+// sends on those channels, so this is called as it exits.
+func (c *client) failPending() {
+	c.responses.Range(func(id, p interface{}) bool {
+		c.responses.Delete(id)
+		p.(*awaiting).fail()
+		return true
+	})
+}
+
+// abandon fails the command id, which will not be answered because the connection has ended.
+func (c *client) abandon(id Id) {
+	if p, ok := c.responses.Load(id); ok {
+		c.responses.Delete(id)
+		p.(*awaiting).fail()
+	}
+}
+
 type command struct {
 	Header
 	commandSet CommandSet
@@ -111,12 +299,27 @@ type Event struct {
 	Data    []byte
 }
 
+// VMCommand is a command sent by the VM in a command set other than EventCommandSet.
+type VMCommand struct {
+	Header
+	Set     CommandSet
+	Command Command
+	Data    []byte
+}
+
 type Header struct {
 	Length uint32
 	Id     Id
 	Flags  uint8
 }
 
+// FlagReply marks a packet as a reply; without it, the packet is a command.
+const FlagReply = uint8(0x80)
+
+func (h Header) IsReply() bool {
+	return h.Flags&FlagReply != 0
+}
+
 type Reply struct {
 	Header
 	ErrCode uint16
@@ -125,55 +328,89 @@ type Reply struct {
 
 const HeaderLength = 11
 
-const (
-	EventCommandSet   = CommandSet(64)
-	CompositeCommands = Command(100)
-)
+// CompositeCommands is the name by which Event.Composite was known before it was generated.
+const CompositeCommands = EventComposite
 
 func (c *client) read() {
 	defer c.wg.Done()
-	defer close(c.e)
+	defer c.failPending()
+	defer c.events.end()
+	defer c.commands.end()
 	for {
-		select {
-		case <-c.close:
-			logrus.Debug("jdwp client reader exits")
+		header, pair, data, err := readPacket(c.conn, c.maxPacket)
+		if err != nil {
+			c.log.Debugf("jdwp client encountered error during read: %v", err)
+			c.terminate(err)
+			c.events.push(vmDisconnected())
 			return
-		default:
-			logrus.Debug("jdwp client waiting for read")
-			var header Header
-			err := readBytes(nil, c.conn, &header.Length)
-			err = readBytes(err, c.conn, &header.Id)
-			err = readBytes(err, c.conn, &header.Flags)
-			var pair uint16
-			err = readBytes(err, c.conn, &pair)
-			if err != nil {
-				logrus.WithError(err).Debug("jdwp client encountered error during read")
+		}
+		if c.capture != nil {
+			packet := make([]byte, HeaderLength, int(header.Length))
+			binary.BigEndian.PutUint32(packet[0:], header.Length)
+			binary.BigEndian.PutUint32(packet[4:], uint32(header.Id))
+			packet[8] = header.Flags
+			binary.BigEndian.PutUint16(packet[9:], pair)
+			c.record(Incoming, append(packet, data...))
+		}
+		c.route(header, pair, data)
+	}
+}
+
+// vmDisconnected synthesises the Composite event that tells consumers of Events the connection has gone.
+func vmDisconnected() *Event {
+	data := Seq().Octet(uint8(SuspendPolicyNone)).Int(1).Octet(uint8(EventKindVM_DISCONNECTED)).Marshal()
+	return &Event{
+		Header:  Header{Length: uint32(HeaderLength + len(data))},
+		Set:     EventCommandSet,
+		Command: CompositeCommands,
+		Data:    data,
+	}
+}
+
+// route classifies a packet by its reply flag. Replies go straight to whoever is waiting on
+// them; commands from the VM are queued for Events or Commands.
+func (c *client) route(header Header, pair uint16, data []byte) {
+	if header.IsReply() {
+		reply := Reply{Header: header, ErrCode: pair, Data: data}
+		c.observers.received(PacketInfo{Header: header, ErrCode: pair})
+		c.log.Debugf("jdwp read reply: %+v", reply)
+		if c.watch != nil {
+			c.watch.replied(&reply)
+		}
+		if p, ok := c.responses.Load(header.Id); ok {
+			p := p.(*awaiting)
+			select {
+			case p.ch <- &reply:
+				c.observers.replied(p.set, p.cmd, reply.ErrCode, time.Since(p.sent))
 				return
-			}
-			data := make([]byte, header.Length-HeaderLength)
-			_, err = io.ReadFull(c.conn, data)
-			if ch, ok := c.responses.Load(header.Id); ok {
-				reply := Reply{Header: header, ErrCode: pair, Data: data}
-				logrus.WithField("reply", reply).Debug("jdwp read")
-				ch.(chan *Reply) <- &reply
-			} else {
-				event := Event{Header: header, Set: CommandSet(pair >> 8), Command: Command(pair & 0xff), Data: data}
-				logrus.WithField("event", event).Debug("jdwp read")
-				c.e <- &event
+			default:
 			}
 		}
+		c.diag.OrphanReply(&reply)
+		return
 	}
+	set, cmd := CommandSet(pair>>8), Command(pair&0xff)
+	c.observers.received(PacketInfo{Header: header, Set: set, Command: cmd})
+	if set == EventCommandSet {
+		event := Event{Header: header, Set: set, Command: cmd, Data: data}
+		c.log.Debugf("jdwp read event: %+v", event)
+		if c.watch != nil && cmd == CompositeCommands {
+			c.watch.event(data)
+		}
+		c.events.push(&event)
+		return
+	}
+	command := VMCommand{Header: header, Set: set, Command: cmd, Data: data}
+	c.log.Debugf("jdwp read command: %+v", command)
+	c.commands.push(&command)
 }
 
 func (c *client) Events() <-chan *Event {
 	return c.e
 }
 
-func readBytes(err error, conn net.Conn, data interface{}) error {
-	if err != nil {
-		return err
-	}
-	return binary.Read(conn, binary.BigEndian, data)
+func (c *client) Commands() <-chan *VMCommand {
+	return c.cmds
 }
 
 func writeBytes(err error, out io.Writer, data interface{}) error {
@@ -198,21 +435,110 @@ func writeString(err error, out io.Writer, s string) error {
 }
 
 func (c *client) Send(set CommandSet, cmd Command, data []byte) (Id, <-chan *Reply, error) {
-	c.id++
+	return c.SendContext(context.Background(), set, cmd, data)
+}
+
+// SendContext writes a command to the VM. If ctx is cancelled while the write is blocked,
+// the write is abandoned; since the packet framing is then lost, the connection is closed.
+func (c *client) SendContext(ctx context.Context, set CommandSet, cmd Command, data []byte) (Id, <-chan *Reply, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+	if err := c.Err(); err != nil {
+		return 0, nil, err
+	}
+	if c.policy != nil {
+		if err := c.policy.Permit(set, cmd, data); err != nil {
+			return 0, nil, err
+		}
+	}
+	id := Id(atomic.AddUint32(&c.id, 1))
 	replyOn := make(chan *Reply, 1)
-	c.responses.Store(c.id, replyOn)
-	logrus.Debug("sending", set, cmd, c.id, data)
-	err := writeBytes(nil, c.conn, uint32(len(data)+HeaderLength))
-	err = writeBytes(err, c.conn, c.id)
-	err = writeBytes(err, c.conn, uint8(0))
-	err = writeBytes(err, c.conn, set)
-	err = writeBytes(err, c.conn, cmd)
-	for err == nil && len(data) > 0 {
-		var n int
-		n, err = c.conn.Write(data)
-		data = data[n:]
+	c.responses.Store(id, &awaiting{ch: replyOn, set: set, cmd: cmd, sent: time.Now()})
+	if err := c.Err(); err != nil {
+		// failPending may already have been and gone
+		c.abandon(id)
+		return 0, nil, err
+	}
+	c.log.Debugf("jdwp sending %d.%d id %d: % x", set, cmd, id, data)
+	if c.watch != nil {
+		c.watch.sending(id, set, cmd, data)
+	}
+
+	packet := make([]byte, HeaderLength, HeaderLength+len(data))
+	binary.BigEndian.PutUint32(packet[0:], uint32(HeaderLength+len(data)))
+	binary.BigEndian.PutUint32(packet[4:], uint32(id))
+	packet[8] = 0
+	packet[9] = uint8(set)
+	packet[10] = uint8(cmd)
+	packet = append(packet, data...)
+
+	// Each packet goes out in one piece, so concurrent senders cannot interleave on the wire
+	c.writing.Lock()
+	c.record(Outgoing, packet)
+	c.observers.sent(PacketInfo{
+		Header:  Header{Length: uint32(len(packet)), Id: id},
+		Set:     set,
+		Command: cmd,
+	})
+	done := c.interruptWrite(ctx)
+	_, err := c.conn.Write(packet)
+	done()
+	c.writing.Unlock()
+	if err != nil {
+		c.terminate(err)
+		c.abandon(id)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		} else {
+			err = c.Err()
+		}
 	}
-	return c.id, replyOn, err
+	return id, replyOn, err
+}
+
+// interruptWrite arranges for a blocked write to fail as soon as ctx is done. The returned
+// function must be called once the write has finished.
+func (c *client) interruptWrite(ctx context.Context) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			if d, ok := c.conn.(writeDeadliner); ok {
+				d.SetWriteDeadline(time.Unix(1, 0))
+			} else {
+				c.terminate(ctx.Err())
+			}
+		case <-stop:
+		}
+	}()
+	return func() {
+		close(stop)
+		<-stopped
+		if d, ok := c.conn.(writeDeadliner); ok && ctx.Err() != nil {
+			d.SetWriteDeadline(time.Time{})
+		}
+	}
+}
+
+// awaiting is a command awaiting its reply.
+type awaiting struct {
+	ch     chan *Reply
+	set    CommandSet
+	cmd    Command
+	sent   time.Time
+	failed sync.Once
+}
+
+// fail closes the channel without a reply. Both failPending and abandon may get there.
+func (a *awaiting) fail() {
+	a.failed.Do(func() {
+		close(a.ch)
+	})
 }
 
 func (c *client) Dispose(id Id) {
@@ -220,10 +546,63 @@ func (c *client) Dispose(id Id) {
 }
 
 func (c *client) Call(set CommandSet, cmd Command, data []byte) (*Reply, error) {
-	id, ch, err := c.Send(set, cmd, data)
+	return c.CallContext(context.Background(), set, cmd, data)
+}
+
+// call sends the command whose body is built by s, turning a failure to build it or an error
+// code in the reply into an error.
+func call(c Client, set CommandSet, cmd Command, s S) (*Reply, error) {
+	return callContext(context.Background(), c, set, cmd, s)
+}
+
+func callContext(ctx context.Context, c Client, set CommandSet, cmd Command, s S) (*Reply, error) {
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	r, err := c.CallContext(ctx, set, cmd, s.Marshal())
+	if err != nil {
+		return nil, err
+	}
+	if r.ErrCode != 0 {
+		return nil, lookupError(r.ErrCode)
+	}
+	return r, nil
+}
+
+// request sends out, laid out as for Marshal, and decodes the reply into reply unless it is nil.
+func request(c Client, set CommandSet, cmd Command, out interface{}, reply interface{}) error {
+	r, err := call(c, set, cmd, c.IDSizes().Seq().Value(out))
+	if err != nil || reply == nil {
+		return err
+	}
+	return Decode(c, r.Data, reply)
+}
+
+// CallContext sends a command and waits for its reply. If ctx is cancelled or expires first,
+// the pending reply slot is dropped and ctx.Err() is returned.
+func (c *client) CallContext(ctx context.Context, set CommandSet, cmd Command, data []byte) (*Reply, error) {
+	id, ch, err := c.SendContext(ctx, set, cmd, data)
 	defer c.Dispose(id)
 	if err != nil {
 		return nil, err
 	}
-	return <-ch, nil
+	select {
+	case r, ok := <-ch:
+		if !ok {
+			return nil, c.Err()
+		}
+		return r, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		// A reply that arrived before the connection ended still counts
+		select {
+		case r, ok := <-ch:
+			if ok {
+				return r, nil
+			}
+		default:
+		}
+		return nil, c.Err()
+	}
 }
@@ -0,0 +1,391 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type packet struct {
+	Header
+	Set     CommandSet
+	Command Command
+	Data    []byte
+}
+
+// testAgent plays the VM end of a connection.
+type testAgent struct {
+	t       *testing.T
+	conn    net.Conn
+	writing sync.Mutex
+}
+
+// agent plays the VM end of conn: it answers the handshake and IDSizes, then passes every
+// other command to handle along with a function that sends the reply.
+func agent(t *testing.T, conn net.Conn, handle func(p packet, reply func(errCode uint16, data []byte))) {
+	(&testAgent{t: t, conn: conn}).serve(handle)
+}
+
+func (a *testAgent) serve(handle func(p packet, reply func(errCode uint16, data []byte))) {
+	hs := make([]byte, len(Handshake))
+	if _, err := io.ReadFull(a.conn, hs); err != nil || string(hs) != Handshake {
+		a.t.Errorf("agent: bad handshake %q: %v", hs, err)
+		return
+	}
+	if _, err := a.conn.Write(hs); err != nil {
+		a.t.Errorf("agent: writing handshake: %v", err)
+		return
+	}
+	for {
+		var hdr [HeaderLength]byte
+		if _, err := io.ReadFull(a.conn, hdr[:]); err != nil {
+			return
+		}
+		p := packet{
+			Header: Header{
+				Length: binary.BigEndian.Uint32(hdr[0:]),
+				Id:     Id(binary.BigEndian.Uint32(hdr[4:])),
+				Flags:  hdr[8],
+			},
+			Set:     CommandSet(hdr[9]),
+			Command: Command(hdr[10]),
+		}
+		p.Data = make([]byte, p.Length-HeaderLength)
+		if _, err := io.ReadFull(a.conn, p.Data); err != nil {
+			return
+		}
+		reply := func(errCode uint16, data []byte) {
+			a.write(p.Id, 0x80, errCode, data)
+		}
+		if p.Set == VirtualMachine && p.Command == VirtualMachineIDSizes {
+			reply(0, Seq().Int(8).Int(8).Int(8).Int(8).Int(8).Marshal())
+			continue
+		}
+		handle(p, reply)
+	}
+}
+
+func (a *testAgent) write(id Id, flags uint8, pair uint16, data []byte) {
+	out := make([]byte, HeaderLength, HeaderLength+len(data))
+	binary.BigEndian.PutUint32(out[0:], uint32(HeaderLength+len(data)))
+	binary.BigEndian.PutUint32(out[4:], uint32(id))
+	out[8] = flags
+	binary.BigEndian.PutUint16(out[9:], pair)
+	a.writing.Lock()
+	defer a.writing.Unlock()
+	a.conn.Write(append(out, data...))
+}
+
+// event sends a Composite event command carrying data.
+func (a *testAgent) event(id Id, data []byte) {
+	a.write(id, 0, uint16(EventCommandSet)<<8|uint16(CompositeCommands), data)
+}
+
+// silentVM returns a client attached to an agent that never answers anything but IDSizes.
+func silentVM(t *testing.T) *client {
+	here, there := net.Pipe()
+	go agent(t, there, func(packet, func(uint16, []byte)) {})
+	c, err := New(here)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*client)
+}
+
+func pending(c *client) int {
+	n := 0
+	c.responses.Range(func(interface{}, interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func TestCallContextDeadline(t *testing.T) {
+	c := silentVM(t)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r, err := c.CallContext(ctx, VirtualMachine, VirtualMachineVersion, []byte{})
+	assert.Nil(t, r)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 0, pending(c))
+}
+
+func TestCallContextAlreadyCancelled(t *testing.T) {
+	c := silentVM(t)
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.CallContext(ctx, VirtualMachine, VirtualMachineVersion, []byte{})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, pending(c))
+}
+
+func TestCloseWakesPendingCalls(t *testing.T) {
+	c := silentVM(t)
+
+	_, sent, err := c.Send(VirtualMachine, VirtualMachineVersion, []byte{})
+	assert.Nil(t, err)
+	errs := make(chan error)
+	go func() {
+		_, err := c.Call(VirtualMachine, VirtualMachineVersion, []byte{})
+		errs <- err
+	}()
+	for pending(c) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Close()
+	err = <-errs
+	assert.True(t, errors.Is(err, ErrDisconnected))
+	assert.True(t, errors.Is(err, ErrClosed))
+	_, ok := <-sent
+	assert.False(t, ok)
+}
+
+func TestReplyBeforeDisconnect(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		here, there := net.Pipe()
+		// The reply is sent, and the connection dropped straight after
+		go agent(t, there, func(p packet, reply func(uint16, []byte)) {
+			reply(0, []byte{7})
+			there.Close()
+		})
+		c, err := New(here)
+		if !assert.Nil(t, err) {
+			return
+		}
+		r, err := c.Call(VirtualMachine, VirtualMachineVersion, []byte{})
+		if assert.Nil(t, err) {
+			assert.Equal(t, []byte{7}, r.Data)
+		}
+		c.Close()
+	}
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	here, there := net.Pipe()
+	// Reply to each command after a random delay, so that replies arrive out of order
+	go agent(t, there, func(p packet, reply func(uint16, []byte)) {
+		delay := time.Duration(rand.Intn(500)) * time.Microsecond
+		go func() {
+			time.Sleep(delay)
+			reply(0, p.Data)
+		}()
+	})
+	c, err := New(here)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer c.Close()
+
+	const callers, calls = 50, 20
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < calls; j++ {
+				body := Seq().Int(i).Int(j).Marshal()
+				r, err := c.Call(VirtualMachine, VirtualMachineVersion, body)
+				if !assert.Nil(t, err) {
+					return
+				}
+				assert.Equal(t, body, r.Data)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 0, pending(c.(*client)))
+}
+
+func TestConcurrentSendsUseDistinctIds(t *testing.T) {
+	c := silentVM(t)
+	defer c.Close()
+
+	const senders = 40
+	ids := make(chan Id, senders)
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, _, err := c.Send(VirtualMachine, VirtualMachineVersion, []byte{})
+			assert.Nil(t, err)
+			ids <- id
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := map[Id]bool{}
+	for id := range ids {
+		assert.False(t, seen[id], "id %d reused", id)
+		seen[id] = true
+	}
+	assert.Equal(t, senders, pending(c))
+}
+
+func TestEventHandlerCanCallVM(t *testing.T) {
+	here, there := net.Pipe()
+	a := &testAgent{t: t, conn: there}
+	go a.serve(func(p packet, reply func(uint16, []byte)) {
+		reply(0, p.Data)
+	})
+	c, err := New(here)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer c.Close()
+
+	const events = 5
+	go func() {
+		for i := 0; i < events; i++ {
+			a.event(Id(0x40000000+i), []byte{0, 0, 0, 0, 0})
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < events; i++ {
+			<-c.Events()
+			// Still holding this event, call back into the VM while more events arrive
+			_, err := c.Call(VirtualMachine, VirtualMachineVersion, []byte{})
+			assert.Nil(t, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event handler calling the VM deadlocked")
+	}
+}
+
+type orphans chan *Reply
+
+func (o orphans) OrphanReply(r *Reply) {
+	o <- r
+}
+
+func TestPacketsClassifiedByReplyFlag(t *testing.T) {
+	here, there := net.Pipe()
+	a := &testAgent{t: t, conn: there}
+	held := make(chan func(uint16, []byte), 1)
+	go a.serve(func(p packet, reply func(uint16, []byte)) {
+		held <- reply
+	})
+	o := make(orphans, 1)
+	c, err := New(here, WithDiagnostics(o))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer c.Close()
+
+	id, _, err := c.Send(VirtualMachine, VirtualMachineVersion, []byte{})
+	assert.Nil(t, err)
+	reply := <-held
+
+	// A command from the VM that happens to share an ID with a pending command is still an event
+	a.event(id, []byte{1, 2, 3})
+	e := <-c.Events()
+	assert.Equal(t, id, e.Id)
+	assert.Equal(t, []byte{1, 2, 3}, e.Data)
+
+	// Commands outside the Event set are surfaced separately
+	a.write(7, 0, 199<<8|1, []byte{4})
+	cmd := <-c.Commands()
+	assert.Equal(t, CommandSet(199), cmd.Set)
+	assert.Equal(t, Command(1), cmd.Command)
+	assert.Equal(t, []byte{4}, cmd.Data)
+
+	// A reply that arrives after its command was given up on is an orphan, not an event
+	c.Dispose(id)
+	reply(0, []byte{5})
+	orphan := <-o
+	assert.Equal(t, id, orphan.Id)
+	assert.True(t, orphan.IsReply())
+	assert.Equal(t, []byte{5}, orphan.Data)
+}
+
+func TestDisconnectFailsPendingCalls(t *testing.T) {
+	here, there := net.Pipe()
+	go agent(t, there, func(p packet, reply func(uint16, []byte)) {
+		there.Close()
+	})
+	c, err := New(here)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer c.Close()
+	assert.Nil(t, c.Err())
+
+	_, err = c.Call(VirtualMachine, VirtualMachineVersion, []byte{})
+	var disconnected *DisconnectedError
+	assert.True(t, errors.As(err, &disconnected))
+	assert.Equal(t, io.EOF, disconnected.Cause)
+	<-c.Done()
+	assert.Equal(t, err, c.Err())
+
+	// Further calls fail straight away
+	_, err = c.Call(VirtualMachine, VirtualMachineVersion, []byte{})
+	assert.True(t, errors.Is(err, ErrDisconnected))
+
+	// The last event says why the stream is ending
+	e, ok := <-c.Events()
+	if assert.True(t, ok) {
+		var comp Composite
+		assert.Nil(t, Parse(e.Data, &comp))
+		assert.Equal(t, []VMEvent{&EventVMDisconnected{}}, comp.Events)
+	}
+	_, ok = <-c.Events()
+	assert.False(t, ok)
+}
+
+// closingPolicy closes its client while a command is being sent, once it has been given one.
+type closingPolicy struct {
+	c *client
+}
+
+func (p *closingPolicy) Permit(CommandSet, Command, []byte) error {
+	if p.c != nil {
+		p.c.Close()
+		p.c.wg.Wait()
+	}
+	return nil
+}
+
+func TestSendRacingClose(t *testing.T) {
+	here, there := net.Pipe()
+	go agent(t, there, func(packet, func(uint16, []byte)) {})
+	policy := &closingPolicy{}
+	cl, err := New(here, WithPolicy(policy))
+	if !assert.Nil(t, err) {
+		return
+	}
+	c := cl.(*client)
+	policy.c = c
+
+	// The connection has gone, and every pending command failed, just before this is sent
+	_, ch, err := c.Send(VirtualMachine, VirtualMachineVersion, []byte{})
+	assert.True(t, errors.Is(err, ErrDisconnected))
+	if ch != nil {
+		select {
+		case _, ok := <-ch:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Error("the channel of an unsent command was never closed")
+		}
+	}
+	assert.Equal(t, 0, pending(c))
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// EventField is one of the values making up an event on the wire, by which an event can be
+// passed over without being decoded.
+type EventField uint8
+
+const (
+	EventFieldThread        EventField = iota // threadID
+	EventFieldLocation                        // location
+	EventFieldTaggedObject                    // tagged-objectID
+	EventFieldValue                           // value, tagged with its type
+	EventFieldByte                            // byte or boolean
+	EventFieldInt                             // int
+	EventFieldLong                            // long
+	EventFieldReferenceType                   // referenceTypeID
+	EventFieldField                           // fieldID
+	EventFieldString                          // string
+)
+
+// eventLayouts are the fields of each kind of event, following its kind and request ID. They
+// are written by hand, and checked by a test against the generated EventComposite types.
+var eventLayouts = map[EventKind][]EventField{
+	EventKindVM_START:                      {EventFieldThread},
+	EventKindSINGLE_STEP:                   {EventFieldThread, EventFieldLocation},
+	EventKindBreakpoint:                    {EventFieldThread, EventFieldLocation},
+	EventKindMETHOD_ENTRY:                  {EventFieldThread, EventFieldLocation},
+	EventKindMETHOD_EXIT:                   {EventFieldThread, EventFieldLocation},
+	EventKindMETHOD_EXIT_WITH_RETURN_VALUE: {EventFieldThread, EventFieldLocation, EventFieldValue},
+	EventKindMONITOR_CONTENDED_ENTER:       {EventFieldThread, EventFieldTaggedObject, EventFieldLocation},
+	EventKindMONITOR_CONTENDED_ENTERED:     {EventFieldThread, EventFieldTaggedObject, EventFieldLocation},
+	EventKindMONITOR_WAIT:                  {EventFieldThread, EventFieldTaggedObject, EventFieldLocation, EventFieldLong},
+	EventKindMONITOR_WAITED:                {EventFieldThread, EventFieldTaggedObject, EventFieldLocation, EventFieldByte},
+	EventKindEXCEPTION:                     {EventFieldThread, EventFieldLocation, EventFieldTaggedObject, EventFieldLocation},
+	EventKindTHREAD_START:                  {EventFieldThread},
+	EventKindTHREAD_DEATH:                  {EventFieldThread},
+	EventKindCLASS_PREPARE:                 {EventFieldThread, EventFieldByte, EventFieldReferenceType, EventFieldString, EventFieldInt},
+	EventKindCLASS_UNLOAD:                  {EventFieldString},
+	EventKindFIELD_ACCESS:                  {EventFieldThread, EventFieldLocation, EventFieldByte, EventFieldReferenceType, EventFieldField, EventFieldTaggedObject},
+	EventKindFIELD_MODIFICATION:            {EventFieldThread, EventFieldLocation, EventFieldByte, EventFieldReferenceType, EventFieldField, EventFieldTaggedObject, EventFieldValue},
+	EventKindVM_DEATH:                      {},
+	EventKindSUSPENSION_EXPIRED:            {EventFieldThread, EventFieldLong},
+}
+
+// rawEvent is one event of a Composite, left undecoded.
+type rawEvent struct {
+	Kind      EventKind
+	RequestId int
+	Data      []byte // the whole event, from its kind onwards
+}
+
+// splitComposite divides the body of a Composite command into its events, using only their
+// layouts, so that they can be passed on without being decoded.
+func (r *Registry) splitComposite(data []byte, sizes IDSizes) (SuspendPolicy, []rawEvent, error) {
+	if len(data) < 5 {
+		return 0, nil, fmt.Errorf("composite of %d bytes is too short", len(data))
+	}
+	policy := SuspendPolicy(data[0])
+	count := int(int32(binary.BigEndian.Uint32(data[1:])))
+	if count < 0 || count > len(data) {
+		return 0, nil, fmt.Errorf("composite claims %d events", count)
+	}
+	events := make([]rawEvent, 0, count)
+	at := 5
+	for i := 0; i < count; i++ {
+		if at+5 > len(data) {
+			return 0, nil, fmt.Errorf("composite event %d is truncated", i)
+		}
+		kind := EventKind(data[at])
+		et, ok := r.event(kind)
+		if !ok {
+			return 0, nil, fmt.Errorf("composite event %d has unknown kind %d", i, kind)
+		}
+		end := at + 5
+		for _, f := range et.layout {
+			if end > len(data) {
+				return 0, nil, fmt.Errorf("composite event %d is truncated", i)
+			}
+			n, err := f.size(data[end:], sizes)
+			if err != nil {
+				return 0, nil, fmt.Errorf("composite event %d: %w", i, err)
+			}
+			end += n
+		}
+		if end > len(data) {
+			return 0, nil, fmt.Errorf("composite event %d is truncated", i)
+		}
+		events = append(events, rawEvent{
+			Kind:      kind,
+			RequestId: int(int32(binary.BigEndian.Uint32(data[at+1:]))),
+			Data:      data[at:end],
+		})
+		at = end
+	}
+	if at != len(data) {
+		return 0, nil, fmt.Errorf("composite has %d bytes after its events", len(data)-at)
+	}
+	return policy, events, nil
+}
+
+// thread returns the thread of an event, if its layout begins with one.
+func (r *Registry) thread(e rawEvent, sizes IDSizes) (ThreadId, bool) {
+	et, _ := r.event(e.Kind)
+	if len(et.layout) == 0 || et.layout[0] != EventFieldThread {
+		return 0, false
+	}
+	t, err := parseId(bytes.NewReader(e.Data[5:]), sizes.ObjectIDSize)
+	return ThreadId(t), err == nil
+}
+
+// joinComposite builds the body of a Composite command from events.
+func joinComposite(policy SuspendPolicy, events []rawEvent) []byte {
+	s := Seq().Octet(uint8(policy)).Int(len(events)).Marshal()
+	for _, e := range events {
+		s = append(s, e.Data...)
+	}
+	return s
+}
+
+// size is the number of bytes taken by the field at the start of data. For the fields whose
+// size depends on their contents, data must hold enough to say what that is.
+func (f EventField) size(data []byte, sizes IDSizes) (int, error) {
+	switch f {
+	case EventFieldThread:
+		return sizes.ObjectIDSize, nil
+	case EventFieldLocation:
+		return 1 + sizes.ReferenceTypeIDSize + sizes.MethodIDSize + 8, nil
+	case EventFieldTaggedObject:
+		return 1 + sizes.ObjectIDSize, nil
+	case EventFieldValue:
+		if len(data) < 1 {
+			return 0, fmt.Errorf("value is missing its tag")
+		}
+		n, err := Tag(data[0]).size(sizes)
+		return 1 + n, err
+	case EventFieldByte:
+		return 1, nil
+	case EventFieldInt:
+		return 4, nil
+	case EventFieldLong:
+		return 8, nil
+	case EventFieldReferenceType:
+		return sizes.ReferenceTypeIDSize, nil
+	case EventFieldField:
+		return sizes.FieldIDSize, nil
+	case EventFieldString:
+		if len(data) < 4 {
+			return 0, fmt.Errorf("string is missing its length")
+		}
+		l := int(int32(binary.BigEndian.Uint32(data)))
+		if l < 0 || l > len(data)-4 {
+			return 0, fmt.Errorf("string of length %d is truncated", l)
+		}
+		return 4 + l, nil
+	}
+	return 0, fmt.Errorf("unknown event field %d", f)
+}
+
+// readFields reads the fields of an event, as laid out, returning their bytes.
+func readFields(buf io.Reader, layout []EventField, sizes IDSizes) ([]byte, error) {
+	var out bytes.Buffer
+	for _, f := range layout {
+		var n int
+		var err error
+		// The variably-sized fields begin by saying how long the rest of them is
+		switch f {
+		case EventFieldValue:
+			if _, err = io.CopyN(&out, buf, 1); err == nil {
+				n, err = Tag(out.Bytes()[out.Len()-1]).size(sizes)
+			}
+		case EventFieldString:
+			if _, err = io.CopyN(&out, buf, 4); err == nil {
+				n = int(int32(binary.BigEndian.Uint32(out.Bytes()[out.Len()-4:])))
+				if n < 0 {
+					err = fmt.Errorf("string has negative length %d", n)
+				}
+			}
+		default:
+			n, err = f.size(nil, sizes)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.CopyN(&out, buf, int64(n)); err != nil {
+			return nil, err
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// size is the number of bytes taken by an untagged value of the tag's type.
+func (t Tag) size(sizes IDSizes) (int, error) {
+	switch t {
+	case TagVoid:
+		return 0, nil
+	case TagByte, TagBoolean:
+		return 1, nil
+	case TagChar, TagShort:
+		return 2, nil
+	case TagFload, TagInt:
+		return 4, nil
+	case TagDouble, TagLong:
+		return 8, nil
+	case TagArray, TagObject, TagString, TagThread, TagThreadGroup, TagClassLoader, TagClassObject:
+		return sizes.ObjectIDSize, nil
+	}
+	return 0, fmt.Errorf("unknown value tag %q", byte(t))
+}
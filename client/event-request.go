@@ -1,74 +1,109 @@
 package client
 
 import (
-	"bytes"
-	"encoding/binary"
+	"fmt"
 )
 
+// The names by which the EventRequest commands were known before they were generated.
 const (
-	EventRequest        = CommandSet(15)
-	Set                 = Command(1)
-	Clear               = Command(2)
-	ClearAllBreakPoints = Command(3)
+	Set                 = EventRequestSetCommand
+	Clear               = EventRequestClearCommand
+	ClearAllBreakPoints = EventRequestClearAllBreakpoints
 )
 
 type EventRequestSet struct {
 	EventKind     EventKind     `jdwp:"Event kind to request. See JDWP.EventKind for a complete list of events that can be requested; some events may require a capability in order to be requested."`
 	SuspendPolicy SuspendPolicy `jdwp:"What threads are suspended when this event occurs? Note that the order of events and command replies accurately reflects the order in which threads are suspended and resumed. For example, if a VM-wide resume is processed before an event occurs which suspends the VM, the reply to the resume command will be written to the transport before the suspending event."`
 	Modifiers     int32         `jdwp:"Constraints used to control the number of generated events.Modifiers specify additional tests that an event must satisfy before it is placed in the event queue. Events are filtered by applying each modifier to an event in the order they are specified in this collection Only events that satisfy all modifiers are reported. A value of 0 means there are no modifiers in the request."`
-	Data          *bytes.Buffer
+	Mods          []Mod
 }
 
 func NewEventRequestSet(kind EventKind, policy SuspendPolicy) *EventRequestSet {
 	return &EventRequestSet{
 		EventKind:     kind,
 		SuspendPolicy: policy,
-		Data:          new(bytes.Buffer),
 	}
 }
 
 func (e *EventRequestSet) WithMod(kind ModKind) *EventRequestSet {
 	e.Modifiers++
-	if err := binary.Write(e.Data, binary.BigEndian, kind); err != nil {
-		panic(err)
-	}
+	e.Mods = append(e.Mods, Mod{ModKind: kind})
 	return e
 }
 
-func (e *EventRequestSet) WithInt(i32 int32) *EventRequestSet {
-	if err := binary.Write(e.Data, binary.BigEndian, i32); err != nil {
-		panic(err)
+// with appends a value to the most recently added modifier. The values are kept
+// unencoded until Marshal, since the width of any IDs depends on the VM.
+func (e *EventRequestSet) with(v interface{}) *EventRequestSet {
+	if len(e.Mods) == 0 {
+		panic("event request modifier value supplied before WithMod")
 	}
+	m := &e.Mods[len(e.Mods)-1]
+	m.Values = append(m.Values, v)
 	return e
 }
 
+func (e *EventRequestSet) WithInt(i32 int32) *EventRequestSet {
+	return e.with(i32)
+}
+
 func (e *EventRequestSet) WithReferenceTypeId(ref ReferenceTypeId) *EventRequestSet {
-	if err := binary.Write(e.Data, binary.BigEndian, ref); err != nil {
-		panic(err)
-	}
-	return e
+	return e.with(ref)
 }
 
 func (e *EventRequestSet) WithLocation(l Location) *EventRequestSet {
-	if err := l.Write(e.Data); err != nil {
-		panic(err)
-	}
-	return e
+	return e.with(l)
 }
 
-func (e *EventRequestSet) Marshal() []byte {
-	out := new(bytes.Buffer)
-	err := writeBytes(nil, out, e.EventKind)
-	err = writeBytes(err, out, e.SuspendPolicy)
-	err = writeBytes(err, out, e.Modifiers)
-	if err != nil {
-		panic(err)
-	}
-	_, err = out.Write(e.Data.Bytes())
-	if err != nil {
-		panic(err)
+// WithString supplies a pattern, for ClassMatch, ClassExclude and SourceNameMatch.
+func (e *EventRequestSet) WithString(str string) *EventRequestSet {
+	return e.with(str)
+}
+
+// WithBool supplies a flag, such as whether an Exception modifier reports caught exceptions.
+func (e *EventRequestSet) WithBool(b bool) *EventRequestSet {
+	return e.with(b)
+}
+
+// WithThreadId supplies a thread, for ThreadOnly and Step.
+func (e *EventRequestSet) WithThreadId(t ThreadId) *EventRequestSet {
+	return e.with(t)
+}
+
+func (e *EventRequestSet) Marshal(sizes IDSizes) []byte {
+	return e.seq(sizes).Marshal()
+}
+
+func (e *EventRequestSet) seq(sizes IDSizes) S {
+	s := sizes.Seq().
+		Octet(uint8(e.EventKind)).
+		Octet(uint8(e.SuspendPolicy)).
+		Int(int(e.Modifiers))
+	for _, m := range e.Mods {
+		s.Octet(uint8(m.ModKind))
+		for _, v := range m.Values {
+			switch v := v.(type) {
+			case int32:
+				s.Int(int(v))
+			case ReferenceTypeId:
+				s.ReferenceTypeId(v)
+			case Location:
+				s.Location(v)
+			case string:
+				s.String(v)
+			case bool:
+				if v {
+					s.Octet(1)
+				} else {
+					s.Octet(0)
+				}
+			case ThreadId:
+				s.ThreadId(v)
+			default:
+				panic(fmt.Sprintf("cannot marshal event request modifier value %#v", v))
+			}
+		}
 	}
-	return out.Bytes()
+	return s
 }
 
 type SuspendPolicy uint8
@@ -81,7 +116,7 @@ const (
 
 type Mod struct {
 	ModKind ModKind
-	Data    []byte
+	Values  []interface{}
 }
 
 type ModKind uint8
@@ -119,11 +154,23 @@ const (
 	// string	sourceNamePattern	Required source name pattern. Matches are limited to exact matches of the given pattern and matches of patterns that begin or end with '*'; for example, "*.Foo" or "java.*".
 )
 
-type EventRequestSetReply struct {
-	RequestId int // ID of created request
-}
-
 type EventRequestClear struct {
 	EventKind EventKind
 	RequestId int
 }
+
+// Set asks the VM to start reporting the requested events, returning the request's ID.
+func (e *EventRequestSet) Set(c Client) (int, error) {
+	res, err := call(c, EventRequest, Set, e.seq(c.IDSizes()))
+	if err != nil {
+		return 0, err
+	}
+	var reply EventRequestSetReply
+	err = c.IDSizes().Parse(res.Data, &reply)
+	return reply.RequestId, err
+}
+
+func (e EventRequestClear) Clear(c Client) error {
+	_, err := call(c, EventRequest, Clear, c.IDSizes().Seq().Value(e))
+	return err
+}
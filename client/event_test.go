@@ -1,6 +1,8 @@
 package client
 
 import (
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -30,3 +32,73 @@ func TestParseCompositeEvent(t *testing.T) {
 	err := Parse(data, &comp)
 	assert.Nil(t, err)
 }
+
+func TestParseCompositeEventFourByteIds(t *testing.T) {
+	sizes := IDSizes{FieldIDSize: 4, MethodIDSize: 4, ObjectIDSize: 4, ReferenceTypeIDSize: 4, FrameIDSize: 4}
+	data := []byte{
+		1,
+		0, 0, 0, 1,
+		2,          // EventKind.Breakpoint
+		0, 0, 0, 2, // requestId
+		0, 0, 0, 3, // threadId
+		// location
+		1,          // TypeTag = CLASS
+		0, 0, 0, 2, // ClassId
+		0, 0, 0, 9, // MethodId
+		0, 0, 0, 0, 0, 0, 0, 4, // Index
+	}
+	var comp Composite
+	err := sizes.Parse(data, &comp)
+	assert.Nil(t, err)
+	bp := comp.Events[0].(*EventBreakpoint)
+	assert.Equal(t, ThreadId(3), bp.Thread)
+	assert.Equal(t, ClassId(2), bp.Location.ClassId)
+	assert.Equal(t, uint64(9), bp.Location.MethodId.MethodId)
+	assert.Equal(t, uint64(4), bp.Location.Index)
+
+	out := sizes.Seq().Location(bp.Location).Marshal()
+	assert.Equal(t, data[14:], out)
+}
+
+func TestParseErrorPath(t *testing.T) {
+	sizes := IDSizes{FieldIDSize: 4, MethodIDSize: 4, ObjectIDSize: 4, ReferenceTypeIDSize: 4, FrameIDSize: 4}
+	data := []byte{
+		1,
+		0, 0, 0, 2,
+		2,          // EventKind.Breakpoint
+		0, 0, 0, 2, // requestId
+		0, 0, 0, 3, // threadId
+		1, 0, 0, 0, 2, 0, 0, 0, 9, 0, 0, 0, 0, 0, 0, 0, 4, // location
+		2,          // EventKind.Breakpoint
+		0, 0, 0, 5, // requestId
+		0, 0, 0, 6, // threadId
+		1, 0, 0, 0, 2, 0, 0, 0, 9, 0, 0, 0, 0, // location, cut short
+	}
+	var comp Composite
+	err := sizes.Parse(data, &comp)
+	var pe *ParseError
+	if assert.True(t, errors.As(err, &pe), "%v", err) {
+		assert.Equal(t, "Composite.Events[1].(*EventBreakpoint).Location.Index", pe.Path)
+		assert.Equal(t, 49, pe.Offset)
+		assert.Equal(t, data[41:], pe.Near)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+		assert.Equal(t, "parsing Composite.Events[1].(*EventBreakpoint).Location.Index at byte 49: unexpected EOF "+
+			"[00 00 00 02 00 00 00 09 | 00 00 00 00]", err.Error())
+	}
+
+	// An unknown kind of event is reported where its kind is
+	err = sizes.Parse(append(data[:31:31], 0xff), &Composite{})
+	if assert.True(t, errors.As(err, &pe), "%v", err) {
+		assert.Equal(t, "Composite.Events[1]", pe.Path)
+		assert.Equal(t, 31, pe.Offset)
+		assert.Contains(t, pe.Error(), "unknown event kind 255")
+	}
+
+	one := append([]byte{1, 0, 0, 0, 1}, data[5:31]...)
+	err = sizes.Parse(append(one, 0), &Composite{})
+	if assert.True(t, errors.As(err, &pe), "%v", err) {
+		assert.Equal(t, "Composite", pe.Path)
+		assert.Equal(t, 31, pe.Offset)
+		assert.Contains(t, pe.Error(), "unread bytes at the end of the buffer: 1 remain")
+	}
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxPacketSize bounds the length of the packets that a client will read: longer ones
+// are taken to be garbage, and end the connection.
+const DefaultMaxPacketSize = 64 << 20
+
+// WithMaxPacketSize replaces DefaultMaxPacketSize.
+func WithMaxPacketSize(size uint32) Option {
+	return func(c *client) {
+		c.maxPacket = size
+	}
+}
+
+// ErrFraming matches, using errors.Is, every *FramingError.
+var ErrFraming = errors.New("jdwp packet framing lost")
+
+// FramingError reports a packet that could not be read whole. The stream cannot be followed
+// past it, so the connection is ended, with this as the Cause of its *DisconnectedError.
+type FramingError struct {
+	Header Header // as read
+	Reason string
+	Err    error // the error that cut the packet short, if any
+}
+
+func (e *FramingError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("jdwp packet id %d of length %d %s: %v", e.Header.Id, e.Header.Length, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("jdwp packet id %d of length %d %s", e.Header.Id, e.Header.Length, e.Reason)
+}
+
+func (e *FramingError) Unwrap() error {
+	return e.Err
+}
+
+func (e *FramingError) Is(target error) bool {
+	return target == ErrFraming
+}
+
+// eagerRead is the size of packet for which the body is allocated before it is read. Longer
+// bodies are allocated as they arrive, so that a peer must send what it claims.
+const eagerRead = 64 << 10
+
+// readPacket reads the next whole packet from r, returning its header, the error code or
+// command pair that follows the header, and its data. It returns io.EOF only when r ends
+// cleanly between packets.
+func readPacket(r io.Reader, max uint32) (Header, uint16, []byte, error) {
+	var hdr [HeaderLength]byte
+	if n, err := io.ReadFull(r, hdr[:]); err != nil {
+		if n > 0 {
+			return Header{}, 0, nil, &FramingError{Reason: fmt.Sprintf("cut short after %d bytes of its header", n), Err: err}
+		}
+		return Header{}, 0, nil, err
+	}
+	header := Header{
+		Length: binary.BigEndian.Uint32(hdr[0:]),
+		Id:     Id(binary.BigEndian.Uint32(hdr[4:])),
+		Flags:  hdr[8],
+	}
+	pair := binary.BigEndian.Uint16(hdr[9:])
+	if header.Length < HeaderLength {
+		return header, 0, nil, &FramingError{Header: header, Reason: "is shorter than its header"}
+	}
+	if header.Length > max {
+		return header, 0, nil, &FramingError{Header: header, Reason: fmt.Sprintf("exceeds the maximum of %d", max)}
+	}
+	size := int64(header.Length - HeaderLength)
+	var data []byte
+	var err error
+	if size <= eagerRead {
+		data = make([]byte, size)
+		var n int
+		n, err = io.ReadFull(r, data)
+		size = int64(n)
+	} else {
+		var buf bytes.Buffer
+		size, err = io.CopyN(&buf, r, size)
+		data = buf.Bytes()
+	}
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return header, 0, nil, &FramingError{
+			Header: header,
+			Reason: fmt.Sprintf("cut short after %d bytes of its body", size),
+			Err:    err,
+		}
+	}
+	return header, pair, data, nil
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rawVM answers the handshake and IDSizes, then writes data and hangs up.
+func rawVM(conn net.Conn, data []byte) {
+	defer conn.Close()
+	hs := make([]byte, len(Handshake))
+	if _, err := io.ReadFull(conn, hs); err != nil {
+		return
+	}
+	conn.Write(hs)
+	var hdr [HeaderLength]byte
+	if _, err := io.ReadFull(conn, hdr[:]); err != nil {
+		return
+	}
+	a := &testAgent{conn: conn}
+	a.write(Id(binary.BigEndian.Uint32(hdr[4:])), FlagReply, 0, Seq().Int(8).Int(8).Int(8).Int(8).Int(8).Marshal())
+	conn.Write(data)
+}
+
+// framingError returns the error that ended a client after reading data.
+func framingError(t *testing.T, data []byte, opts ...Option) *FramingError {
+	here, there := net.Pipe()
+	go rawVM(there, data)
+	c, err := New(here, append(opts, WithLogger(NopLogger))...)
+	require.NoError(t, err)
+	defer c.Close()
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("still connected")
+	}
+	assert.True(t, errors.Is(c.Err(), ErrDisconnected))
+	assert.True(t, errors.Is(c.Err(), ErrFraming), "%v", c.Err())
+	var fe *FramingError
+	require.True(t, errors.As(c.Err(), &fe))
+	return fe
+}
+
+func header(length uint32, id Id) []byte {
+	hdr := make([]byte, HeaderLength)
+	binary.BigEndian.PutUint32(hdr[0:], length)
+	binary.BigEndian.PutUint32(hdr[4:], uint32(id))
+	hdr[8] = FlagReply
+	return hdr
+}
+
+func TestFramingLengthShorterThanHeader(t *testing.T) {
+	fe := framingError(t, header(5, 7))
+	assert.Equal(t, Header{Length: 5, Id: 7, Flags: FlagReply}, fe.Header)
+	assert.Contains(t, fe.Error(), "shorter than its header")
+}
+
+func TestFramingLengthOverMaximum(t *testing.T) {
+	fe := framingError(t, header(1<<31, 7), WithMaxPacketSize(1024))
+	assert.Equal(t, uint32(1<<31), fe.Header.Length)
+	assert.Contains(t, fe.Error(), "exceeds the maximum of 1024")
+}
+
+func TestFramingTruncated(t *testing.T) {
+	fe := framingError(t, append(header(HeaderLength+100, 7), make([]byte, 10)...))
+	assert.Equal(t, io.ErrUnexpectedEOF, fe.Err)
+	assert.Contains(t, fe.Error(), "cut short after 10 bytes of its body")
+
+	// Bodies too long to allocate up front are read as they arrive
+	fe = framingError(t, append(header(HeaderLength+1<<20, 7), make([]byte, 100000)...))
+	assert.Equal(t, io.ErrUnexpectedEOF, fe.Err)
+	assert.Contains(t, fe.Error(), "cut short after 100000 bytes of its body")
+
+	fe = framingError(t, header(HeaderLength, 7)[:6])
+	assert.Contains(t, fe.Error(), "cut short after 6 bytes of its header")
+}
+
+func TestFramingFailsPendingCalls(t *testing.T) {
+	here, there := net.Pipe()
+	go agent(t, there, func(p packet, reply func(uint16, []byte)) {
+		there.Write(header(3, p.Id))
+	})
+	c, err := New(here, WithLogger(NopLogger))
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.Call(VirtualMachine, VirtualMachineVersion, nil)
+	assert.True(t, errors.Is(err, ErrFraming), "%v", err)
+}
+
+func FuzzRead(f *testing.F) {
+	event := Seq().Octet(uint8(SuspendPolicyNone)).Int(1).Octet(uint8(EventKindVM_START)).Int(0).ObjectId(1).Marshal()
+	ev := header(uint32(HeaderLength+len(event)), 1)
+	ev[8], ev[9], ev[10] = 0, uint8(EventCommandSet), uint8(CompositeCommands)
+	f.Add([]byte{})
+	f.Add(append(ev, event...))
+	f.Add(header(0, 1))
+	f.Add(header(0xffffffff, 1))
+	f.Add(append(header(HeaderLength+4, 1), 1, 2))
+	f.Add(append(append(header(HeaderLength, 1), header(HeaderLength+2, 2)...), 1, 2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		here, there := net.Pipe()
+		go rawVM(there, data)
+		c, err := New(here, WithMaxPacketSize(1<<16), WithLogger(NopLogger))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		select {
+		case <-c.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("the reader did not notice the end of the stream")
+		}
+		if !errors.Is(c.Err(), ErrDisconnected) {
+			t.Fatalf("unexpected error %v", c.Err())
+		}
+
+		// A body that is framed correctly must still parse, or fail to, without panicking
+		bodies := [][]byte{data}
+		r := bytes.NewReader(data)
+		for {
+			_, _, body, err := readPacket(r, 1<<16)
+			if err != nil {
+				break
+			}
+			bodies = append(bodies, body)
+		}
+		registry := NewRegistry()
+		for _, body := range bodies {
+			for _, sizes := range []IDSizes{DefaultIDSizes, {FieldIDSize: 4, MethodIDSize: 4, ObjectIDSize: 4, ReferenceTypeIDSize: 4, FrameIDSize: 4}} {
+				for _, into := range []interface{}{
+					&Composite{},
+					&EventCompositeEvent{},
+					&ClassesBySignatureReply{},
+					&VariableTableReply{},
+					&ReferenceTypeConstantPoolReply{},
+					&ArrayReferenceGetValuesReply{},
+				} {
+					_ = sizes.Parse(body, into)
+					_ = registry.Parse(sizes, body, into)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+const Handshake = "JDWP-Handshake"
+
+// DefaultHandshakeTimeout bounds how long New waits for the VM to answer the handshake.
+const DefaultHandshakeTimeout = 10 * time.Second
+
+// ErrHandshake matches, using errors.Is, every error reporting a failed handshake.
+var ErrHandshake = errors.New("jdwp handshake failed")
+
+// HandshakeShortError reports a connection that ended part way through the handshake.
+type HandshakeShortError struct {
+	Received []byte // what arrived before the connection ended
+	Err      error
+}
+
+func (e *HandshakeShortError) Error() string {
+	return fmt.Sprintf("jdwp handshake cut short after %d/%d bytes (%q): %v", len(e.Received), len(Handshake), e.Received, e.Err)
+}
+
+func (e *HandshakeShortError) Unwrap() error {
+	return e.Err
+}
+
+func (e *HandshakeShortError) Is(target error) bool {
+	return target == ErrHandshake
+}
+
+// HandshakeMismatchError reports a peer that answered with something other than the
+// handshake: most likely the address is not a JDWP agent at all.
+type HandshakeMismatchError struct {
+	Received []byte // what had arrived when the difference was seen
+}
+
+func (e *HandshakeMismatchError) Error() string {
+	return fmt.Sprintf("jdwp handshake expected %q but received %q; is this a JDWP agent?", Handshake, e.Received)
+}
+
+func (e *HandshakeMismatchError) Is(target error) bool {
+	return target == ErrHandshake
+}
+
+// HandshakeTimeoutError reports a peer that did not complete the handshake in time.
+type HandshakeTimeoutError struct {
+	After    time.Duration // the handshake timeout in force
+	Received []byte        // what arrived before the deadline, which is a correct prefix
+}
+
+func (e *HandshakeTimeoutError) Error() string {
+	return fmt.Sprintf("jdwp handshake timed out after %v, having received %d/%d bytes", e.After, len(e.Received), len(Handshake))
+}
+
+func (e *HandshakeTimeoutError) Is(target error) bool {
+	return target == ErrHandshake
+}
+
+// Timeout lets the error be treated as a net.Error.
+func (e *HandshakeTimeoutError) Timeout() bool {
+	return true
+}
+
+func (e *HandshakeTimeoutError) Temporary() bool {
+	return false
+}
+
+// WithHandshakeTimeout replaces DefaultHandshakeTimeout. A timeout of zero waits forever.
+func WithHandshakeTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.handshakeTimeout = timeout
+	}
+}
+
+// Handshake sends the handshake and checks the VM's answer. The VM echoes the handshake
+// exactly, so the answer is checked as it arrives: a different banner is reported as soon as
+// it departs from the handshake, without waiting for the rest.
+func (c *client) Handshake() error {
+	var expired int32
+	if c.handshakeTimeout > 0 {
+		if d, ok := c.conn.(deadliner); ok {
+			if err := d.SetDeadline(time.Now().Add(c.handshakeTimeout)); err != nil {
+				return err
+			}
+			defer d.SetDeadline(time.Time{})
+		} else {
+			timer := time.AfterFunc(c.handshakeTimeout, func() {
+				atomic.StoreInt32(&expired, 1)
+				c.conn.Close()
+			})
+			defer timer.Stop()
+		}
+	}
+	fail := func(received []byte, err error) error {
+		if ne, ok := err.(net.Error); (ok && ne.Timeout()) || atomic.LoadInt32(&expired) != 0 {
+			return &HandshakeTimeoutError{After: c.handshakeTimeout, Received: received}
+		}
+		return &HandshakeShortError{Received: received, Err: err}
+	}
+
+	if _, err := c.conn.Write([]byte(Handshake)); err != nil {
+		return fail(nil, err)
+	}
+	resp := make([]byte, len(Handshake))
+	received := 0
+	for received < len(resp) {
+		n, err := c.conn.Read(resp[received:])
+		for i := received; i < received+n; i++ {
+			if resp[i] != Handshake[i] {
+				return &HandshakeMismatchError{Received: resp[:received+n]}
+			}
+		}
+		received += n
+		if err != nil && received < len(resp) {
+			return fail(resp[:received], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// peer reads the handshake from the far end of a pipe, then answers it with banner.
+func peer(banner string, hangUp bool) net.Conn {
+	here, there := net.Pipe()
+	go func() {
+		hs := make([]byte, len(Handshake))
+		if _, err := io.ReadFull(there, hs); err != nil {
+			return
+		}
+		there.Write([]byte(banner))
+		if hangUp {
+			there.Close()
+		}
+	}()
+	return here
+}
+
+func TestHandshakeTimesOut(t *testing.T) {
+	start := time.Now()
+	_, err := New(peer("JDWP-", false), WithHandshakeTimeout(50*time.Millisecond))
+	assert.True(t, time.Since(start) < 5*time.Second)
+	assert.True(t, errors.Is(err, ErrHandshake))
+	var timeout *HandshakeTimeoutError
+	if assert.True(t, errors.As(err, &timeout)) {
+		assert.Equal(t, 50*time.Millisecond, timeout.After)
+		assert.Equal(t, []byte("JDWP-"), timeout.Received)
+	}
+	ne, ok := err.(net.Error)
+	assert.True(t, ok && ne.Timeout())
+}
+
+func TestHandshakeTimesOutUnread(t *testing.T) {
+	// Nothing ever reads the handshake that is sent
+	here, _ := net.Pipe()
+	_, err := New(here, WithHandshakeTimeout(50*time.Millisecond))
+	var timeout *HandshakeTimeoutError
+	assert.True(t, errors.As(err, &timeout))
+}
+
+func TestHandshakeWrongBanner(t *testing.T) {
+	_, err := New(peer("HTTP/1.1 400 Bad Request\r\n", false))
+	assert.True(t, errors.Is(err, ErrHandshake))
+	var mismatch *HandshakeMismatchError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Contains(t, string(mismatch.Received), "HTTP/")
+	}
+	assert.Contains(t, err.Error(), `"HTTP/`)
+}
+
+func TestHandshakeCutShort(t *testing.T) {
+	_, err := New(peer("JDWP-Hand", true))
+	assert.True(t, errors.Is(err, ErrHandshake))
+	var short *HandshakeShortError
+	if assert.True(t, errors.As(err, &short)) {
+		assert.Equal(t, []byte("JDWP-Hand"), short.Received)
+		assert.Equal(t, io.EOF, short.Err)
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// IDSizes is the reply to VirtualMachine.IDSizes: the number of bytes used on the wire
+// for each of the variably-sized identifier types.
+type IDSizes struct {
+	FieldIDSize         int // fieldID size in bytes
+	MethodIDSize        int // methodID size in bytes
+	ObjectIDSize        int // objectID size in bytes
+	ReferenceTypeIDSize int // referenceTypeID size in bytes
+	FrameIDSize         int // frameID size in bytes
+}
+
+// DefaultIDSizes are the sizes used by HotSpot, and assumed by Seq and Parse.
+var DefaultIDSizes = IDSizes{
+	FieldIDSize:         8,
+	MethodIDSize:        8,
+	ObjectIDSize:        8,
+	ReferenceTypeIDSize: 8,
+	FrameIDSize:         8,
+}
+
+func (sizes IDSizes) validate() error {
+	for _, size := range []int{sizes.FieldIDSize, sizes.MethodIDSize, sizes.ObjectIDSize, sizes.ReferenceTypeIDSize, sizes.FrameIDSize} {
+		if size < 1 || size > 8 {
+			return fmt.Errorf("unsupported ID sizes: %+v", sizes)
+		}
+	}
+	return nil
+}
+
+// Seq starts a new command body whose IDs are encoded using these sizes.
+func (sizes IDSizes) Seq() S {
+	return &s{sizes: sizes}
+}
+
+// Parse decodes data into the structure pointed to by into, reading IDs using these sizes.
+func (sizes IDSizes) Parse(data []byte, into interface{}) error {
+	return parse(sizes, nil, data, into)
+}
+
+func parse(sizes IDSizes, r *Registry, data []byte, into interface{}) error {
+	buf := bytes.NewBuffer(data)
+	v := reflect.ValueOf(into)
+	root := "value"
+	if v.IsValid() {
+		root = typeName(v.Type())
+		if v.Kind() == reflect.Ptr && !v.IsNil() {
+			// The path starts from what is pointed to
+			v, root = v.Elem(), typeName(v.Type().Elem())
+		}
+	}
+	err := ParseBuf(&idReader{Reader: buf, sizes: sizes, registry: r}, v, nil, nil)
+	if err == nil && buf.Len() > 0 {
+		err = &ParseError{
+			Offset: len(data) - buf.Len(),
+			Err:    fmt.Errorf("unread bytes at the end of the buffer: %d remain", buf.Len()),
+		}
+	}
+	if pe, ok := err.(*ParseError); ok {
+		pe.Path = root + pe.Path
+		return pe.withData(data)
+	}
+	return err
+}
+
+// idReader carries the negotiated ID sizes and the registry alongside the bytes being parsed,
+// so that they reach ParseBuf through any registered interface factories. It counts the bytes
+// read, so that a ParseError can say where it was met.
+type idReader struct {
+	io.Reader
+	sizes    IDSizes
+	registry *Registry
+	offset   int
+}
+
+func (r *idReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.offset += n
+	return n, err
+}
+
+func offsetOf(buf io.Reader) int {
+	if r, ok := buf.(*idReader); ok {
+		return r.offset
+	}
+	return 0
+}
+
+func sizesOf(buf io.Reader) IDSizes {
+	if r, ok := buf.(*idReader); ok {
+		return r.sizes
+	}
+	return DefaultIDSizes
+}
+
+func registryOfReader(buf io.Reader) *Registry {
+	if r, ok := buf.(*idReader); ok {
+		return r.registry
+	}
+	return nil
+}
+
+// The IDs of the objects and types that the commands of the JDWP specification are sent for.
+type (
+	ThreadGroupId ObjectId
+	ClassLoaderId ObjectId
+	ClassObjectId ObjectId
+	ArrayId       ObjectId
+	ModuleId      ObjectId
+	InterfaceId   ReferenceTypeId
+	ArrayTypeId   ReferenceTypeId
+)
+
+var idTypes = map[reflect.Type]func(IDSizes) int{
+	reflect.TypeOf(ReferenceTypeId(0)): func(sizes IDSizes) int { return sizes.ReferenceTypeIDSize },
+	reflect.TypeOf(ClassId(0)):         func(sizes IDSizes) int { return sizes.ReferenceTypeIDSize },
+	reflect.TypeOf(ObjectId(0)):        func(sizes IDSizes) int { return sizes.ObjectIDSize },
+	reflect.TypeOf(ThreadId(0)):        func(sizes IDSizes) int { return sizes.ObjectIDSize },
+	reflect.TypeOf(StringId(0)):        func(sizes IDSizes) int { return sizes.ObjectIDSize },
+	reflect.TypeOf(ThreadGroupId(0)):   func(sizes IDSizes) int { return sizes.ObjectIDSize },
+	reflect.TypeOf(ClassLoaderId(0)):   func(sizes IDSizes) int { return sizes.ObjectIDSize },
+	reflect.TypeOf(ClassObjectId(0)):   func(sizes IDSizes) int { return sizes.ObjectIDSize },
+	reflect.TypeOf(ArrayId(0)):         func(sizes IDSizes) int { return sizes.ObjectIDSize },
+	reflect.TypeOf(ModuleId(0)):        func(sizes IDSizes) int { return sizes.ObjectIDSize },
+	reflect.TypeOf(InterfaceId(0)):     func(sizes IDSizes) int { return sizes.ReferenceTypeIDSize },
+	reflect.TypeOf(ArrayTypeId(0)):     func(sizes IDSizes) int { return sizes.ReferenceTypeIDSize },
+	reflect.TypeOf(FieldId(0)):         func(sizes IDSizes) int { return sizes.FieldIDSize },
+	reflect.TypeOf(FrameId(0)):         func(sizes IDSizes) int { return sizes.FrameIDSize },
+}
+
+// idSize reports whether a value of type t, held in field, is an ID - and if so, how wide it is.
+// Plain integer fields can be marked as IDs with a tag such as `jdwp:"id:method"`.
+func idSize(sizes IDSizes, t reflect.Type, field *reflect.StructField) (int, bool) {
+	if f, ok := idTypes[t]; ok {
+		return f(sizes), true
+	}
+	if field == nil {
+		return 0, false
+	}
+	switch findKey(field.Tag.Get("jdwp"), "id") {
+	case "field":
+		return sizes.FieldIDSize, true
+	case "method":
+		return sizes.MethodIDSize, true
+	case "object":
+		return sizes.ObjectIDSize, true
+	case "referenceType":
+		return sizes.ReferenceTypeIDSize, true
+	case "frame":
+		return sizes.FrameIDSize, true
+	}
+	return 0, false
+}
+
+func parseId(buf io.Reader, size int) (uint64, error) {
+	if size < 1 || size > 8 {
+		return 0, fmt.Errorf("unsupported ID size %d", size)
+	}
+	var b [8]byte
+	if _, err := io.ReadFull(buf, b[8-size:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+func writeId(out io.Writer, id uint64, size int) error {
+	if size < 1 || size > 8 {
+		return fmt.Errorf("unsupported ID size %d", size)
+	}
+	if size < 8 && id>>(8*uint(size)) != 0 {
+		return fmt.Errorf("ID %#x does not fit in %d bytes", id, size)
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], id)
+	_, err := out.Write(b[8-size:])
+	return err
+}
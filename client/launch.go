@@ -0,0 +1,225 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AgentLib is added to the arguments of a launched JVM, to have it wait for a debugger on a
+// port of its choosing.
+const AgentLib = "-agentlib:jdwp=transport=dt_socket,server=y,suspend=y,address=127.0.0.1:0"
+
+// DefaultLaunchTimeout bounds how long Launch waits for the VM to listen, and then to start.
+const DefaultLaunchTimeout = 30 * time.Second
+
+var listeningBanner = regexp.MustCompile(`Listening for transport dt_socket at address: (\S+)`)
+
+// LaunchOption configures Launch.
+type LaunchOption func(*launch)
+
+type launch struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	setup   func(Client, *EventVMStart) error
+	timeout time.Duration
+	opts    []Option
+}
+
+// WithOutput sets where the VM's standard output and error are streamed; by default, to
+// those of this process.
+func WithOutput(stdout io.Writer, stderr io.Writer) LaunchOption {
+	return func(l *launch) {
+		l.stdout = stdout
+		l.stderr = stderr
+	}
+}
+
+// WithSetup runs setup, such as setting breakpoints, while the VM is still suspended at its start.
+func WithSetup(setup func(c Client, start *EventVMStart) error) LaunchOption {
+	return func(l *launch) {
+		l.setup = setup
+	}
+}
+
+// WithLaunchTimeout replaces DefaultLaunchTimeout.
+func WithLaunchTimeout(timeout time.Duration) LaunchOption {
+	return func(l *launch) {
+		l.timeout = timeout
+	}
+}
+
+// WithClientOptions configures the client that attaches to the launched VM.
+func WithClientOptions(opts ...Option) LaunchOption {
+	return func(l *launch) {
+		l.opts = append(l.opts, opts...)
+	}
+}
+
+// Process is a VM started by Launch, along with the client attached to it.
+type Process struct {
+	Client
+	Address string // where the VM listened for the debugger
+	cmd     *exec.Cmd
+	streams sync.WaitGroup
+	exited  chan struct{}
+	err     error
+}
+
+// Launch runs a JVM under the debugger: path is the java command, and AgentLib is added
+// ahead of args. Once attached, it waits for the VM to start, runs any setup, and resumes it.
+//
+// The VM announces its address on its standard output or error, depending on the JDK; both
+// are watched, and streamed on as they arrive.
+func Launch(path string, args []string, opts ...LaunchOption) (*Process, error) {
+	l := &launch{stdout: os.Stdout, stderr: os.Stderr, timeout: DefaultLaunchTimeout}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	p := &Process{
+		cmd:    exec.Command(path, append([]string{AgentLib}, args...)...),
+		exited: make(chan struct{}),
+	}
+	stdout, err := p.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := p.cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.cmd.Start(); err != nil {
+		return nil, err
+	}
+	found := make(chan string, 1)
+	p.streams.Add(2)
+	go p.stream(stdout, l.stdout, found)
+	go p.stream(stderr, l.stderr, found)
+	go func() {
+		// Wait closes the pipes, so the streams must be read to the end first
+		p.streams.Wait()
+		p.err = p.cmd.Wait()
+		close(p.exited)
+	}()
+
+	timeout := time.NewTimer(l.timeout)
+	defer timeout.Stop()
+	select {
+	case p.Address = <-found:
+	case <-p.exited:
+		return nil, fmt.Errorf("%s exited before listening for a debugger: %v", path, p.err)
+	case <-timeout.C:
+		p.kill()
+		return nil, fmt.Errorf("%s did not listen for a debugger within %v", path, l.timeout)
+	}
+
+	p.Client, err = DialTimeout("tcp", p.Address, l.timeout, l.opts...)
+	if err != nil {
+		p.kill()
+		return nil, err
+	}
+	start, err := p.awaitStart(timeout.C)
+	if err == nil && l.setup != nil {
+		err = l.setup(p.Client, start)
+	}
+	if err == nil {
+		err = p.resume()
+	}
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// stream copies the output of the VM line by line, looking out for the listening banner.
+func (p *Process) stream(r io.Reader, w io.Writer, found chan<- string) {
+	defer p.streams.Done()
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			w.Write([]byte(line))
+			if m := listeningBanner.FindStringSubmatch(line); m != nil {
+				select {
+				case found <- listeningAddress(m[1]):
+				default:
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// listeningAddress turns the banner's address, which some JDKs give as a bare port, into
+// one that can be dialled.
+func listeningAddress(addr string) string {
+	if !strings.Contains(addr, ":") {
+		return "127.0.0.1:" + addr
+	}
+	return addr
+}
+
+func (p *Process) awaitStart(timeout <-chan time.Time) (*EventVMStart, error) {
+	select {
+	case e, ok := <-p.Events():
+		if !ok {
+			return nil, p.Err()
+		}
+		var comp Composite
+		if err := Decode(p.Client, e.Data, &comp); err != nil {
+			return nil, err
+		}
+		if len(comp.Events) > 0 {
+			if start, ok := comp.Events[0].(*EventVMStart); ok {
+				return start, nil
+			}
+		}
+		return nil, fmt.Errorf("expected the VM to start, but received %+v", comp)
+	case <-timeout:
+		return nil, fmt.Errorf("VM did not start")
+	}
+}
+
+func (p *Process) resume() error {
+	r, err := p.Call(VirtualMachine, VirtualMachineResume, nil)
+	if err != nil {
+		return err
+	}
+	if r.ErrCode != 0 {
+		return lookupError(r.ErrCode)
+	}
+	return nil
+}
+
+func (p *Process) kill() {
+	p.cmd.Process.Kill()
+	<-p.exited
+}
+
+// Exited is closed once the VM has exited, and its output has been streamed.
+func (p *Process) Exited() <-chan struct{} {
+	return p.exited
+}
+
+// Wait waits for the VM to exit, returning the error from exec.Cmd.Wait.
+func (p *Process) Wait() error {
+	<-p.exited
+	return p.err
+}
+
+// Close disconnects from the VM and kills it.
+func (p *Process) Close() error {
+	err := p.Client.Close()
+	p.kill()
+	return err
+}
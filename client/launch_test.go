@@ -0,0 +1,124 @@
+package client_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+// TestLaunchHelperProcess is not a test: run by the script from fakeJava, it stands in for a
+// JVM started with the JDWP agent, announcing its address and then serving the fake VM.
+func TestLaunchHelperProcess(t *testing.T) {
+	args, ok := os.LookupEnv("JDWP_LAUNCHED")
+	if !ok {
+		return
+	}
+	if !strings.HasPrefix(args, client.AgentLib+" ") {
+		fmt.Fprintln(os.Stderr, "launched without the agent:", args)
+		os.Exit(1)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		os.Exit(1)
+	}
+	fmt.Println("Listening for transport dt_socket at address:", l.Addr().(*net.TCPAddr).Port)
+
+	vm := jdwptest.NewVM()
+	newProgram(vm)
+	main := vm.AddThread("main")
+	a := jdwptest.NewAgent(vm)
+	a.OnAttach(func(s *jdwptest.Session) {
+		s.VMStart(main)
+	})
+	a.Handle(client.VirtualMachine, client.VirtualMachineResume, func(s *jdwptest.Session, p *jdwptest.Packet) ([]byte, error) {
+		fmt.Println("resumed")
+		return nil, nil
+	})
+	conn, err := l.Accept()
+	if err != nil {
+		os.Exit(1)
+	}
+	a.ServeConn(conn)
+	os.Exit(0)
+}
+
+// fakeJava writes a script that runs TestLaunchHelperProcess, passing on its arguments.
+func fakeJava(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "java")
+	script := fmt.Sprintf("#!/bin/sh\nJDWP_LAUNCHED=\"$*\" exec '%s' -test.run=TestLaunchHelperProcess\n", os.Args[0])
+	require.NoError(t, ioutil.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+// syncBuffer collects output written from another goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLaunch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jdwp")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stdout, stderr syncBuffer
+	var setupSaw []client.ClassDetails
+	p, err := client.Launch(fakeJava(t, dir), []string{"-cp", ".", "com.example.Main"},
+		client.WithOutput(&stdout, &stderr),
+		client.WithLaunchTimeout(10*time.Second),
+		client.WithSetup(func(c client.Client, start *client.EventVMStart) error {
+			assert.NotZero(t, start.Thread)
+			var err error
+			setupSaw, err = client.ClassesBySignature(c, "Lcom/example/Main;")
+			return err
+		}))
+	require.NoError(t, err, "stderr: %s", stderr.String())
+	assert.Len(t, setupSaw, 1)
+	assert.Contains(t, stdout.String(), "Listening for transport dt_socket at address: ")
+	assert.True(t, strings.HasPrefix(p.Address, "127.0.0.1:"))
+
+	// The VM was resumed once setup was done
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(stdout.String(), "resumed\n") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Contains(t, stdout.String(), "resumed\n")
+
+	assert.NoError(t, p.Close())
+	select {
+	case <-p.Exited():
+	default:
+		t.Fatal("VM still running after Close")
+	}
+}
+
+func TestLaunchExitsEarly(t *testing.T) {
+	_, err := client.Launch("false", nil, client.WithOutput(ioutil.Discard, ioutil.Discard))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exited before listening")
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"net"
+)
+
+// Listener accepts connections from VMs that were started with server=n, and which
+// therefore attach to the debugger rather than waiting to be attached to.
+type Listener struct {
+	l    net.Listener
+	opts []Option
+}
+
+// Listen opens address for VMs to attach to. The options apply to every accepted Client.
+func Listen(network string, address string, opts ...Option) (*Listener, error) {
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &Listener{l: l, opts: opts}, nil
+}
+
+// Accept waits for the next VM to connect and returns a ready Client for it.
+// Although the VM initiates the connection, JDWP still has the debugger open the
+// handshake, so the accepted connection is handshaken exactly as a dialled one is.
+func (l *Listener) Accept() (Client, error) {
+	conn, err := l.l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return New(conn, l.opts...)
+}
+
+func (l *Listener) Addr() net.Addr {
+	return l.l.Addr()
+}
+
+func (l *Listener) Close() error {
+	return l.l.Close()
+}
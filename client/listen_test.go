@@ -0,0 +1,39 @@
+package client
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenAcceptsAttachingVMs(t *testing.T) {
+	l, err := Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer l.Close()
+
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if !assert.Nil(t, err) {
+			return
+		}
+		go agent(t, conn, func(p packet, reply func(uint16, []byte)) {
+			reply(0, Seq().String("fake VM").Int(1).Int(8).String("1.8").String("fake").Marshal())
+		})
+
+		c, err := l.Accept()
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, DefaultIDSizes, c.IDSizes())
+
+		r, err := c.Call(VirtualMachine, VirtualMachineVersion, []byte{})
+		assert.Nil(t, err)
+		var v VersionReply
+		assert.Nil(t, Parse(r.Data, &v))
+		assert.Equal(t, "fake VM", v.Description)
+		c.Close()
+	}
+}
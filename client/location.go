@@ -1,11 +1,8 @@
 package client
 
 import (
-	"bytes"
 	"encoding/binary"
 	"io"
-
-	"github.com/sirupsen/logrus"
 )
 
 type Location struct {
@@ -54,21 +51,26 @@ func NewLocation(id MethodId, index int64) Location {
 	}
 }
 
+// parsed links the method to its class, so that its commands can be sent.
+func (l *Location) parsed() {
+	l.MethodId.ref = l.ClassId
+}
+
 func (s *s) Location(l Location) S {
-	if err := l.Write((*bytes.Buffer)(s)); err != nil {
-		logrus.WithError(err).Error("trouble writing out Location")
+	if err := l.Write(&s.buf, s.sizes); err != nil {
+		s.fail(err, "Location")
 	}
 	return s
 }
 
-func (l *Location) Write(out io.Writer) error {
+func (l *Location) Write(out io.Writer, sizes IDSizes) error {
 	if err := binary.Write(out, binary.BigEndian, l.TypeTag); err != nil {
 		return err
 	}
-	if err := binary.Write(out, binary.BigEndian, l.ClassId); err != nil {
+	if err := l.ClassId.Write(out, sizes); err != nil {
 		return err
 	}
-	if err := binary.Write(out, binary.BigEndian, l.MethodId.MethodId); err != nil {
+	if err := writeId(out, l.MethodId.MethodId, sizes.MethodIDSize); err != nil {
 		return err
 	}
 	if err := binary.Write(out, binary.BigEndian, l.Index); err != nil {
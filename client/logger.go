@@ -0,0 +1,43 @@
+package client
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// Logger is where a client reports what it is doing. A *logrus.Logger is one.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// DefaultLogger is the standard logrus logger.
+var DefaultLogger Logger = logrus.StandardLogger()
+
+// NopLogger discards everything.
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+// WithLogger replaces DefaultLogger.
+func WithLogger(l Logger) Option {
+	return func(c *client) {
+		c.log = l
+	}
+}
+
+// loggerOf is the Logger of a client from this package, or DefaultLogger for any other.
+func loggerOf(c Client) Logger {
+	if c, ok := c.(interface{ logger() Logger }); ok {
+		return c.logger()
+	}
+	return DefaultLogger
+}
+
+func (c *client) logger() Logger {
+	return c.log
+}
@@ -3,99 +3,124 @@ package client
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
-
-	"github.com/sirupsen/logrus"
+	"math"
+	"reflect"
 )
 
 type S interface {
 	Octet(uint8) S
 	Int(int) S
 	ReferenceTypeId(ReferenceTypeId) S
+	ObjectId(ObjectId) S
 	ThreadId(ThreadId) S
 	ClassId(ClassId) S
 	MethodId(MethodId) S
 	FrameId(Frame) S
+	Location(Location) S
 	String(string) S
+	// Value writes v as the package's Marshal would.
+	Value(interface{}) S
 
+	// Marshal returns the bytes written so far.
 	Marshal() []byte
+	// Err is the first error met while writing, if any; the command should not be sent.
+	Err() error
 }
 
-type s bytes.Buffer
+type s struct {
+	buf   bytes.Buffer
+	sizes IDSizes
+	err   error
+}
 
+// Seq starts a new command body, assuming DefaultIDSizes.
 func Seq() S {
-	return new(s)
+	return DefaultIDSizes.Seq()
 }
 
 func (s *s) Octet(octet uint8) S {
-	if err := binary.Write((*bytes.Buffer)(s), binary.BigEndian, octet); err != nil {
-		logrus.WithError(err).Error("trouble writing out octet")
+	if err := binary.Write(&s.buf, binary.BigEndian, octet); err != nil {
+		s.fail(err, "octet")
 	}
 	return s
 }
 
 func (s *s) Int(i int) S {
 	i32 := int32(i)
-	if err := binary.Write((*bytes.Buffer)(s), binary.BigEndian, i32); err != nil {
-		logrus.WithError(err).Error("trouble writing out integer")
+	if err := binary.Write(&s.buf, binary.BigEndian, i32); err != nil {
+		s.fail(err, "integer")
 	}
 	return s
 }
 
 func (s *s) ReferenceTypeId(ref ReferenceTypeId) S {
-	if err := ref.Write((*bytes.Buffer)(s)); err != nil {
-		logrus.WithError(err).Error("trouble writing out ReferenceTypeId")
+	if err := ref.Write(&s.buf, s.sizes); err != nil {
+		s.fail(err, "ReferenceTypeId")
 	}
 	return s
 }
 
-func (ref ReferenceTypeId) Write(out io.Writer) error {
-	return binary.Write(out, binary.BigEndian, ref)
+func (ref ReferenceTypeId) Write(out io.Writer, sizes IDSizes) error {
+	return writeId(out, uint64(ref), sizes.ReferenceTypeIDSize)
+}
+
+func (s *s) ObjectId(id ObjectId) S {
+	if err := id.Write(&s.buf, s.sizes); err != nil {
+		s.fail(err, "ObjectId")
+	}
+	return s
+}
+
+func (id ObjectId) Write(out io.Writer, sizes IDSizes) error {
+	return writeId(out, uint64(id), sizes.ObjectIDSize)
 }
 
 func (s *s) ClassId(id ClassId) S {
-	if err := id.Write((*bytes.Buffer)(s)); err != nil {
-		logrus.WithError(err).Error("trouble writing out ReferenceTypeId")
+	if err := id.Write(&s.buf, s.sizes); err != nil {
+		s.fail(err, "ClassId")
 	}
 	return s
 }
 
-func (id ClassId) Write(out io.Writer) error {
-	return binary.Write(out, binary.BigEndian, id)
+func (id ClassId) Write(out io.Writer, sizes IDSizes) error {
+	return writeId(out, uint64(id), sizes.ReferenceTypeIDSize)
 }
 
 func (s *s) ThreadId(id ThreadId) S {
-	if err := id.Write((*bytes.Buffer)(s)); err != nil {
-		logrus.WithError(err).Error("trouble writing out ReferenceTypeId")
+	if err := id.Write(&s.buf, s.sizes); err != nil {
+		s.fail(err, "ThreadId")
 	}
 	return s
 }
 
-func (id ThreadId) Write(out io.Writer) error {
-	return binary.Write(out, binary.BigEndian, id)
+func (id ThreadId) Write(out io.Writer, sizes IDSizes) error {
+	return writeId(out, uint64(id), sizes.ObjectIDSize)
 }
 
 func (s *s) FrameId(id Frame) S {
-	if err := id.Write((*bytes.Buffer)(s)); err != nil {
-		logrus.WithError(err).Error("trouble writing out ReferenceTypeId")
+	if err := id.Write(&s.buf, s.sizes); err != nil {
+		s.fail(err, "Frame")
 	}
 	return s
 }
 
-func (f Frame) Write(out io.Writer) error {
-	err := f.thr.Write(out)
+func (f Frame) Write(out io.Writer, sizes IDSizes) error {
+	err := f.thr.Write(out, sizes)
 	if err == nil {
-		err = binary.Write(out, binary.BigEndian, f.FrameId)
+		err = writeId(out, uint64(f.FrameId), sizes.FrameIDSize)
 	}
 	return err
 }
 
 func (s *s) MethodId(m MethodId) S {
-	if err := binary.Write((*bytes.Buffer)(s), binary.BigEndian, m.ref); err != nil {
-		logrus.WithError(err).Error("trouble writing out MethodId.ReferenceTypeId")
+	if err := m.ref.Write(&s.buf, s.sizes); err != nil {
+		s.fail(err, "MethodId.ReferenceTypeId")
 	}
-	if err := binary.Write((*bytes.Buffer)(s), binary.BigEndian, m.MethodId); err != nil {
-		logrus.WithError(err).Error("trouble writing out MethodId.MethodId")
+	if err := writeId(&s.buf, m.MethodId, s.sizes.MethodIDSize); err != nil {
+		s.fail(err, "MethodId.MethodId")
 	}
 	return s
 }
@@ -103,18 +128,225 @@ func (s *s) MethodId(m MethodId) S {
 func (s *s) String(str string) S {
 	b := []byte(str)
 	l32 := uint32(len(b))
-	if err := binary.Write((*bytes.Buffer)(s), binary.BigEndian, l32); err != nil {
-		logrus.WithError(err).Error("trouble writing out string length")
+	if err := binary.Write(&s.buf, binary.BigEndian, l32); err != nil {
+		s.fail(err, "string length")
 		return s
 	}
 
-	if _, err := (*bytes.Buffer)(s).Write(b); err != nil {
-		logrus.WithError(err).Error("trouble writing out string length")
+	if _, err := s.buf.Write(b); err != nil {
+		s.fail(err, "string")
 	}
 
 	return s
 }
 
 func (s *s) Marshal() []byte {
-	return (*bytes.Buffer)(s).Bytes()
+	return s.buf.Bytes()
+}
+
+func (s *s) Err() error {
+	return s.err
+}
+
+func (s *s) fail(err error, what string) {
+	if s.err == nil {
+		s.err = fmt.Errorf("writing %s: %w", what, err)
+	}
+}
+
+func (s *s) Value(v interface{}) S {
+	if err := MarshalBuf(&idWriter{Writer: &s.buf, sizes: s.sizes}, reflect.ValueOf(v), nil, nil); err != nil {
+		s.fail(err, fmt.Sprintf("%T", v))
+	}
+	return s
+}
+
+// Marshal encodes v, which is laid out as for Parse, assuming DefaultIDSizes.
+func Marshal(v interface{}) ([]byte, error) {
+	return DefaultIDSizes.Marshal(v)
+}
+
+// Marshal encodes v, which is laid out as for Parse, writing IDs using these sizes.
+func (sizes IDSizes) Marshal(v interface{}) ([]byte, error) {
+	return marshal(sizes, nil, v)
+}
+
+// Marshal encodes v, which is laid out as for Parse, writing IDs using sizes and accepting
+// the interface types registered with the registry.
+func (r *Registry) Marshal(sizes IDSizes, v interface{}) ([]byte, error) {
+	return marshal(sizes, r, v)
+}
+
+func marshal(sizes IDSizes, r *Registry, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := MarshalBuf(&idWriter{Writer: &buf, sizes: sizes, registry: r}, reflect.ValueOf(v), nil, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// idWriter is the counterpart of idReader, for marshalling.
+type idWriter struct {
+	io.Writer
+	sizes    IDSizes
+	registry *Registry
+}
+
+// MarshalBuf is the counterpart of ParseBuf. The counter of each slice is written as the
+// slice's length, whatever its field holds, and a slice within a slice is preceded by its
+// length. A value in an interface is preceded by its tag,
+// event kind or the byte selecting it, and one implementing Marshaller writes itself.
+func MarshalBuf(out io.Writer, v reflect.Value, parent *reflect.Value, parentField *reflect.StructField) error {
+	var sizes IDSizes
+	var registry *Registry
+	if w, ok := out.(*idWriter); ok {
+		sizes, registry = w.sizes, w.registry
+	} else {
+		sizes = DefaultIDSizes
+	}
+	if !v.IsValid() {
+		return errors.New("cannot marshal nil")
+	}
+	t := v.Type()
+	if size, ok := idSize(sizes, t, parentField); ok {
+		return writeId(out, v.Uint(), size)
+	}
+	if m, ok := marshallerOf(v); ok {
+		return m.Marshal(out)
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return fmt.Errorf("cannot marshal nil %s", t)
+		}
+		return MarshalBuf(out, v.Elem(), nil, nil)
+	case reflect.Struct:
+		// Work on a copy whose counters can be filled in
+		c := reflect.New(t).Elem()
+		c.Set(v)
+		for i := 0; i < t.NumField(); i++ {
+			tf := t.Field(i)
+			if tf.Type.Kind() != reflect.Slice {
+				continue
+			}
+			counter := c.FieldByName(findKey(tf.Tag.Get("jdwp"), "counter"))
+			if !counter.IsValid() {
+				return fmt.Errorf("slice %s.%s has no counter", t, tf.Name)
+			}
+			switch counter.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				counter.SetInt(int64(c.Field(i).Len()))
+			case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				counter.SetUint(uint64(c.Field(i).Len()))
+			default:
+				return fmt.Errorf("counter of %s.%s is not an integer", t, tf.Name)
+			}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			tf := t.Field(i)
+			if tf.PkgPath != "" {
+				continue
+			}
+			if err := MarshalBuf(out, c.Field(i), &c, &tf); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if err := binary.Write(out, binary.BigEndian, int32(v.Len())); err != nil {
+			return err
+		}
+		_, err := io.WriteString(out, v.String())
+		return err
+	case reflect.Bool:
+		return binary.Write(out, binary.BigEndian, v.Bool())
+	case reflect.Int8:
+		return binary.Write(out, binary.BigEndian, int8(v.Int()))
+	case reflect.Int16:
+		return binary.Write(out, binary.BigEndian, int16(v.Int()))
+	case reflect.Int, reflect.Int32:
+		return binary.Write(out, binary.BigEndian, int32(v.Int()))
+	case reflect.Uint8:
+		return binary.Write(out, binary.BigEndian, uint8(v.Uint()))
+	case reflect.Uint16:
+		return binary.Write(out, binary.BigEndian, uint16(v.Uint()))
+	case reflect.Uint32:
+		return binary.Write(out, binary.BigEndian, uint32(v.Uint()))
+	case reflect.Uint64:
+		return binary.Write(out, binary.BigEndian, v.Uint())
+	case reflect.Int64:
+		return binary.Write(out, binary.BigEndian, v.Int())
+	case reflect.Float32:
+		return binary.Write(out, binary.BigEndian, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		return binary.Write(out, binary.BigEndian, math.Float64bits(v.Float()))
+	case reflect.Slice:
+		if parentField == nil {
+			return fmt.Errorf("slice %s has no counter", t)
+		}
+		return marshalElems(out, v)
+	case reflect.Array:
+		return marshalElems(out, v)
+	case reflect.Interface:
+		if _, ok := registry.factory(t); !ok {
+			return fmt.Errorf("cannot marshal type %s", t)
+		}
+		if v.IsNil() {
+			return fmt.Errorf("cannot marshal nil %s", t)
+		}
+		switch e := v.Elem().Interface().(type) {
+		case TaggedValue:
+			if err := binary.Write(out, binary.BigEndian, e.Tag()); err != nil {
+				return err
+			}
+		case VMEvent:
+			if err := binary.Write(out, binary.BigEndian, e.EventKind()); err != nil {
+				return err
+			}
+		case interface{ alternative() uint8 }:
+			if err := binary.Write(out, binary.BigEndian, e.alternative()); err != nil {
+				return err
+			}
+		}
+		return MarshalBuf(out, v.Elem(), nil, nil)
+	default:
+		return fmt.Errorf("cannot marshal %s, of kind %s", t, t.Kind())
+	}
+	return nil
+}
+
+// marshalElems writes the elements of a slice or array. One that is itself a slice is preceded
+// by its count, as parseElem expects.
+func marshalElems(out io.Writer, v reflect.Value) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem() == byteType {
+		_, err := out.Write(v.Bytes())
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i)
+		if e.Kind() == reflect.Slice {
+			if err := binary.Write(out, binary.BigEndian, int32(e.Len())); err != nil {
+				return err
+			}
+			if err := marshalElems(out, e); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := MarshalBuf(out, e, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func marshallerOf(v reflect.Value) (Marshaller, bool) {
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		m, ok := v.Interface().(Marshaller)
+		return m, ok
+	}
+	if v.CanAddr() {
+		m, ok := v.Addr().Interface().(Marshaller)
+		return m, ok
+	}
+	return nil, false
 }
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalComposite(t *testing.T) {
+	sizes := IDSizes{FieldIDSize: 4, MethodIDSize: 4, ObjectIDSize: 4, ReferenceTypeIDSize: 4, FrameIDSize: 4}
+	comp := Composite{
+		SuspendPolicy: SuspendPolicyEventThread,
+		Events: []VMEvent{
+			&EventBreakpoint{RequestId: 2, Thread: 3, Location: Location{TypeTag: TypeTagClass, ClassId: 2, MethodId: MethodId{MethodId: 9}, Index: 4}},
+			&EventUnknown{Kind: EventKindTHREAD_START, RequestId: 5, Data: []byte{0, 0, 0, 6}},
+		},
+	}
+	data, err := sizes.Marshal(&comp)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		1,
+		0, 0, 0, 2, // filled in from the events
+		2,          // EventKind.Breakpoint
+		0, 0, 0, 2, // requestId
+		0, 0, 0, 3, // threadId
+		1,          // TypeTag = CLASS
+		0, 0, 0, 2, // ClassId
+		0, 0, 0, 9, // MethodId
+		0, 0, 0, 0, 0, 0, 0, 4, // Index
+		6,          // EventKind.THREAD_START
+		0, 0, 0, 5, // requestId
+		0, 0, 0, 6, // threadId
+	}, data)
+
+	var parsed Composite
+	require.NoError(t, sizes.Parse(data, &parsed))
+	comp.NumEvents = 2
+	comp.Events[0].(*EventBreakpoint).Location.MethodId.ref = 2
+	assert.Equal(t, comp, parsed)
+}
+
+func TestMarshalTaggedValues(t *testing.T) {
+	object, str := ObjectId(7), StringId(8)
+	values := struct {
+		Count  int
+		Values []TaggedValue `jdwp:"counter:Count"`
+	}{Values: []TaggedValue{&object, &str}}
+	data, err := Marshal(values)
+	require.NoError(t, err)
+	assert.Equal(t, Seq().Int(2).Octet(uint8(TagObject)).ObjectId(7).Octet(uint8(TagString)).ObjectId(8).Marshal(), data)
+
+	// The builder writes values too
+	s := Seq().Octet(1).Value(values)
+	require.NoError(t, s.Err())
+	assert.Equal(t, append([]byte{1}, data...), s.Marshal())
+}
+
+func TestMarshalErrors(t *testing.T) {
+	_, err := Marshal(nil)
+	assert.Error(t, err)
+	_, err = Marshal(struct{ Values []string }{})
+	assert.Contains(t, err.Error(), "has no counter")
+	_, err = Marshal(struct{ Value interface{ Tag() Tag } }{})
+	assert.Contains(t, err.Error(), "cannot marshal type")
+	_, err = Marshal(struct{ Value complex64 }{})
+	assert.Contains(t, err.Error(), "cannot marshal complex64")
+	_, err = IDSizes{ObjectIDSize: 4}.Marshal(ObjectId(1 << 40))
+	assert.Contains(t, err.Error(), "does not fit")
+
+	s := Seq().Value(struct{ Value complex64 }{})
+	assert.Contains(t, s.Err().Error(), "writing struct { Value complex64 }")
+}
+
+type sample struct {
+	Tag     uint8
+	Object  ObjectId
+	Class   ClassId
+	Size    int32
+	Serial  uint32
+	Time    int64
+	Index   uint64
+	Method  uint64 `jdwp:"id:method"`
+	Count   int
+	Names   []string `jdwp:"counter:Count"`
+	Fields  int
+	Members []Field `jdwp:"counter:Fields"`
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	roundTrip := func(in sample) bool {
+		data, err := Marshal(in)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		var out sample
+		if err := Parse(data, &out); err != nil {
+			t.Log(err)
+			return false
+		}
+		in.Count, in.Fields = len(in.Names), len(in.Members)
+		if len(in.Names) == 0 {
+			in.Names = nil
+		}
+		if len(in.Members) == 0 {
+			in.Members = nil
+		}
+		if len(out.Names) == 0 {
+			out.Names = nil
+		}
+		if len(out.Members) == 0 {
+			out.Members = nil
+		}
+		return reflect.DeepEqual(in, out)
+	}
+	assert.NoError(t, quick.Check(roundTrip, nil))
+}
+
+type primitives struct {
+	Flag   bool
+	Byte   int8
+	Char   uint16
+	Short  int16
+	Float  float32
+	Double float64
+	Ids    [2]ObjectId
+}
+
+func TestMarshalPrimitivesRoundTrip(t *testing.T) {
+	roundTrip := func(in primitives) bool {
+		data, err := Marshal(in)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		var out primitives
+		if err := Parse(data, &out); err != nil {
+			t.Log(err)
+			return false
+		}
+		return reflect.DeepEqual(in, out)
+	}
+	assert.NoError(t, quick.Check(roundTrip, nil))
+}
+
+func TestParseLayouts(t *testing.T) {
+	type layouts struct {
+		Flag     bool
+		Char     uint16
+		Double   float64
+		Length   uint8
+		Code     []byte `jdwp:"counter:Length"`
+		Rows     int
+		Table    [][]int16 `jdwp:"counter:Rows"`
+		Position [2]int8
+	}
+	data := []byte{
+		1,
+		0, 'x',
+		0x3f, 0xf8, 0, 0, 0, 0, 0, 0, // 1.5
+		3,
+		0xb1, 0x2a, 0xac,
+		0, 0, 0, 2,
+		0, 0, 0, 1, 0, 7,
+		0, 0, 0, 2, 0xff, 0xff, 0, 9,
+		0xfe, 4,
+	}
+	var l layouts
+	require.NoError(t, Parse(data, &l))
+	assert.Equal(t, layouts{
+		Flag:     true,
+		Char:     'x',
+		Double:   1.5,
+		Length:   3,
+		Code:     []byte{0xb1, 0x2a, 0xac},
+		Rows:     2,
+		Table:    [][]int16{{7}, {-1, 9}},
+		Position: [2]int8{-2, 4},
+	}, l)
+
+	again, err := Marshal(l)
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+
+	// A short buffer fails, rather than trusting the count with an allocation
+	err = Parse([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 200, 1}, &layouts{})
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "%v", err)
+}
+
+func TestParseErrors(t *testing.T) {
+	assert.Contains(t, Parse([]byte{0, 0, 0, 0, 0, 0, 0, 0}, &struct{ Value complex64 }{}).Error(),
+		"cannot parse complex64")
+	assert.Contains(t, Parse(nil, &struct{ Values []string }{}).Error(), "has no counter")
+	assert.Contains(t, Parse([]byte{0, 0, 0, 1, 0xff, 0xff, 0xff, 0xff}, &struct {
+		Count  int
+		Values [][]int `jdwp:"counter:Count"`
+	}{}).Error(), "has -1 elements")
+	assert.Contains(t, Parse([]byte{'V', 0x7f, 0xff, 0xff, 0xff}, &ArrayRegion{}).Error(), "void values")
+	many := struct {
+		Count  int
+		Values []struct{} `jdwp:"counter:Count"`
+	}{}
+	assert.NoError(t, Parse([]byte{0x7f, 0xff, 0xff, 0xff}, &many))
+	assert.Len(t, many.Values, 0x7fffffff)
+	assert.Contains(t, Parse([]byte{0xff, 0xff, 0xff, 0xff}, &struct {
+		Count  int
+		Values []int `jdwp:"counter:Count"`
+	}{}).Error(), "has -1 elements")
+}
+
+func TestParseStringLength(t *testing.T) {
+	var s struct{ Value string }
+	err := Parse([]byte{0xff, 0xff, 0xff, 0xff}, &s)
+	assert.Contains(t, err.Error(), "string of -1 bytes")
+
+	// A length far beyond the data fails without allocating for it
+	err = Parse([]byte{0x7f, 0xff, 0xff, 0xff, 'h', 'i'}, &s)
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "%v", err)
+}
+
+func TestPrimitiveValues(t *testing.T) {
+	region := ArrayRegion{Tag: TagChar, Values: []TaggedValue{CharValue('h'), CharValue('i')}}
+	data, err := Marshal(&region)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{'C', 0, 0, 0, 2, 0, 'h', 0, 'i'}, data)
+
+	var parsed ArrayRegion
+	require.NoError(t, Parse(data, &parsed))
+	require.Len(t, parsed.Values, 2)
+	v, err := parsed.Values[1].RecoverValue(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 'i', v)
+
+	for tag, want := range map[Tag]interface{}{
+		TagBoolean: true,
+		TagByte:    int8(-1),
+		TagShort:   int16(-1),
+		TagFload:   float32(-1),
+		TagDouble:  float64(-1),
+	} {
+		var value struct{ Value TaggedValue }
+		data := map[Tag][]byte{
+			TagBoolean: {1},
+			TagByte:    {0xff},
+			TagShort:   {0xff, 0xff},
+			TagFload:   {0xbf, 0x80, 0, 0},
+			TagDouble:  {0xbf, 0xf0, 0, 0, 0, 0, 0, 0},
+		}[tag]
+		require.NoError(t, Parse(append([]byte{uint8(tag)}, data...), &value), "%c", tag)
+		v, err := value.Value.RecoverValue(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, want, v)
+	}
+	assert.Equal(t, TagShort, VariableDef{Signature: "S"}.Tag())
+	assert.Equal(t, TagArray, VariableDef{Signature: "[I"}.Tag())
+}
@@ -1,23 +1,12 @@
 package client
 
-import "github.com/sirupsen/logrus"
-
-const (
-	Method              = CommandSet(6)
-	MethodLineTable     = Command(1)
-	MethodVariableTable = Command(2)
-)
-
 func (m MethodId) LineTable(c Client) (*LineTableReply, error) {
-	res, err := c.Call(Method, MethodLineTable, Seq().MethodId(m).Marshal())
+	res, err := call(c, Method, MethodLineTable, c.IDSizes().Seq().MethodId(m))
 	if err != nil {
 		return nil, err
 	}
-	if res.ErrCode != 0 {
-		return nil, lookupError(res.ErrCode)
-	}
 	var lt LineTableReply
-	err = Parse(res.Data, &lt)
+	err = c.IDSizes().Parse(res.Data, &lt)
 	if err != nil {
 		return nil, err
 	}
@@ -37,15 +26,12 @@ type LineEntry struct {
 }
 
 func (m MethodId) VariableTable(c Client) (*VariableTableReply, error) {
-	res, err := c.Call(Method, MethodVariableTable, Seq().MethodId(m).Marshal())
+	res, err := call(c, Method, MethodVariableTable, c.IDSizes().Seq().MethodId(m))
 	if err != nil {
 		return nil, err
 	}
-	if res.ErrCode != 0 {
-		return nil, lookupError(res.ErrCode)
-	}
 	var vtr VariableTableReply
-	err = Parse(res.Data, &vtr)
+	err = c.IDSizes().Parse(res.Data, &vtr)
 	if err != nil {
 		return nil, err
 	}
@@ -66,12 +52,11 @@ type VariableDef struct {
 	Slot      int
 }
 
+// Tag is the tag of the variable's values. The tags of primitives and arrays are the first
+// character of their signatures, as is that of objects.
 func (v VariableDef) Tag() Tag {
-	switch v.Signature[0] {
-	case 'L':
-		return TagObject
-	default:
-		logrus.Fatalf("don't know how to produce a tag for %+v", v)
+	if v.Signature == "" {
 		return TagVoid
 	}
+	return Tag(v.Signature[0])
 }
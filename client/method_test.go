@@ -8,13 +8,14 @@ import (
 
 func TestParseLineTable(t *testing.T) {
 	data := []byte{
-		0, 0, 0, 39, 76, 111, 114, 103,
-		47, 105, 111, 99, 116, 108, 47, 100,
-		101, 98, 117, 103,
-		47, 97, 112, 112, 47,
-		87, 101, 98, 83, 101, 114, 118, 101, 114, 36, 72, 97, 110, 100, 108, 101, 114, 59,
+		0, 0, 0, 0, 0, 0, 0, 0, // start
+		0, 0, 0, 0, 0, 0, 0, 20, // end
+		0, 0, 0, 2, // lines
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 23,
+		0, 0, 0, 0, 0, 0, 0, 8, 0, 0, 0, 24,
 	}
 	var ltr LineTableReply
 	err := Parse(data, &ltr)
 	assert.Nil(t, err)
+	assert.Equal(t, []LineEntry{{LineCodeIndex: 0, LineNumber: 23}, {LineCodeIndex: 8, LineNumber: 24}}, ltr.LineEntries)
 }
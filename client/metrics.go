@@ -0,0 +1,187 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the round-trip latency histogram kept by Metrics.
+var LatencyBuckets = []time.Duration{
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// CommandKey identifies a command.
+type CommandKey struct {
+	Set     CommandSet
+	Command Command
+}
+
+// CommandStats are the figures kept for one command.
+type CommandStats struct {
+	Sent     uint64            // commands written
+	Replies  map[uint16]uint64 // replies received, by error code
+	Latency  time.Duration     // total round-trip time of the replies
+	Buckets  []uint64          // replies within each of LatencyBuckets, not cumulative
+	Overflow uint64            // replies slower than the last bucket
+}
+
+// Metrics is an Observer that counts commands, their error codes and their round-trip
+// latencies, along with the packets received. It is also an http.Handler, serving these in
+// the Prometheus text format.
+type Metrics struct {
+	mu       sync.Mutex
+	commands map[CommandKey]*CommandStats
+	received map[string]uint64 // by kind of packet
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		commands: map[CommandKey]*CommandStats{},
+		received: map[string]uint64{},
+	}
+}
+
+func (m *Metrics) stats(key CommandKey) *CommandStats {
+	s, ok := m.commands[key]
+	if !ok {
+		s = &CommandStats{Replies: map[uint16]uint64{}, Buckets: make([]uint64, len(LatencyBuckets))}
+		m.commands[key] = s
+	}
+	return s
+}
+
+func (m *Metrics) PacketSent(p PacketInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats(CommandKey{p.Set, p.Command}).Sent++
+}
+
+func (m *Metrics) PacketReceived(p PacketInfo) {
+	kind := "command"
+	if p.IsReply() {
+		kind = "reply"
+	} else if p.Set == EventCommandSet {
+		kind = "event"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.received[kind]++
+}
+
+func (m *Metrics) CommandReplied(set CommandSet, cmd Command, errCode uint16, rtt time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stats(CommandKey{set, cmd})
+	s.Replies[errCode]++
+	s.Latency += rtt
+	for i, b := range LatencyBuckets {
+		if rtt <= b {
+			s.Buckets[i]++
+			return
+		}
+	}
+	s.Overflow++
+}
+
+// Command returns a copy of the figures for one command.
+func (m *Metrics) Command(set CommandSet, cmd Command) CommandStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cs := CommandStats{Replies: map[uint16]uint64{}, Buckets: make([]uint64, len(LatencyBuckets))}
+	if s, ok := m.commands[CommandKey{set, cmd}]; ok {
+		cs.Sent, cs.Latency, cs.Overflow = s.Sent, s.Latency, s.Overflow
+		copy(cs.Buckets, s.Buckets)
+		for code, n := range s.Replies {
+			cs.Replies[code] = n
+		}
+	}
+	return cs
+}
+
+// Received is the number of packets received of a kind: "reply", "event" or "command".
+func (m *Metrics) Received(kind string) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.received[kind]
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WritePrometheus(out io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]CommandKey, 0, len(m.commands))
+	for k := range m.commands {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Set != keys[j].Set {
+			return keys[i].Set < keys[j].Set
+		}
+		return keys[i].Command < keys[j].Command
+	})
+
+	w := bufio.NewWriter(out)
+	fmt.Fprintln(w, "# HELP jdwp_commands_sent_total Commands sent to the VM.")
+	fmt.Fprintln(w, "# TYPE jdwp_commands_sent_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(w, "jdwp_commands_sent_total{%s} %d\n", k.labels(), m.commands[k].Sent)
+	}
+
+	fmt.Fprintln(w, "# HELP jdwp_replies_total Replies received from the VM, by error code.")
+	fmt.Fprintln(w, "# TYPE jdwp_replies_total counter")
+	for _, k := range keys {
+		s := m.commands[k]
+		codes := make([]int, 0, len(s.Replies))
+		for code := range s.Replies {
+			codes = append(codes, int(code))
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(w, "jdwp_replies_total{%s,error=\"%d\"} %d\n", k.labels(), code, s.Replies[uint16(code)])
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP jdwp_command_duration_seconds Round-trip time of commands.")
+	fmt.Fprintln(w, "# TYPE jdwp_command_duration_seconds histogram")
+	for _, k := range keys {
+		s := m.commands[k]
+		var count uint64
+		for i, b := range LatencyBuckets {
+			count += s.Buckets[i]
+			fmt.Fprintf(w, "jdwp_command_duration_seconds_bucket{%s,le=\"%g\"} %d\n", k.labels(), b.Seconds(), count)
+		}
+		count += s.Overflow
+		fmt.Fprintf(w, "jdwp_command_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", k.labels(), count)
+		fmt.Fprintf(w, "jdwp_command_duration_seconds_sum{%s} %g\n", k.labels(), s.Latency.Seconds())
+		fmt.Fprintf(w, "jdwp_command_duration_seconds_count{%s} %d\n", k.labels(), count)
+	}
+
+	fmt.Fprintln(w, "# HELP jdwp_packets_received_total Packets received from the VM, by kind.")
+	fmt.Fprintln(w, "# TYPE jdwp_packets_received_total counter")
+	for _, kind := range []string{"command", "event", "reply"} {
+		fmt.Fprintf(w, "jdwp_packets_received_total{kind=\"%s\"} %d\n", kind, m.received[kind])
+	}
+	return w.Flush()
+}
+
+func (k CommandKey) labels() string {
+	return fmt.Sprintf("set=\"%d\",command=\"%d\"", k.Set, k.Command)
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WritePrometheus(w)
+}
@@ -0,0 +1,129 @@
+package client_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+func TestMetrics(t *testing.T) {
+	vm := jdwptest.NewVM()
+	newProgram(vm)
+	m := client.NewMetrics()
+	c, err := client.New(jdwptest.NewAgent(vm).Pipe(), client.WithObserver(m), client.WithLogger(client.NopLogger))
+	require.NoError(t, err)
+	defer c.Close()
+
+	cls := classBySignature(t, c, "Lcom/example/Main;")
+	_, err = cls.Methods(c)
+	require.NoError(t, err)
+	_, err = cls.Methods(c)
+	require.NoError(t, err)
+	_, err = client.ClassId(0xdead).Methods(c)
+	assert.Equal(t, client.ErrInvalidClass, err)
+
+	methods := m.Command(client.ReferenceType, client.ReferenceTypeMethods)
+	assert.Equal(t, uint64(3), methods.Sent)
+	assert.Equal(t, map[uint16]uint64{0: 2, 21: 1}, methods.Replies)
+	var replied uint64
+	for _, n := range methods.Buckets {
+		replied += n
+	}
+	assert.Equal(t, uint64(3), replied+methods.Overflow)
+
+	// Along with the ID sizes negotiated by New
+	assert.Equal(t, uint64(1), m.Command(client.VirtualMachine, client.VirtualMachineIDSizes).Sent)
+	assert.Equal(t, uint64(5), m.Received("reply"))
+	assert.Equal(t, uint64(0), m.Received("event"))
+
+	var out bytes.Buffer
+	require.NoError(t, m.WritePrometheus(&out))
+	labels := fmt.Sprintf(`set="%d",command="%d"`, client.ReferenceType, client.ReferenceTypeMethods)
+	text := out.String()
+	assert.Contains(t, text, "# TYPE jdwp_commands_sent_total counter\n")
+	assert.Contains(t, text, "jdwp_commands_sent_total{"+labels+"} 3\n")
+	assert.Contains(t, text, "jdwp_replies_total{"+labels+`,error="0"} 2`+"\n")
+	assert.Contains(t, text, "jdwp_replies_total{"+labels+`,error="21"} 1`+"\n")
+	assert.Contains(t, text, "jdwp_command_duration_seconds_bucket{"+labels+`,le="+Inf"} 3`+"\n")
+	assert.Contains(t, text, "jdwp_command_duration_seconds_count{"+labels+"} 3\n")
+	assert.Contains(t, text, `jdwp_packets_received_total{kind="reply"} 5`+"\n")
+}
+
+// recordingLogger keeps every line logged.
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) log(level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) { l.log("debug", format, args...) }
+func (l *recordingLogger) Warnf(format string, args ...interface{})  { l.log("warn", format, args...) }
+func (l *recordingLogger) Errorf(format string, args ...interface{}) { l.log("error", format, args...) }
+
+func (l *recordingLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+func TestWithLogger(t *testing.T) {
+	vm := jdwptest.NewVM()
+	p := newProgram(vm)
+	log := &recordingLogger{}
+	c, err := client.New(jdwptest.NewAgent(vm).Pipe(), client.WithLogger(log))
+	require.NoError(t, err)
+	defer c.Close()
+
+	cls := classBySignature(t, c, "Lcom/example/Main;")
+	_, err = c.Call(client.Thread, client.ThreadSuspend, c.IDSizes().Seq().ThreadId(p.thread.Id).Marshal())
+	require.NoError(t, err)
+	frames, err := p.thread.Id.Frames(c, 0, 1)
+	require.NoError(t, err)
+	run := method(t, c, cls, "run")
+	vars, err := run.MethodId.VariableTable(c)
+	require.NoError(t, err)
+	// A variable whose scope ends before the frame's location is skipped
+	stale := vars.Variables[0]
+	stale.Name, stale.Length = "stale", 1
+	_, err = frames[0].GetValues(c, append(vars.Variables, stale)...)
+	require.NoError(t, err)
+
+	text := log.String()
+	assert.Contains(t, text, "debug jdwp sending 1.7 ")
+	assert.Contains(t, text, "debug jdwp read reply: ")
+	assert.Contains(t, text, "debug requesting variable name, ")
+	assert.Contains(t, text, "warn skipping variable stale - not in legal scope")
+}
+
+func TestCommandBodyErrors(t *testing.T) {
+	vm := jdwptest.NewVM()
+	vm.Sizes = client.IDSizes{FieldIDSize: 4, MethodIDSize: 4, ObjectIDSize: 4, ReferenceTypeIDSize: 4, FrameIDSize: 4}
+	newProgram(vm)
+	m := client.NewMetrics()
+	c, err := client.New(jdwptest.NewAgent(vm).Pipe(), client.WithObserver(m), client.WithLogger(client.NopLogger))
+	require.NoError(t, err)
+	defer c.Close()
+
+	s := c.IDSizes().Seq().Int(1).ObjectId(1 << 40).Int(2)
+	if assert.Error(t, s.Err()) {
+		assert.Contains(t, s.Err().Error(), "does not fit in 4 bytes")
+	}
+
+	// The command is not sent at all
+	_, _, err = client.ObjectId(1 << 40).ReferenceType(c)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(0), m.Command(client.ObjectReference, client.ObjectReferenceReferenceType).Sent)
+}
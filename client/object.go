@@ -4,12 +4,6 @@ import (
 	"fmt"
 )
 
-const (
-	ObjectReference              = CommandSet(9)
-	ObjectReferenceReferenceType = Command(1)
-	ObjectReferenceClassObject   = Command(11)
-)
-
 type ObjectId uint64
 
 func (o ObjectId) Tag() Tag {
@@ -42,34 +36,29 @@ func (o ObjectId) RecoverValue(c Client) (interface{}, error) {
 }
 
 func (o ObjectId) ReferenceType(c Client) (TypeTag, ClassId, error) {
-	res, err := c.Call(ObjectReference, ObjectReferenceReferenceType,
-		Seq().ReferenceTypeId(ReferenceTypeId(o)).Marshal())
+	res, err := call(c, ObjectReference, ObjectReferenceReferenceType, c.IDSizes().Seq().ObjectId(o))
 	if err != nil {
 		return 0, 0, err
 	}
-	if res.ErrCode != 0 {
-		return 0, 0, lookupError(res.ErrCode)
-	}
 	var tv struct {
 		RTT TypeTag
 		Ref ClassId
 	}
-	err = Parse(res.Data, &tv)
+	err = c.IDSizes().Parse(res.Data, &tv)
 	return tv.RTT, tv.Ref, err
 }
 
-func (o ObjectId) ClassObject(c Client) (ClassId, error) {
-	res, err := c.Call(ObjectReference, ObjectReferenceClassObject,
-		Seq().ReferenceTypeId(ReferenceTypeId(o)).Marshal())
+// ClassObject returns the instance of java.lang.Class for the object's type.
+func (o ObjectId) ClassObject(c Client) (ClassObjectId, error) {
+	_, ref, err := o.ReferenceType(c)
 	if err != nil {
 		return 0, err
 	}
-	if res.ErrCode != 0 {
-		return 0, lookupError(res.ErrCode)
+	r, err := ref.ClassObject(c)
+	if err != nil {
+		return 0, err
 	}
-	var ref ClassId
-	err = Parse(res.Data, &ref)
-	return ref, err
+	return r.ClassObject, nil
 }
 
 type Class struct {
@@ -78,36 +67,48 @@ type Class struct {
 	Fields    []Field
 }
 
+// RecoverClass fetches the class's signature and fields together, in a single batch.
 func (id ClassId) RecoverClass(c Client) (*Class, error) {
-	sig, err := id.Signature(c)
+	b := NewBatch(c)
+	sig, fs := id.signature(b), id.fields(b)
+	b.Run()
+	signature, err := sig()
 	if err != nil {
 		return nil, err
 	}
-	fs, err := id.Fields(c)
+	fields, err := fs()
 	if err != nil {
 		return nil, err
 	}
 	return &Class{
 		ClassId:   id,
-		Signature: sig,
-		Fields:    fs,
+		Signature: signature,
+		Fields:    fields,
 	}, nil
 }
 
 func (id ClassId) Fields(c Client) ([]Field, error) {
-	r, err := c.Call(ReferenceType, ReferenceTypeFields, Seq().ReferenceTypeId(ReferenceTypeId(id)).Marshal())
-	if err != nil {
-		return nil, err
-	}
-	if r.ErrCode != 0 {
-		return nil, lookupError(r.ErrCode)
-	}
-	var res struct {
-		Count  int
-		Fields []Field `jdwp:"counter:Count"`
+	b := NewBatch(c)
+	fs := id.fields(b)
+	b.Run()
+	return fs()
+}
+
+// fields queues the Fields command on b, returning a function that parses its reply once b
+// has run.
+func (id ClassId) fields(b *Batch) func() ([]Field, error) {
+	bc := b.add(ReferenceType, ReferenceTypeFields, b.c.IDSizes().Seq().ReferenceTypeId(ReferenceTypeId(id)))
+	return func() ([]Field, error) {
+		if bc.Err != nil {
+			return nil, bc.Err
+		}
+		var res struct {
+			Count  int
+			Fields []Field `jdwp:"counter:Count"`
+		}
+		err := b.c.IDSizes().Parse(bc.Reply.Data, &res)
+		return res.Fields, err
 	}
-	err = Parse(r.Data, &res)
-	return res.Fields, err
 }
 
 type FieldId uint64
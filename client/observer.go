@@ -0,0 +1,52 @@
+package client
+
+import (
+	"time"
+)
+
+// PacketInfo describes a packet without its data.
+type PacketInfo struct {
+	Header
+	Set     CommandSet // for a command
+	Command Command    // for a command
+	ErrCode uint16     // for a reply
+}
+
+// Observer is told about the traffic on a connection, for instance to keep Metrics. It is
+// called from the goroutines sending and reading packets, so should return promptly.
+type Observer interface {
+	// PacketSent is called as each command is written.
+	PacketSent(p PacketInfo)
+	// PacketReceived is called as each packet is read, before it is routed.
+	PacketReceived(p PacketInfo)
+	// CommandReplied is called as the reply to a command arrives, with the time since the
+	// command was sent. Replies that nobody is waiting for are not included.
+	CommandReplied(set CommandSet, cmd Command, errCode uint16, rtt time.Duration)
+}
+
+// WithObserver adds an Observer; any number may be added.
+func WithObserver(o Observer) Option {
+	return func(c *client) {
+		c.observers = append(c.observers, o)
+	}
+}
+
+type observers []Observer
+
+func (os observers) sent(p PacketInfo) {
+	for _, o := range os {
+		o.PacketSent(p)
+	}
+}
+
+func (os observers) received(p PacketInfo) {
+	for _, o := range os {
+		o.PacketReceived(p)
+	}
+}
+
+func (os observers) replied(set CommandSet, cmd Command, errCode uint16, rtt time.Duration) {
+	for _, o := range os {
+		o.CommandReplied(set, cmd, errCode, rtt)
+	}
+}
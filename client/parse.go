@@ -5,36 +5,60 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
 	"strings"
-
-	"github.com/sirupsen/logrus"
 )
 
 type Marshaller interface {
 	Marshal(io.Writer) error
 }
 
-func Parse(data []byte, into interface{}) error {
-	buf := bytes.NewBuffer(data)
-	err := ParseBuf(buf, reflect.ValueOf(into), nil, nil)
-	if err != nil {
-		return err
-	}
-
-	if buf.Len() > 0 {
-		return fmt.Errorf("unread bytes at the end of the buffer: %d remain", buf.Len())
-	}
+// Unmarshaller is implemented by types that read themselves, such as those whose layout
+// depends on what has already been read.
+type Unmarshaller interface {
+	Unmarshal(io.Reader) error
+}
 
-	return nil
+// Parse decodes data into the structure pointed to by into, assuming DefaultIDSizes. It fails
+// with a *ParseError.
+func Parse(data []byte, into interface{}) error {
+	return DefaultIDSizes.Parse(data, into)
 }
 
 var (
 	interfaceFactories = map[reflect.Type]func(io.Reader, reflect.Value) error{}
 )
 
+// ParseBuf reads into from buf, laid out as JDWP lays out each kind: integers, floats and
+// booleans big-endian at their natural sizes, with int as a four-byte int, strings preceded by
+// their length, and IDs at the sizes the reader was made with. A slice field has as many
+// elements as the field named by its counter tag holds, and a slice within a slice is preceded
+// by its own count; an array has its fixed length. An interface is filled in by its factory.
+//
+// An error is returned as a *ParseError, giving the path to the value that could not be read.
 func ParseBuf(buf io.Reader, into reflect.Value, parent *reflect.Value, parentField *reflect.StructField) error {
+	start := offsetOf(buf)
+	err := parseBuf(buf, into, parent, parentField)
+	if _, ok := err.(*ParseError); err != nil && !ok {
+		err = &ParseError{Offset: start, Err: err}
+	}
+	return err
+}
+
+func parseBuf(buf io.Reader, into reflect.Value, parent *reflect.Value, parentField *reflect.StructField) error {
 	t := into.Type()
+	if size, ok := idSize(sizesOf(buf), t, parentField); ok {
+		id, err := parseId(buf, size)
+		if err != nil {
+			return err
+		}
+		into.SetUint(id)
+		return nil
+	}
+	if u, ok := addressOf(into).(Unmarshaller); ok {
+		return u.Unmarshal(buf)
+	}
 	switch t.Kind() {
 	case reflect.Ptr:
 		return ParseBuf(buf, into.Elem(), nil, nil)
@@ -45,15 +69,36 @@ func ParseBuf(buf io.Reader, into reflect.Value, parent *reflect.Value, parentFi
 				continue
 			}
 			if err := ParseBuf(buf, f, &into, &tf); err != nil {
-				return err
+				return prefixPath(err, "."+tf.Name)
 			}
 		}
+		if p, ok := addressOf(into).(parsed); ok {
+			p.parsed()
+		}
 	case reflect.String:
 		str, err := parseString(buf)
 		if err != nil {
 			return err
 		}
 		into.SetString(str)
+	case reflect.Bool:
+		b, err := parseUint8(buf)
+		if err != nil {
+			return err
+		}
+		into.SetBool(b != 0)
+	case reflect.Int8:
+		var i8 int8
+		if err := binary.Read(buf, binary.BigEndian, &i8); err != nil {
+			return err
+		}
+		into.SetInt(int64(i8))
+	case reflect.Int16:
+		var i16 int16
+		if err := binary.Read(buf, binary.BigEndian, &i16); err != nil {
+			return err
+		}
+		into.SetInt(int64(i16))
 	case reflect.Int, reflect.Int32:
 		i32, err := parseInt32(buf)
 		if err != nil {
@@ -66,6 +111,12 @@ func ParseBuf(buf io.Reader, into reflect.Value, parent *reflect.Value, parentFi
 			return err
 		}
 		into.SetUint(uint64(i8))
+	case reflect.Uint16:
+		var u16 uint16
+		if err := binary.Read(buf, binary.BigEndian, &u16); err != nil {
+			return err
+		}
+		into.SetUint(uint64(u16))
 	case reflect.Uint32:
 		i32, err := parseUint32(buf)
 		if err != nil {
@@ -84,35 +135,207 @@ func ParseBuf(buf io.Reader, into reflect.Value, parent *reflect.Value, parentFi
 			return err
 		}
 		into.SetInt(i64)
+	case reflect.Float32:
+		bits, err := parseUint32(buf)
+		if err != nil {
+			return err
+		}
+		into.SetFloat(float64(math.Float32frombits(bits)))
+	case reflect.Float64:
+		bits, err := parseUint64(buf)
+		if err != nil {
+			return err
+		}
+		into.SetFloat(math.Float64frombits(bits))
 	case reflect.Slice:
-		// Get the count field
-		tags := parentField.Tag.Get("jdwp")
-		countName := findKey(tags, "counter")
-		count := int(parent.FieldByName(countName).Int())
-		// Make a slice of that length
-		slice := reflect.MakeSlice(t, count, count)
-
-		for i := 0; i < count; i++ {
-			err := ParseBuf(buf, slice.Index(i), nil, nil)
-			if err != nil {
-				return err
-			}
+		if parent == nil || parentField == nil {
+			return fmt.Errorf("slice %s has no counter", t)
+		}
+		count, err := counterOf(*parent, *parentField)
+		if err != nil {
+			return err
+		}
+		slice, err := parseElems(buf, t, count)
+		if err != nil {
+			return err
 		}
 		into.Set(slice)
+	case reflect.Array:
+		for i := 0; i < into.Len(); i++ {
+			if err := parseElem(buf, into.Index(i)); err != nil {
+				return prefixPath(err, fmt.Sprintf("[%d]", i))
+			}
+		}
 	case reflect.Interface:
-		logrus.Debug("into is ", into, " and type is ", into.Type())
-		if parser, ok := interfaceFactories[into.Type()]; !ok {
+		parser, ok := registryOfReader(buf).factory(into.Type())
+		if !ok {
 			return fmt.Errorf("cannot instantiate type %s", into.Type())
-		} else {
-			return parser(buf, into)
 		}
+		if err := parser(buf, into); err != nil {
+			if !into.IsNil() {
+				// The factory set the value it failed to read
+				return prefixPath(err, ".("+typeName(into.Elem().Type())+")")
+			}
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot parse %s, of kind %s", t, t.Kind())
+	}
+
+	return nil
+}
+
+// counterOf returns the number of elements of the slice field f, held in the field of parent
+// named by its counter tag.
+func counterOf(parent reflect.Value, f reflect.StructField) (int, error) {
+	counter := parent.FieldByName(findKey(f.Tag.Get("jdwp"), "counter"))
+	if !counter.IsValid() {
+		return 0, fmt.Errorf("slice %s.%s has no counter", parent.Type(), f.Name)
+	}
+	var n int64
+	switch counter.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = counter.Int()
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if counter.Uint() > math.MaxInt32 {
+			return 0, fmt.Errorf("%s.%s has %d elements", parent.Type(), f.Name, counter.Uint())
+		}
+		n = int64(counter.Uint())
 	default:
-		logrus.Warn("warning: field ", into, " has unknown kind ", into.Kind())
+		return 0, fmt.Errorf("counter of %s.%s is not an integer", parent.Type(), f.Name)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("%s.%s has %d elements", parent.Type(), f.Name, n)
+	}
+	return int(n), nil
+}
+
+var (
+	byteType         = reflect.TypeOf(byte(0))
+	unmarshallerType = reflect.TypeOf((*Unmarshaller)(nil)).Elem()
+)
+
+// parseElems reads a slice of type t with count elements. The count is not trusted with an
+// allocation: the slice grows as its elements are read, and a short buffer fails soon enough.
+func parseElems(buf io.Reader, t reflect.Type, count int) (reflect.Value, error) {
+	if t.Elem() == byteType {
+		// Such as Method.Bytecodes, read in one go
+		b, err := readBytes(buf, int64(count))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b).Convert(t), nil
 	}
+	if t.Elem().Size() == 0 && !reflect.PtrTo(t.Elem()).Implements(unmarshallerType) {
+		// Elements that take no bytes cannot run short, however many there are
+		return reflect.MakeSlice(t, count, count), nil
+	}
+	slice := reflect.MakeSlice(t, 0, 0)
+	for i := 0; i < count; i++ {
+		elem := reflect.New(t.Elem()).Elem()
+		if err := parseElem(buf, elem); err != nil {
+			return reflect.Value{}, prefixPath(err, fmt.Sprintf("[%d]", i))
+		}
+		slice = reflect.Append(slice, elem)
+	}
+	return slice, nil
+}
 
+// parseElem reads an element of a slice or array. One that is itself a slice, having no field
+// to hold its counter, is preceded by its count.
+func parseElem(buf io.Reader, into reflect.Value) error {
+	if into.Kind() != reflect.Slice {
+		return ParseBuf(buf, into, nil, nil)
+	}
+	count, err := parseInt32(buf)
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("%s has %d elements", into.Type(), count)
+	}
+	slice, err := parseElems(buf, into.Type(), int(count))
+	if err != nil {
+		return err
+	}
+	into.Set(slice)
 	return nil
 }
 
+// ParseError reports a value that could not be parsed. Its Path leads to the value from the
+// type being parsed into, as in Composite.Events[1].(*EventBreakpoint).Location.Index, and
+// Near holds the bytes around its Offset.
+type ParseError struct {
+	Path   string
+	Offset int    // of the value, from the start of the data being parsed
+	Start  int    // the offset of Near
+	Near   []byte // the data around Offset, where it is known
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("parsing %s at byte %d: %v", e.Path, e.Offset, e.Err)
+	at := e.Offset - e.Start
+	if e.Near == nil || at < 0 || at > len(e.Near) {
+		return msg
+	}
+	return fmt.Sprintf("%s [% x | % x]", msg, e.Near[:at], e.Near[at:])
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// near is how many bytes either side of a ParseError's offset are kept.
+const near = 8
+
+// withData fills in the bytes around the error's offset in data.
+func (e *ParseError) withData(data []byte) *ParseError {
+	e.Start = e.Offset - near
+	if e.Start < 0 {
+		e.Start = 0
+	}
+	end := e.Offset + near
+	if end > len(data) {
+		end = len(data)
+	}
+	if e.Start <= end {
+		e.Near = data[e.Start:end]
+	}
+	return e
+}
+
+// prefixPath adds the step leading to the value that failed to parse, as the error returns
+// through the values enclosing it.
+func prefixPath(err error, step string) error {
+	if pe, ok := err.(*ParseError); ok {
+		pe.Path = step + pe.Path
+	}
+	return err
+}
+
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		return "*" + typeName(t.Elem())
+	}
+	if t.Name() != "" {
+		return t.Name()
+	}
+	return t.String()
+}
+
+// parsed is implemented by types with state to fill in once their fields have been read.
+type parsed interface {
+	parsed()
+}
+
+func addressOf(v reflect.Value) interface{} {
+	if !v.CanAddr() {
+		return nil
+	}
+	return v.Addr().Interface()
+}
+
 func findKey(tags string, key string) string {
 	for _, item := range strings.Split(tags, " ") {
 		kv := strings.SplitN(item, ":", 2)
@@ -128,14 +351,28 @@ func parseString(buf io.Reader) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	bs := make([]byte, l)
-	_, err = io.ReadFull(buf, bs)
+	if l < 0 {
+		return "", fmt.Errorf("string of %d bytes", l)
+	}
+	bs, err := readBytes(buf, int64(l))
 	if err != nil {
 		return "", err
 	}
 	return string(bs), nil
 }
 
+// readBytes reads n bytes, allocating as they arrive rather than trusting n.
+func readBytes(buf io.Reader, n int64) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := io.CopyN(&b, buf, n); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
 func parseInt64(buf io.Reader) (int64, error) {
 	var i int64
 	err := binary.Read(buf, binary.BigEndian, &i)
@@ -166,6 +403,24 @@ func parseUint8(buf io.Reader) (uint8, error) {
 	return i, err
 }
 
+// alternatives returns a factory for an interface whose implementations are selected by the
+// byte preceding them, as in a Select of the JDWP specification.
+func alternatives(alts map[uint8]func() interface{}) func(io.Reader, reflect.Value) error {
+	return func(buf io.Reader, into reflect.Value) error {
+		selector, err := parseUint8(buf)
+		if err != nil {
+			return err
+		}
+		newAlt, ok := alts[selector]
+		if !ok {
+			return fmt.Errorf("%s has no alternative %d", into.Type(), selector)
+		}
+		v := reflect.ValueOf(newAlt())
+		into.Set(v)
+		return ParseBuf(buf, v, nil, nil)
+	}
+}
+
 func RegisterFactory(slice interface{}, f func(io.Reader, reflect.Value) error) {
 	t := reflect.ValueOf(slice).Type().Elem()
 	interfaceFactories[t] = f
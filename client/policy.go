@@ -0,0 +1,160 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Policy decides whether a client may send a command. It is consulted before anything is
+// written, so a refused command never reaches the VM.
+type Policy interface {
+	// Permit returns nil to let the command be sent, or an error, typically a *RefusedError,
+	// to refuse it.
+	Permit(set CommandSet, cmd Command, data []byte) error
+}
+
+// WithPolicy has the client consult p before sending each command.
+func WithPolicy(p Policy) Option {
+	return func(c *client) {
+		c.policy = p
+	}
+}
+
+// ErrRefused matches, using errors.Is, every *RefusedError.
+var ErrRefused = errors.New("jdwp command refused")
+
+// RefusedError reports a command that a Policy would not let the client send.
+type RefusedError struct {
+	Set     CommandSet
+	Command Command
+	Reason  string
+}
+
+func (e *RefusedError) Error() string {
+	return fmt.Sprintf("jdwp command %d.%d refused: %s", e.Set, e.Command, e.Reason)
+}
+
+func (e *RefusedError) Is(target error) bool {
+	return target == ErrRefused
+}
+
+// mutating lists every command in the JDWP specification, as a test checks, saying whether it
+// can change the state of the VM or of the program running in it. Reading state, setting and clearing event
+// requests, and suspending and resuming single threads do not count as changes.
+var mutating = map[CommandKey]bool{
+	{VirtualMachine, VirtualMachineVersion}:               false,
+	{VirtualMachine, VirtualMachineClassesBySignature}:    false,
+	{VirtualMachine, VirtualMachineAllClasses}:            false,
+	{VirtualMachine, VirtualMachineAllThreads}:            false,
+	{VirtualMachine, VirtualMachineTopLevelThreadGroups}:  false,
+	{VirtualMachine, VirtualMachineDispose}:               false,
+	{VirtualMachine, VirtualMachineIDSizes}:               false,
+	{VirtualMachine, VirtualMachineSuspend}:               true,
+	{VirtualMachine, VirtualMachineResume}:                false,
+	{VirtualMachine, VirtualMachineExit}:                  true,
+	{VirtualMachine, VirtualMachineCreateString}:          true,
+	{VirtualMachine, VirtualMachineCapabilities}:          false,
+	{VirtualMachine, VirtualMachineClassPaths}:            false,
+	{VirtualMachine, VirtualMachineDisposeObjects}:        false,
+	{VirtualMachine, VirtualMachineHoldEvents}:            true,
+	{VirtualMachine, VirtualMachineReleaseEvents}:         false,
+	{VirtualMachine, VirtualMachineCapabilitiesNew}:       false,
+	{VirtualMachine, VirtualMachineRedefineClasses}:       true,
+	{VirtualMachine, VirtualMachineSetDefaultStratum}:     true,
+	{VirtualMachine, VirtualMachineAllClassesWithGeneric}: false,
+	{VirtualMachine, VirtualMachineInstanceCounts}:        false,
+	{VirtualMachine, VirtualMachineAllModules}:            false,
+
+	// ReferenceType: Signature, ClassLoader, Modifiers, Fields, Methods, GetValues,
+	// SourceFile, NestedTypes, Status, Interfaces, ClassObject, SourceDebugExtension,
+	// SignatureWithGeneric, FieldsWithGeneric, MethodsWithGeneric, Instances,
+	// ClassFileVersion, ConstantPool, Module
+	{ReferenceType, 1}: false, {ReferenceType, 2}: false, {ReferenceType, 3}: false,
+	{ReferenceType, 4}: false, {ReferenceType, 5}: false, {ReferenceType, 6}: false,
+	{ReferenceType, 7}: false, {ReferenceType, 8}: false, {ReferenceType, 9}: false,
+	{ReferenceType, 10}: false, {ReferenceType, 11}: false, {ReferenceType, 12}: false,
+	{ReferenceType, 13}: false, {ReferenceType, 14}: false, {ReferenceType, 15}: false,
+	{ReferenceType, 16}: false, {ReferenceType, 17}: false, {ReferenceType, 18}: false,
+	{ReferenceType, 19}: false,
+
+	// ClassType: Superclass, SetValues, InvokeMethod, NewInstance
+	{ClassType, 1}: false, {ClassType, 2}: true, {ClassType, 3}: true, {ClassType, 4}: true,
+	// ArrayType: NewInstance
+	{ArrayType, 1}: true,
+	// InterfaceType: InvokeMethod
+	{InterfaceType, 1}: true,
+
+	// Method: LineTable, VariableTable, Bytecodes, IsObsolete, VariableTableWithGeneric
+	{Method, 1}: false, {Method, 2}: false, {Method, 3}: false, {Method, 4}: false, {Method, 5}: false,
+
+	// ObjectReference: ReferenceType, GetValues, SetValues, MonitorInfo, InvokeMethod,
+	// DisableCollection, EnableCollection, IsCollected, ReferringObjects
+	{ObjectReference, 1}: false, {ObjectReference, 2}: false, {ObjectReference, 3}: true,
+	{ObjectReference, 5}: false, {ObjectReference, 6}: true, {ObjectReference, 7}: false,
+	{ObjectReference, 8}: false, {ObjectReference, 9}: false, {ObjectReference, 10}: false,
+
+	// StringReference: Value
+	{StringReference, 1}: false,
+
+	// ThreadReference: Name, Suspend, Resume, Status, ThreadGroup, Frames, FrameCount,
+	// OwnedMonitors, CurrentContendedMonitor, Stop, Interrupt, SuspendCount,
+	// OwnedMonitorsStackDepthInfo, ForceEarlyReturn
+	{Thread, 1}: false, {Thread, 2}: false, {Thread, 3}: false, {Thread, 4}: false,
+	{Thread, 5}: false, {Thread, 6}: false, {Thread, 7}: false, {Thread, 8}: false,
+	{Thread, 9}: false, {Thread, 10}: true, {Thread, 11}: true, {Thread, 12}: false,
+	{Thread, 13}: false, {Thread, 14}: true,
+
+	// ThreadGroupReference: Name, Parent, Children
+	{ThreadGroupReference, 1}: false, {ThreadGroupReference, 2}: false, {ThreadGroupReference, 3}: false,
+	// ArrayReference: Length, GetValues, SetValues
+	{ArrayReference, 1}: false, {ArrayReference, 2}: false, {ArrayReference, 3}: true,
+	// ClassLoaderReference: VisibleClasses
+	{ClassLoaderReference, 1}: false,
+
+	// EventRequest.Set is mutating only when it would suspend every thread; see ReadOnly
+	{EventRequest, Set}:                 false,
+	{EventRequest, Clear}:               false,
+	{EventRequest, ClearAllBreakPoints}: false,
+
+	// StackFrame: GetValues, SetValues, ThisObject, PopFrames
+	{StackFrame, 1}: false, {StackFrame, 2}: true, {StackFrame, 3}: false, {StackFrame, 4}: true,
+	// ClassObjectReference: ReflectedType
+	{ClassObjectReference, 1}: false,
+	// ModuleReference: Name, ClassLoader
+	{ModuleReference, 1}: false, {ModuleReference, 2}: false,
+}
+
+// Mutating says whether a command can change the state of the VM. Commands missing from the
+// JDWP specification, such as those of vendor extensions, are assumed to.
+func Mutating(set CommandSet, cmd Command) bool {
+	m, ok := mutating[CommandKey{set, cmd}]
+	return m || !ok
+}
+
+// readOnly is the Policy returned by ReadOnly.
+type readOnly map[CommandKey]bool
+
+// ReadOnly is a Policy refusing every command that is Mutating, along with event requests
+// that would suspend every thread, unless the command is allowed. Allowing
+// VirtualMachine.Suspend also allows event requests to suspend every thread.
+func ReadOnly(allow ...CommandKey) Policy {
+	p := readOnly{}
+	for _, k := range allow {
+		p[k] = true
+	}
+	return p
+}
+
+func (p readOnly) Permit(set CommandSet, cmd Command, data []byte) error {
+	if p[CommandKey{set, cmd}] {
+		return nil
+	}
+	if Mutating(set, cmd) {
+		return &RefusedError{Set: set, Command: cmd, Reason: "it may change the state of the VM"}
+	}
+	if set == EventRequest && cmd == Set && len(data) >= 2 && SuspendPolicy(data[1]) == SuspendPolicyAll &&
+		!p[CommandKey{VirtualMachine, VirtualMachineSuspend}] {
+		return &RefusedError{Set: set, Command: cmd, Reason: "the event request would suspend every thread"}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+func TestMutating(t *testing.T) {
+	for _, k := range []client.CommandKey{
+		{Set: client.VirtualMachine, Command: client.VirtualMachineExit},
+		{Set: client.VirtualMachine, Command: client.VirtualMachineSuspend},
+		{Set: client.VirtualMachine, Command: client.VirtualMachineRedefineClasses},
+		{Set: client.ClassType, Command: 2},       // SetValues
+		{Set: client.ClassType, Command: 3},       // InvokeMethod
+		{Set: client.ObjectReference, Command: 6}, // InvokeMethod
+		{Set: client.Thread, Command: 10},         // Stop
+		{Set: client.Thread, Command: 11},         // Interrupt
+		{Set: client.Thread, Command: 14},         // ForceEarlyReturn
+		{Set: client.StackFrame, Command: 2},      // SetValues
+		{Set: client.StackFrame, Command: 4},      // PopFrames
+		{Set: 199, Command: 1},                    // unknown to the specification
+	} {
+		assert.True(t, client.Mutating(k.Set, k.Command), "%+v", k)
+	}
+	for _, k := range []client.CommandKey{
+		{Set: client.VirtualMachine, Command: client.VirtualMachineVersion},
+		{Set: client.VirtualMachine, Command: client.VirtualMachineAllModules},
+		{Set: client.ReferenceType, Command: client.ReferenceTypeMethods},
+		{Set: client.Thread, Command: client.ThreadSuspend},
+		{Set: client.Thread, Command: client.ThreadResume},
+		{Set: client.StackFrame, Command: client.StackFrameGetValues},
+		{Set: client.EventRequest, Command: client.Set},
+	} {
+		assert.False(t, client.Mutating(k.Set, k.Command), "%+v", k)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	m := client.NewMetrics()
+	c, err := client.New(jdwptest.NewAgent(p.vm).Pipe(),
+		client.WithPolicy(client.ReadOnly(client.CommandKey{Set: client.Thread, Command: 11})),
+		client.WithObserver(m))
+	require.NoError(t, err)
+	defer c.Close()
+
+	// Reading is allowed
+	cls := classBySignature(t, c, "Lcom/example/Main;")
+	run := method(t, c, cls, "run")
+
+	// Changing anything is refused before it is sent
+	_, err = c.Call(client.VirtualMachine, client.VirtualMachineExit, c.IDSizes().Seq().Int(0).Marshal())
+	assert.True(t, errors.Is(err, client.ErrRefused))
+	var refused *client.RefusedError
+	if assert.True(t, errors.As(err, &refused)) {
+		assert.Equal(t, client.VirtualMachine, refused.Set)
+		assert.Equal(t, client.VirtualMachineExit, refused.Command)
+	}
+	_, err = c.Call(client.VirtualMachine, client.VirtualMachineSuspend, nil)
+	assert.True(t, errors.Is(err, client.ErrRefused))
+	assert.Zero(t, m.Command(client.VirtualMachine, client.VirtualMachineExit).Sent)
+	assert.Zero(t, m.Command(client.VirtualMachine, client.VirtualMachineSuspend).Sent)
+
+	// So is an event request that would suspend every thread, but not one suspending its own
+	location := client.NewLocation(run.MethodId, 8)
+	_, err = client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyAll).
+		WithMod(client.ModKindLocation).WithLocation(location).Set(c)
+	assert.True(t, errors.Is(err, client.ErrRefused))
+	_, err = client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyEventThread).
+		WithMod(client.ModKindLocation).WithLocation(location).Set(c)
+	assert.NoError(t, err)
+	assert.Len(t, p.vm.Requests(), 1)
+
+	// Allowed commands reach the VM, which does not implement Interrupt
+	r, err := c.Call(client.Thread, 11, c.IDSizes().Seq().ThreadId(p.thread.Id).Marshal())
+	require.NoError(t, err)
+	assert.Equal(t, client.ErrNotImplemented.(client.JdwpError).Code, r.ErrCode)
+}
+
+func TestReadOnlyProxy(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	up, err := client.New(jdwptest.NewAgent(p.vm).Pipe(), client.WithPolicy(client.ReadOnly()))
+	require.NoError(t, err)
+	defer up.Close()
+	proxy := client.NewProxy(up)
+	defer proxy.Close()
+
+	c := debugger(t, proxy)
+	defer c.Close()
+	r, err := c.Call(client.VirtualMachine, client.VirtualMachineSuspend, nil)
+	require.NoError(t, err)
+	assert.Equal(t, client.ErrNotImplemented.(client.JdwpError).Code, r.ErrCode)
+	assert.Zero(t, p.thread.SuspendCount())
+}
@@ -0,0 +1,482 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Proxy shares one VM among any number of debuggers. Each downstream connection speaks JDWP
+// as though it were attached to the VM alone: its commands are forwarded upstream under IDs of
+// the Proxy's choosing, and the replies are returned under the IDs it used. Events are routed
+// to the debugger that set the request they answer, and VM-wide events, which answer no
+// request, go to all of them.
+//
+// A few commands are handled by the Proxy, so that one debugger cannot upset the others:
+// VirtualMachine.Dispose disconnects only that debugger, and EventRequest.ClearAllBreakpoints
+// clears only its breakpoints. When a debugger disconnects, the event requests it set are
+// cleared. Suspensions and resumptions are not tracked, and are shared by every debugger; when
+// a Composite that suspended threads is split, only one debugger is told of the suspension.
+// Events and commands from the VM are queued for each debugger, so that one that is slow to
+// read them holds up nobody else; past DefaultEventQueueSize, it loses the oldest.
+// Commands that the upstream client's Policy refuses are answered with ErrNotImplemented.
+//
+// The Proxy consumes the upstream client's Events and Commands.
+type Proxy struct {
+	c    Client
+	log  Logger
+	done chan struct{}
+	once sync.Once
+
+	mu         sync.Mutex
+	settled    *sync.Cond // signalled as EventRequest.Set commands are answered, and as the Proxy or upstream ends
+	setting    int        // EventRequest.Set commands awaiting their reply
+	downstream map[*downstream]bool
+	owners     map[int]owner // event request ID to the debugger that set it
+	listeners  map[net.Listener]bool
+}
+
+// owner records who set an event request.
+type owner struct {
+	d    *downstream
+	kind EventKind
+}
+
+// downstream is a debugger attached to a Proxy.
+type downstream struct {
+	conn      io.ReadWriteCloser
+	writing   sync.Mutex
+	id        uint32       // for commands sent to the debugger; accessed atomically
+	out       *packetQueue // commands awaiting writing to the debugger
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newDownstream(conn io.ReadWriteCloser, log Logger) *downstream {
+	return &downstream{
+		conn:   conn,
+		out:    newPacketQueue("debugger", DefaultEventQueueSize, DropOldest, log),
+		closed: make(chan struct{}),
+	}
+}
+
+// NewProxy starts passing events from c to the debuggers that the Proxy will serve.
+func NewProxy(c Client) *Proxy {
+	p := &Proxy{
+		c:          c,
+		log:        loggerOf(c),
+		done:       make(chan struct{}),
+		downstream: map[*downstream]bool{},
+		owners:     map[int]owner{},
+		listeners:  map[net.Listener]bool{},
+	}
+	p.settled = sync.NewCond(&p.mu)
+	go p.watchUpstream()
+	go p.forwardEvents()
+	go p.forwardCommands()
+	return p
+}
+
+// Serve accepts debuggers from l until it fails or the Proxy is closed.
+func (p *Proxy) Serve(l net.Listener) error {
+	p.mu.Lock()
+	if p.isClosed() {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+	p.listeners[l] = true
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.listeners, l)
+		p.mu.Unlock()
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if p.isClosed() {
+				return ErrClosed
+			}
+			return err
+		}
+		go p.ServeConn(conn)
+	}
+}
+
+// ListenAndServe listens on address for debuggers, and serves them.
+func (p *Proxy) ListenAndServe(network string, address string) error {
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	return p.Serve(l)
+}
+
+// ServeConn serves one debugger until it disconnects, disposes of the VM, or the Proxy is
+// closed.
+func (p *Proxy) ServeConn(conn io.ReadWriteCloser) error {
+	d := newDownstream(conn, p.log)
+	if err := answerHandshake(conn); err != nil {
+		conn.Close()
+		return err
+	}
+	p.mu.Lock()
+	if p.isClosed() {
+		p.mu.Unlock()
+		conn.Close()
+		return ErrClosed
+	}
+	p.downstream[d] = true
+	p.mu.Unlock()
+	defer func() {
+		d.close()
+		p.detach(d)
+	}()
+	go d.out.deliver(d.closed, func(packet interface{}) bool {
+		return d.send(packet.([]byte))
+	})
+
+	for {
+		header, pair, data, err := readPacket(conn, DefaultMaxPacketSize)
+		if err != nil {
+			if err == io.EOF || p.isClosed() {
+				return nil
+			}
+			return err
+		}
+		if header.IsReply() {
+			// Debuggers do not ask for replies to events
+			continue
+		}
+		if !p.command(d, header.Id, CommandSet(pair>>8), Command(pair&0xff), data) {
+			return nil
+		}
+	}
+}
+
+// Close disconnects every debugger and stops accepting more. The upstream client is left
+// for the caller to close.
+func (p *Proxy) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for l := range p.listeners {
+			l.Close()
+		}
+		for d := range p.downstream {
+			d.close()
+		}
+		p.settled.Broadcast()
+	})
+	return nil
+}
+
+// watchUpstream wakes anything waiting for EventRequest.Set replies once the upstream client
+// has ended, as those replies will never come.
+func (p *Proxy) watchUpstream() {
+	select {
+	case <-p.c.Done():
+	case <-p.done:
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.settled.Broadcast()
+}
+
+func (p *Proxy) isClosed() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// command handles a command from a debugger, returning false once the debugger has gone.
+func (p *Proxy) command(d *downstream, id Id, set CommandSet, cmd Command, data []byte) bool {
+	switch {
+	case set == VirtualMachine && cmd == VirtualMachineDispose:
+		// Only this debugger is leaving; the others keep the VM
+		p.detach(d)
+		d.reply(id, 0, nil)
+		d.close()
+		return false
+	case set == EventRequest && cmd == ClearAllBreakPoints:
+		d.reply(id, errorCode(p.clearRequests(d, EventKindBreakpoint)), nil)
+		return true
+	case set == EventRequest && cmd == Set:
+		p.mu.Lock()
+		p.setting++
+		p.mu.Unlock()
+	}
+
+	// The command is written here, so that the VM sees a debugger's commands in the order
+	// they were sent, but the reply is awaited separately, so that they may be pipelined.
+	upstream, ch, err := p.c.Send(set, cmd, data)
+	go func() {
+		defer p.c.Dispose(upstream)
+		var r *Reply
+		if err == nil {
+			r = <-ch
+		}
+		if set == EventRequest {
+			p.requestAnswered(d, cmd, data, r)
+		}
+		if r == nil {
+			if !errors.Is(err, ErrRefused) {
+				err = ErrVmDead
+			}
+			d.reply(id, errorCode(err), nil)
+			return
+		}
+		d.reply(id, r.ErrCode, r.Data)
+	}()
+	return true
+}
+
+// requestAnswered notes who owns the event requests set and cleared through the Proxy. The
+// reply to EventRequest.Set always precedes the first event for the request, so events
+// awaiting an owner wait for every Set in flight to be answered.
+func (p *Proxy) requestAnswered(d *downstream, cmd Command, data []byte, r *Reply) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch cmd {
+	case Set:
+		if r != nil && r.ErrCode == 0 && len(r.Data) >= 4 && len(data) >= 1 {
+			requestId := int(int32(binary.BigEndian.Uint32(r.Data)))
+			p.owners[requestId] = owner{d: d, kind: EventKind(data[0])}
+		}
+		p.setting--
+		p.settled.Broadcast()
+	case Clear:
+		if r != nil && r.ErrCode == 0 && len(data) >= 5 {
+			delete(p.owners, int(int32(binary.BigEndian.Uint32(data[1:]))))
+		}
+	}
+}
+
+// clearRequests clears the event requests that d has set, of one kind or, given
+// EventKind(0), of every kind.
+func (p *Proxy) clearRequests(d *downstream, kind EventKind) error {
+	p.mu.Lock()
+	var clear []EventRequestClear
+	for requestId, o := range p.owners {
+		if o.d == d && (kind == 0 || o.kind == kind) {
+			clear = append(clear, EventRequestClear{EventKind: o.kind, RequestId: requestId})
+			delete(p.owners, requestId)
+		}
+	}
+	p.mu.Unlock()
+	var first error
+	for _, e := range clear {
+		if err := e.Clear(p.c); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// detach forgets a debugger, clearing its event requests so that the VM no longer stops for
+// events that nobody will see.
+func (p *Proxy) detach(d *downstream) {
+	p.mu.Lock()
+	attached := p.downstream[d]
+	delete(p.downstream, d)
+	p.mu.Unlock()
+	if !attached {
+		return
+	}
+	if err := p.clearRequests(d, 0); err != nil {
+		p.log.Warnf("jdwp proxy could not clear the requests of a departed debugger: %v", err)
+	}
+}
+
+// owner finds the debugger that set an event request, waiting for any requests still being
+// set. It returns nil if the request belongs to nobody attached, or if the Proxy or the
+// upstream client has ended.
+func (p *Proxy) owner(requestId int) *downstream {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for {
+		if o, ok := p.owners[requestId]; ok {
+			return o.d
+		}
+		if p.setting == 0 || p.isClosed() || p.c.Err() != nil {
+			return nil
+		}
+		p.settled.Wait()
+	}
+}
+
+func (p *Proxy) attached() []*downstream {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ds := make([]*downstream, 0, len(p.downstream))
+	for d := range p.downstream {
+		ds = append(ds, d)
+	}
+	return ds
+}
+
+// forwardEvents splits each Composite among the debuggers that asked for its events.
+func (p *Proxy) forwardEvents() {
+	for e := range p.c.Events() {
+		if e.Command != CompositeCommands {
+			for _, d := range p.attached() {
+				d.command(e.Set, e.Command, e.Data)
+			}
+			continue
+		}
+		policy, events, err := registryOf(p.c).splitComposite(e.Data, p.c.IDSizes())
+		if err != nil {
+			p.log.Warnf("jdwp proxy passing on a composite event it cannot split: %v", err)
+			for _, d := range p.attached() {
+				d.command(e.Set, e.Command, e.Data)
+			}
+			continue
+		}
+		var order []*downstream
+		routed := map[*downstream][]rawEvent{}
+		route := func(d *downstream, e rawEvent) {
+			if _, ok := routed[d]; !ok {
+				order = append(order, d)
+			}
+			routed[d] = append(routed[d], e)
+		}
+		for _, ev := range events {
+			if ev.Kind == EventKindVM_DISCONNECTED {
+				p.Close()
+				return
+			}
+			if ev.Kind == EventKindSUSPENSION_EXPIRED {
+				// The upstream client's own business, which debuggers would not understand
+				continue
+			}
+			if ev.RequestId == 0 {
+				for _, d := range p.attached() {
+					route(d, ev)
+				}
+			} else if d := p.owner(ev.RequestId); d != nil {
+				route(d, ev)
+			} else {
+				p.log.Debugf("jdwp proxy dropping event for request %d, which no debugger owns", ev.RequestId)
+			}
+		}
+		for i, d := range order {
+			// The VM suspended once, so only one debugger is told to resume
+			part := policy
+			if i > 0 {
+				part = SuspendPolicyNone
+			}
+			d.command(EventCommandSet, CompositeCommands, joinComposite(part, routed[d]))
+		}
+		if len(order) == 0 && policy != SuspendPolicyNone {
+			p.resume(policy, events)
+		}
+	}
+	p.Close()
+}
+
+// resume undoes the suspension caused by events that nobody was sent.
+func (p *Proxy) resume(policy SuspendPolicy, events []rawEvent) {
+	var err error
+	if policy == SuspendPolicyAll {
+		_, err = call(p.c, VirtualMachine, VirtualMachineResume, p.c.IDSizes().Seq())
+	} else if len(events) > 0 {
+		if thread, ok := registryOf(p.c).thread(events[0], p.c.IDSizes()); ok {
+			_, err = call(p.c, Thread, ThreadResume, p.c.IDSizes().Seq().ThreadId(thread))
+		}
+	}
+	if err != nil {
+		p.log.Warnf("jdwp proxy could not resume after events that no debugger owns: %v", err)
+	}
+}
+
+// forwardCommands passes every other command from the VM to all the debuggers.
+func (p *Proxy) forwardCommands() {
+	for cmd := range p.c.Commands() {
+		for _, d := range p.attached() {
+			d.command(cmd.Set, cmd.Command, cmd.Data)
+		}
+	}
+}
+
+// command queues a command for the debugger, without waiting for it to be written.
+func (d *downstream) command(set CommandSet, cmd Command, data []byte) {
+	id := Id(atomic.AddUint32(&d.id, 1))
+	d.out.push(encodePacket(id, 0, uint16(set)<<8|uint16(cmd), data))
+}
+
+// reply sends a reply to the debugger. It is written at once, as only the command's own
+// goroutine waits on it.
+func (d *downstream) reply(id Id, errCode uint16, data []byte) {
+	d.send(encodePacket(id, FlagReply, errCode, data))
+}
+
+// send writes a packet to the debugger, reporting whether it could. A debugger that cannot be
+// written to is disconnected, which its reader will notice.
+func (d *downstream) send(packet []byte) bool {
+	d.writing.Lock()
+	defer d.writing.Unlock()
+	if _, err := d.conn.Write(packet); err != nil {
+		d.close()
+		return false
+	}
+	return true
+}
+
+func (d *downstream) close() {
+	d.closeOnce.Do(func() {
+		close(d.closed)
+		d.conn.Close()
+	})
+}
+
+func encodePacket(id Id, flags uint8, pair uint16, data []byte) []byte {
+	packet := make([]byte, HeaderLength, HeaderLength+len(data))
+	binary.BigEndian.PutUint32(packet[0:], uint32(HeaderLength+len(data)))
+	binary.BigEndian.PutUint32(packet[4:], uint32(id))
+	packet[8] = flags
+	binary.BigEndian.PutUint16(packet[9:], pair)
+	return append(packet, data...)
+}
+
+// errorCode is the JDWP error code for err. A command refused by the client's Policy is
+// reported as ErrNotImplemented, and any other error that is not a JdwpError as ErrInternal.
+func errorCode(err error) uint16 {
+	if err == nil {
+		return 0
+	}
+	if e, ok := err.(JdwpError); ok {
+		return e.Code
+	}
+	if errors.Is(err, ErrRefused) {
+		return ErrNotImplemented.(JdwpError).Code
+	}
+	return ErrInternal.(JdwpError).Code
+}
+
+// answerHandshake plays the VM's part in the handshake, echoing the debugger's.
+func answerHandshake(conn io.ReadWriter) error {
+	if d, ok := conn.(deadliner); ok {
+		d.SetDeadline(time.Now().Add(DefaultHandshakeTimeout))
+		defer d.SetDeadline(time.Time{})
+	}
+	received := make([]byte, len(Handshake))
+	if n, err := io.ReadFull(conn, received); err != nil {
+		return &HandshakeShortError{Received: received[:n], Err: err}
+	}
+	if !bytes.Equal(received, []byte(Handshake)) {
+		return &HandshakeMismatchError{Received: received}
+	}
+	_, err := conn.Write(received)
+	return err
+}
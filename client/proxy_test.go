@@ -0,0 +1,292 @@
+package client_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+// debugger attaches another debugger to the proxy.
+func debugger(t *testing.T, p *client.Proxy) client.Client {
+	here, there := net.Pipe()
+	go p.ServeConn(there)
+	c, err := client.New(here, client.WithLogger(client.NopLogger))
+	require.NoError(t, err)
+	return c
+}
+
+// breakpoint sets a breakpoint where the program's thread is stopped.
+func breakpoint(t *testing.T, c client.Client) int {
+	run := method(t, c, classBySignature(t, c, "Lcom/example/Main;"), "run")
+	id, err := client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyNone).
+		WithMod(client.ModKindLocation).WithLocation(client.NewLocation(run.MethodId, 8)).
+		Set(c)
+	require.NoError(t, err)
+	return id
+}
+
+func composite(t *testing.T, c client.Client) client.Composite {
+	select {
+	case e := <-c.Events():
+		var comp client.Composite
+		require.NoError(t, client.Decode(c, e.Data, &comp))
+		return comp
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+		return client.Composite{}
+	}
+}
+
+func noEvent(t *testing.T, c client.Client) {
+	select {
+	case e := <-c.Events():
+		t.Fatalf("unexpected event %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestProxyRepliesToSender(t *testing.T) {
+	vm := jdwptest.NewVM()
+	newProgram(vm)
+	for i := 0; i < 5; i++ {
+		vm.AddClass(fmt.Sprintf("Lcom/example/C%d;", i))
+	}
+	up := connect(t, jdwptest.NewAgent(vm))
+	defer up.Close()
+	p := client.NewProxy(up)
+	defer p.Close()
+
+	var wg sync.WaitGroup
+	for d := 0; d < 3; d++ {
+		c := debugger(t, p)
+		defer c.Close()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				sig := fmt.Sprintf("Lcom/example/C%d;", i%5)
+				cs, err := client.ClassesBySignature(c, sig)
+				if assert.NoError(t, err) && assert.Len(t, cs, 1) {
+					got, err := cs[0].ClassId.Signature(c)
+					assert.NoError(t, err)
+					assert.Equal(t, sig, got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestProxyRoutesEvents(t *testing.T) {
+	prog := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(prog.vm)
+	up := connect(t, a)
+	defer up.Close()
+	p := client.NewProxy(up)
+	defer p.Close()
+
+	one, two := debugger(t, p), debugger(t, p)
+	defer one.Close()
+	defer two.Close()
+	first, second := breakpoint(t, one), breakpoint(t, two)
+
+	// The VM reports both in one Composite, which is split between them
+	assert.Equal(t, 2, a.Breakpoint(prog.thread))
+	for c, id := range map[client.Client]int{one: first, two: second} {
+		comp := composite(t, c)
+		if assert.Len(t, comp.Events, 1) {
+			assert.Equal(t, id, comp.Events[0].(*client.EventBreakpoint).RequestId)
+		}
+	}
+
+	// Events for no request go to everyone
+	require.NoError(t, a.Sessions()[0].VMStart(prog.thread))
+	for _, c := range []client.Client{one, two} {
+		comp := composite(t, c)
+		if assert.Len(t, comp.Events, 1) {
+			assert.Equal(t, &client.EventVMStart{Thread: prog.thread.Id}, comp.Events[0])
+		}
+	}
+
+	// Each debugger clears only its own breakpoints
+	r, err := two.Call(client.EventRequest, client.ClearAllBreakPoints, nil)
+	require.NoError(t, err)
+	assert.Zero(t, r.ErrCode)
+	assert.Len(t, prog.vm.Requests(), 1)
+	assert.Equal(t, 1, a.Breakpoint(prog.thread))
+	assert.Len(t, composite(t, one).Events, 1)
+	noEvent(t, two)
+}
+
+func TestProxyDebuggerLeaves(t *testing.T) {
+	prog := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(prog.vm)
+	up := connect(t, a)
+	defer up.Close()
+	p := client.NewProxy(up)
+	defer p.Close()
+
+	staying, leaving, disposing := debugger(t, p), debugger(t, p), debugger(t, p)
+	defer staying.Close()
+	breakpoint(t, staying)
+	breakpoint(t, leaving)
+	breakpoint(t, disposing)
+	require.Len(t, prog.vm.Requests(), 3)
+
+	// Disposing of the VM disconnects only that debugger, and clears its requests
+	r, err := disposing.Call(client.VirtualMachine, client.VirtualMachineDispose, nil)
+	require.NoError(t, err)
+	assert.Zero(t, r.ErrCode)
+	select {
+	case <-disposing.Done():
+	case <-time.After(time.Second):
+		t.Fatal("still attached after Dispose")
+	}
+	assert.Len(t, prog.vm.Requests(), 2)
+
+	// As does dropping the connection
+	leaving.Close()
+	deadline := time.Now().Add(time.Second)
+	for len(prog.vm.Requests()) > 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, prog.vm.Requests(), 1)
+
+	assert.Equal(t, 1, a.Breakpoint(prog.thread))
+	assert.Len(t, composite(t, staying).Events, 1)
+	_, err = client.ClassesBySignature(staying, "Lcom/example/Main;")
+	assert.NoError(t, err)
+}
+
+func TestProxyVMGone(t *testing.T) {
+	prog := newProgram(jdwptest.NewVM())
+	up := connect(t, jdwptest.NewAgent(prog.vm))
+	p := client.NewProxy(up)
+	defer p.Close()
+
+	c := debugger(t, p)
+	defer c.Close()
+	up.Close()
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("debugger still attached after the VM went")
+	}
+}
+
+func TestProxySuspendsOnce(t *testing.T) {
+	prog := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(prog.vm)
+	up := connect(t, a)
+	defer up.Close()
+	p := client.NewProxy(up)
+	defer p.Close()
+
+	one, two := debugger(t, p), debugger(t, p)
+	defer one.Close()
+	defer two.Close()
+	for _, c := range []client.Client{one, two} {
+		run := method(t, c, classBySignature(t, c, "Lcom/example/Main;"), "run")
+		_, err := client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyAll).
+			WithMod(client.ModKindLocation).WithLocation(client.NewLocation(run.MethodId, 8)).
+			Set(c)
+		require.NoError(t, err)
+	}
+
+	// The VM is suspended once, so only one of the debuggers should resume it
+	assert.Equal(t, 2, a.Breakpoint(prog.thread))
+	policies := map[client.SuspendPolicy]int{}
+	for _, c := range []client.Client{one, two} {
+		policies[composite(t, c).SuspendPolicy]++
+	}
+	assert.Equal(t, map[client.SuspendPolicy]int{client.SuspendPolicyAll: 1, client.SuspendPolicyNone: 1}, policies)
+	assert.Equal(t, 1, prog.thread.SuspendCount())
+}
+
+func TestProxyUnansweredSet(t *testing.T) {
+	prog := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(prog.vm)
+	up := connect(t, a)
+	p := client.NewProxy(up)
+	defer p.Close()
+
+	// A request that nobody attached owns, so that its event waits on the Sets in flight
+	breakpoint(t, up)
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	a.Handle(client.EventRequest, client.Set, func(s *jdwptest.Session, pkt *jdwptest.Packet) ([]byte, error) {
+		entered <- struct{}{}
+		<-release
+		return nil, client.ErrInvalidEventType
+	})
+	c := debugger(t, p)
+	defer c.Close()
+	set := func() {
+		data := client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyNone).Marshal(c.IDSizes())
+		_, _, err := c.Send(client.EventRequest, client.Set, data)
+		require.NoError(t, err)
+		<-entered
+	}
+
+	// Once the Set fails, the event is dropped and the next passed on
+	set()
+	assert.Equal(t, 1, a.Breakpoint(prog.thread))
+	close(release)
+	require.NoError(t, a.Sessions()[0].VMStart(prog.thread))
+	if comp := composite(t, c); assert.Len(t, comp.Events, 1) {
+		assert.IsType(t, &client.EventVMStart{}, comp.Events[0])
+	}
+
+	// A Set that the VM never answers does not outlast the VM
+	release = make(chan struct{})
+	defer close(release)
+	set()
+	assert.Equal(t, 1, a.Breakpoint(prog.thread))
+	up.Close()
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("debugger still attached after the VM went")
+	}
+}
+
+func TestProxySlowDebugger(t *testing.T) {
+	prog := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(prog.vm)
+	up := connect(t, a)
+	defer up.Close()
+	p := client.NewProxy(up)
+	defer p.Close()
+
+	// A debugger that shakes hands and sends a command, but never reads again
+	stalled, there := net.Pipe()
+	defer stalled.Close()
+	go p.ServeConn(there)
+	_, err := stalled.Write([]byte(client.Handshake))
+	require.NoError(t, err)
+	_, err = io.ReadFull(stalled, make([]byte, len(client.Handshake)))
+	require.NoError(t, err)
+	version := make([]byte, client.HeaderLength)
+	binary.BigEndian.PutUint32(version[0:], client.HeaderLength)
+	binary.BigEndian.PutUint32(version[4:], 1)
+	binary.BigEndian.PutUint16(version[9:], uint16(client.VirtualMachine)<<8|uint16(client.VirtualMachineVersion))
+	_, err = stalled.Write(version)
+	require.NoError(t, err)
+
+	c := debugger(t, p)
+	defer c.Close()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, a.Sessions()[0].VMStart(prog.thread))
+		assert.Len(t, composite(t, c).Events, 1)
+	}
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"sync"
+)
+
+// DefaultEventQueueSize is the number of events (or VM commands) buffered for a slow
+// consumer of Events (or Commands) before the OverflowPolicy applies.
+const DefaultEventQueueSize = 1024
+
+// OverflowPolicy decides which packet is lost when a queue is full.
+//
+// Either way, an event that is dropped will never be seen: if it suspended any threads,
+// they remain suspended until something else resumes them.
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the longest-queued packet to make room for the new arrival.
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards the new arrival, keeping the queue as it is.
+	DropNewest
+)
+
+// packetQueue sits between the reader, which must never block on a consumer, and the
+// Events or Commands channel. It holds at most size packets, besides the one being handed
+// over to the consumer.
+type packetQueue struct {
+	name    string
+	mu      sync.Mutex
+	packets []interface{}
+	size    int
+	policy  OverflowPolicy
+	ended   bool
+	ready   chan struct{}
+	dropped uint64
+	log     Logger
+}
+
+func newPacketQueue(name string, size int, policy OverflowPolicy, log Logger) *packetQueue {
+	if size < 1 {
+		size = 1
+	}
+	return &packetQueue{
+		name:   name,
+		size:   size,
+		policy: policy,
+		ready:  make(chan struct{}, 1),
+		log:    log,
+	}
+}
+
+// push adds a packet to the queue without blocking.
+func (q *packetQueue) push(p interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.packets) >= q.size {
+		q.dropped++
+		if q.policy == DropNewest {
+			q.log.Warnf("jdwp %s queue full, dropping newest packet (%d dropped): %+v", q.name, q.dropped, p)
+			return
+		}
+		q.log.Warnf("jdwp %s queue full, dropping oldest packet (%d dropped): %+v", q.name, q.dropped, q.packets[0])
+		q.packets = q.packets[1:]
+	}
+	q.packets = append(q.packets, p)
+	q.signal()
+}
+
+// end marks that no more packets will be pushed.
+func (q *packetQueue) end() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.ended = true
+	q.signal()
+}
+
+func (q *packetQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// take removes the oldest packet, reporting whether there was one. The last result is false
+// once the queue has ended, so that nothing will follow what is returned.
+func (q *packetQueue) take() (interface{}, bool, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.packets) == 0 {
+		return nil, false, !q.ended
+	}
+	p := q.packets[0]
+	q.packets = q.packets[1:]
+	return p, true, !q.ended || len(q.packets) > 0
+}
+
+// deliver hands queued packets to send, in order, until the queue ends, stop is closed,
+// or send reports that it was stopped.
+func (q *packetQueue) deliver(stop <-chan struct{}, send func(interface{}) bool) {
+	for {
+		select {
+		case <-q.ready:
+		case <-stop:
+			return
+		}
+		// One at a time, so that the queue never holds more than its size
+		for {
+			p, ok, more := q.take()
+			if ok && !send(p) {
+				return
+			}
+			if !more {
+				return
+			}
+			if !ok {
+				break
+			}
+		}
+	}
+}
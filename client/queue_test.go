@@ -0,0 +1,79 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func queued(q *packetQueue) []Id {
+	ids := []Id{}
+	for {
+		p, ok, _ := q.take()
+		if !ok {
+			return ids
+		}
+		ids = append(ids, p.(*Event).Id)
+	}
+}
+
+func TestQueueOverflow(t *testing.T) {
+	for policy, expected := range map[OverflowPolicy][]Id{
+		DropOldest: {4, 5},
+		DropNewest: {1, 2},
+	} {
+		q := newPacketQueue("events", 2, policy, NopLogger)
+		for id := Id(1); id <= 5; id++ {
+			q.push(&Event{Header: Header{Id: id}})
+		}
+		assert.Equal(t, expected, queued(q))
+		assert.Equal(t, uint64(3), q.dropped)
+	}
+}
+
+func TestQueueDrainsBeforeEnding(t *testing.T) {
+	q := newPacketQueue("events", 10, DropOldest, NopLogger)
+	out := make(chan *Event)
+	go func() {
+		defer close(out)
+		q.deliver(make(chan struct{}), func(p interface{}) bool {
+			out <- p.(*Event)
+			return true
+		})
+	}()
+	q.push(&Event{Header: Header{Id: 1}})
+	q.push(&Event{Header: Header{Id: 2}})
+	q.end()
+
+	ids := []Id{}
+	for e := range out {
+		ids = append(ids, e.Id)
+	}
+	assert.Equal(t, []Id{1, 2}, ids)
+}
+
+func TestQueueHoldsSizeWhileDelivering(t *testing.T) {
+	q := newPacketQueue("events", 2, DropNewest, NopLogger)
+	q.push(&Event{Header: Header{Id: 1}})
+	q.push(&Event{Header: Header{Id: 2}})
+
+	handing, out := make(chan struct{}), make(chan *Event)
+	go q.deliver(make(chan struct{}), func(p interface{}) bool {
+		handing <- struct{}{}
+		out <- p.(*Event)
+		return true
+	})
+	<-handing
+
+	// With the first packet handed over, there is room for one more, and no more
+	q.push(&Event{Header: Header{Id: 3}})
+	q.push(&Event{Header: Header{Id: 4}})
+	assert.Equal(t, uint64(1), q.dropped)
+
+	ids := []Id{(<-out).Id}
+	for i := 0; i < 2; i++ {
+		<-handing
+		ids = append(ids, (<-out).Id)
+	}
+	assert.Equal(t, []Id{1, 2, 3}, ids)
+}
@@ -1,41 +1,38 @@
 package client
 
-const (
-	ReferenceType          = CommandSet(2)
-	ReferenceTypeSignature = Command(1)
-	ReferenceTypeMethods   = Command(5)
-	ReferenceTypeFields    = Command(4)
-)
-
 func (ref ClassId) Signature(c Client) (string, error) {
-	res, err := c.Call(ReferenceType, ReferenceTypeSignature, Seq().ClassId(ref).Marshal())
-	if err != nil {
-		return "", err
-	}
-	if res.ErrCode != 0 {
-		return "", lookupError(res.ErrCode)
-	}
-	var sig string
-	err = Parse(res.Data, &sig)
-	if err != nil {
-		return "", err
+	b := NewBatch(c)
+	sig := ref.signature(b)
+	b.Run()
+	return sig()
+}
+
+// signature queues the Signature command on b, returning a function that parses its reply
+// once b has run.
+func (ref ClassId) signature(b *Batch) func() (string, error) {
+	bc := b.add(ReferenceType, ReferenceTypeSignature, b.c.IDSizes().Seq().ClassId(ref))
+	return func() (string, error) {
+		if bc.Err != nil {
+			return "", bc.Err
+		}
+		var sig string
+		if err := b.c.IDSizes().Parse(bc.Reply.Data, &sig); err != nil {
+			return "", err
+		}
+		return sig, nil
 	}
-	return sig, nil
 }
 
 func (ref ClassId) Methods(c Client) ([]MethodDef, error) {
-	res, err := c.Call(ReferenceType, ReferenceTypeMethods, Seq().ClassId(ref).Marshal())
+	res, err := call(c, ReferenceType, ReferenceTypeMethods, c.IDSizes().Seq().ClassId(ref))
 	if err != nil {
 		return nil, err
 	}
-	if res.ErrCode != 0 {
-		return nil, lookupError(res.ErrCode)
-	}
 	ms := struct {
 		Declared int
 		Methods  []MethodDef `jdwp:"counter:Declared"`
 	}{}
-	err = Parse(res.Data, &ms)
+	err = c.IDSizes().Parse(res.Data, &ms)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +44,7 @@ func (ref ClassId) Methods(c Client) ([]MethodDef, error) {
 
 type MethodId struct {
 	ref      ClassId
-	MethodId uint64
+	MethodId uint64 `jdwp:"id:method"`
 }
 
 type MethodDef struct {
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Registry holds what a client knows of JDWP beyond the specification: the command sets
+// reserved for vendors, from 128 upwards, and event kinds of their own. Each client can
+// have its own, given by WithRegistry; whatever a registry lacks is looked up among the
+// specification's commands and events, and the factories registered by RegisterFactory. A
+// nil *Registry knows only those.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[reflect.Type]func(io.Reader, reflect.Value) error
+	events    map[EventKind]eventType
+	commands  map[CommandKey]CommandCodec
+}
+
+type eventType struct {
+	layout   []EventField
+	newEvent func() VMEvent
+}
+
+// CommandCodec encodes the arguments of a command that a registry adds, and decodes its
+// reply. Without Encode, the arguments must already be a []byte, or nil; without Decode,
+// the reply is returned as a []byte.
+type CommandCodec struct {
+	Encode func(sizes IDSizes, args interface{}) ([]byte, error)
+	Decode func(sizes IDSizes, data []byte) (interface{}, error)
+}
+
+// ErrUnregistered is returned by Invoke for a command that the client's registry lacks.
+var ErrUnregistered = errors.New("jdwp command not registered")
+
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: map[reflect.Type]func(io.Reader, reflect.Value) error{},
+		events:    map[EventKind]eventType{},
+		commands:  map[CommandKey]CommandCodec{},
+	}
+}
+
+// WithRegistry has the client use r for the commands and events it adds, such as in Decode,
+// Invoke, and when a Proxy splits Composite events.
+func WithRegistry(r *Registry) Option {
+	return func(c *client) {
+		c.reg = r
+	}
+}
+
+// RegisterEvent adds an event kind, whose fields after its kind and request ID are laid out
+// as given. Events of the kind are decoded into the value returned by newEvent, which should
+// be a pointer to a struct whose first field is the request ID; without newEvent, they are
+// decoded as an *EventUnknown.
+func (r *Registry) RegisterEvent(kind EventKind, layout []EventField, newEvent func() VMEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[kind] = eventType{layout: layout, newEvent: newEvent}
+}
+
+// RegisterCommand adds a command, to be sent with Invoke.
+func (r *Registry) RegisterCommand(set CommandSet, cmd Command, codec CommandCodec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands[CommandKey{Set: set, Command: cmd}] = codec
+}
+
+// RegisterFactory is the counterpart of the package's RegisterFactory, for values parsed
+// using this registry.
+func (r *Registry) RegisterFactory(slice interface{}, f func(io.Reader, reflect.Value) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[reflect.ValueOf(slice).Type().Elem()] = f
+}
+
+// Parse decodes data into the structure pointed to by into, reading IDs using sizes and
+// instantiating interfaces using the registry.
+func (r *Registry) Parse(sizes IDSizes, data []byte, into interface{}) error {
+	return parse(sizes, r, data, into)
+}
+
+func (r *Registry) factory(t reflect.Type) (func(io.Reader, reflect.Value) error, bool) {
+	if r != nil {
+		r.mu.RLock()
+		f, ok := r.factories[t]
+		r.mu.RUnlock()
+		if ok {
+			return f, true
+		}
+	}
+	f, ok := interfaceFactories[t]
+	return f, ok
+}
+
+// event returns the type of a kind of event, or false if it is unknown.
+func (r *Registry) event(kind EventKind) (eventType, bool) {
+	if r != nil {
+		r.mu.RLock()
+		e, ok := r.events[kind]
+		r.mu.RUnlock()
+		if ok {
+			return e, true
+		}
+	}
+	layout, ok := eventLayouts[kind]
+	newEvent := builtinEvents[kind]
+	return eventType{layout: layout, newEvent: newEvent}, ok || newEvent != nil
+}
+
+func (r *Registry) command(set CommandSet, cmd Command) (CommandCodec, bool) {
+	if r == nil {
+		return CommandCodec{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	codec, ok := r.commands[CommandKey{Set: set, Command: cmd}]
+	return codec, ok
+}
+
+func registryOf(c Client) *Registry {
+	if c, ok := c.(interface{ registry() *Registry }); ok {
+		return c.registry()
+	}
+	return nil
+}
+
+func (c *client) registry() *Registry {
+	return c.reg
+}
+
+// Decode parses data, such as the body of an Event or Reply, using the client's ID sizes and
+// registry.
+func Decode(c Client, data []byte, into interface{}) error {
+	return registryOf(c).Parse(c.IDSizes(), data, into)
+}
+
+// Invoke sends a command added by the client's registry, encoding args and decoding the
+// reply with the command's codec. An error code in the reply is returned as an error.
+func Invoke(c Client, set CommandSet, cmd Command, args interface{}) (interface{}, error) {
+	codec, ok := registryOf(c).command(set, cmd)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d.%d", ErrUnregistered, set, cmd)
+	}
+	var data []byte
+	if codec.Encode != nil {
+		var err error
+		if data, err = codec.Encode(c.IDSizes(), args); err != nil {
+			return nil, err
+		}
+	} else if args != nil {
+		b, ok := args.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("command %d.%d has no encoder for %T", set, cmd, args)
+		}
+		data = b
+	}
+	r, err := c.Call(set, cmd, data)
+	if err != nil {
+		return nil, err
+	}
+	if r.ErrCode != 0 {
+		return nil, lookupError(r.ErrCode)
+	}
+	if codec.Decode == nil {
+		return r.Data, nil
+	}
+	return codec.Decode(c.IDSizes(), r.Data)
+}
@@ -0,0 +1,108 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+const (
+	ddm      = client.CommandSet(199)
+	ddmChunk = client.Command(1)
+	ddmEvent = client.EventKind(199)
+)
+
+type eventDDM struct {
+	RequestId int
+	Thread    client.ThreadId
+	Chunk     string
+}
+
+func (*eventDDM) EventKind() client.EventKind {
+	return ddmEvent
+}
+
+func ddmRegistry() *client.Registry {
+	r := client.NewRegistry()
+	r.RegisterEvent(ddmEvent, []client.EventField{client.EventFieldThread, client.EventFieldString},
+		func() client.VMEvent { return &eventDDM{} })
+	r.RegisterCommand(ddm, ddmChunk, client.CommandCodec{
+		Encode: func(sizes client.IDSizes, args interface{}) ([]byte, error) {
+			return sizes.Seq().String(args.(string)).Marshal(), nil
+		},
+		Decode: func(sizes client.IDSizes, data []byte) (interface{}, error) {
+			var s string
+			err := sizes.Parse(data, &s)
+			return s, err
+		},
+	})
+	return r
+}
+
+func TestRegistryEvents(t *testing.T) {
+	data := client.Seq().Octet(uint8(client.SuspendPolicyNone)).Int(3).
+		Octet(uint8(ddmEvent)).Int(0).ThreadId(7).String("HELO").
+		// Known only by its layout
+		Octet(uint8(client.EventKindTHREAD_START)).Int(4).ThreadId(8).
+		Octet(uint8(client.EventKindVM_START)).Int(0).ThreadId(9).
+		Marshal()
+
+	var comp client.Composite
+	require.NoError(t, ddmRegistry().Parse(client.DefaultIDSizes, data, &comp))
+	require.Len(t, comp.Events, 3)
+	assert.Equal(t, &eventDDM{Thread: 7, Chunk: "HELO"}, comp.Events[0])
+	assert.Equal(t, &client.EventUnknown{
+		Kind:      client.EventKindTHREAD_START,
+		RequestId: 4,
+		Data:      client.Seq().ThreadId(8).Marshal(),
+	}, comp.Events[1])
+	assert.Equal(t, &client.EventVMStart{Thread: 9}, comp.Events[2])
+
+	// Events of a kind with no layout cannot be passed over
+	err := client.Parse(data, &comp)
+	assert.Contains(t, err.Error(), "unknown event kind 199")
+}
+
+func TestRegistryPerClient(t *testing.T) {
+	vm := jdwptest.NewVM()
+	a := jdwptest.NewAgent(vm)
+	a.Handle(ddm, ddmChunk, func(s *jdwptest.Session, p *jdwptest.Packet) ([]byte, error) {
+		d := jdwptest.NewDecoder(vm.Sizes, p.Data)
+		return jdwptest.NewEncoder(vm.Sizes).String("re: " + d.String()).Bytes(), d.Err()
+	})
+	a.Handle(ddm, 2, func(s *jdwptest.Session, p *jdwptest.Packet) ([]byte, error) {
+		return nil, client.ErrIllegalArgument
+	})
+	c, err := client.New(a.Pipe(), client.WithRegistry(ddmRegistry()))
+	require.NoError(t, err)
+	defer c.Close()
+	other := connect(t, a)
+	defer other.Close()
+
+	r, err := client.Invoke(c, ddm, ddmChunk, "HELO")
+	require.NoError(t, err)
+	assert.Equal(t, "re: HELO", r)
+	_, err = client.Invoke(other, ddm, ddmChunk, "HELO")
+	assert.True(t, errors.Is(err, client.ErrUnregistered))
+
+	data := c.IDSizes().Seq().Octet(uint8(client.SuspendPolicyNone)).Int(1).
+		Octet(uint8(ddmEvent)).Int(0).ThreadId(7).String("APNM").Marshal()
+	var comp client.Composite
+	require.NoError(t, client.Decode(c, data, &comp))
+	assert.Equal(t, &eventDDM{Thread: 7, Chunk: "APNM"}, comp.Events[0])
+	assert.Error(t, client.Decode(other, data, &comp))
+
+	// Errors are reported for commands with no codec too
+	r2 := client.NewRegistry()
+	r2.RegisterCommand(ddm, 2, client.CommandCodec{})
+	c2, err := client.New(a.Pipe(), client.WithRegistry(r2))
+	require.NoError(t, err)
+	defer c2.Close()
+	_, err = client.Invoke(c2, ddm, 2, []byte{1})
+	assert.Equal(t, client.ErrIllegalArgument, err)
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// ReplayConn is a net.Conn that plays the VM's side of a recorded session back to a client.
+// Commands must arrive in the order in which they were recorded; each is answered with the
+// recorded reply, and recorded events are injected at the points they originally arrived.
+// A command that differs from the recording ends the session with a *ReplayMismatchError.
+type ReplayConn struct {
+	net.Conn
+	vm      net.Conn
+	entries []*CaptureEntry
+	mu      sync.Mutex
+	err     error
+	done    chan struct{}
+}
+
+// ReplayMismatchError describes the first command that departed from the recording.
+type ReplayMismatchError struct {
+	Index    int           // position in the recording
+	Expected *CaptureEntry // the recorded command, or nil if the recording had finished
+	Got      VMCommand     // the command the client actually sent
+}
+
+func (e *ReplayMismatchError) Error() string {
+	got := fmt.Sprintf("command %d.%d with data [% x]", e.Got.Set, e.Got.Command, e.Got.Data)
+	if e.Expected == nil {
+		return fmt.Sprintf("replay mismatch: recording finished, but received %s", got)
+	}
+	return fmt.Sprintf("replay mismatch at entry %d: expected command %d.%d with data [% x], but received %s",
+		e.Index, e.Expected.CommandSet, e.Expected.Command, e.Expected.Data(), got)
+}
+
+// ReadCapture reads a whole capture, as written by WithCapture.
+func ReadCapture(r io.Reader) ([]*CaptureEntry, error) {
+	cr := NewCaptureReader(r)
+	entries := []*CaptureEntry{}
+	for {
+		e, err := cr.Next()
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+func NewReplayConn(entries []*CaptureEntry) *ReplayConn {
+	conn, vm := net.Pipe()
+	r := &ReplayConn{
+		Conn:    conn,
+		vm:      vm,
+		entries: entries,
+		done:    make(chan struct{}),
+	}
+	go r.play()
+	return r
+}
+
+// Done is closed once every recorded entry has been played, or the replay has failed.
+func (r *ReplayConn) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err reports why the replay failed, if it has.
+func (r *ReplayConn) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *ReplayConn) fail(err error) {
+	r.mu.Lock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.mu.Unlock()
+	r.vm.Close()
+}
+
+// Read and Write report a mismatch, rather than the closed pipe that it leaves behind.
+func (r *ReplayConn) Read(b []byte) (int, error) {
+	n, err := r.Conn.Read(b)
+	if err != nil && r.Err() != nil {
+		err = r.Err()
+	}
+	return n, err
+}
+
+func (r *ReplayConn) Write(b []byte) (int, error) {
+	n, err := r.Conn.Write(b)
+	if err != nil && r.Err() != nil {
+		err = r.Err()
+	}
+	return n, err
+}
+
+func (r *ReplayConn) Close() error {
+	r.vm.Close()
+	return r.Conn.Close()
+}
+
+func (r *ReplayConn) play() {
+	defer close(r.done)
+	hs := make([]byte, len(Handshake))
+	if _, err := io.ReadFull(r.vm, hs); err != nil {
+		r.fail(err)
+		return
+	}
+	if string(hs) != Handshake {
+		r.fail(fmt.Errorf("replay: unexpected handshake %q", hs))
+		return
+	}
+	if _, err := r.vm.Write(hs); err != nil {
+		r.fail(err)
+		return
+	}
+
+	// The client need not number its commands as the recorded one did
+	ids := map[Id]Id{}
+	for i, e := range r.entries {
+		if e.Direction == Outgoing {
+			got, err := r.receive()
+			if err != nil {
+				r.fail(err)
+				return
+			}
+			if got.Set != e.CommandSet || got.Command != e.Command || !bytes.Equal(got.Data, e.Data()) {
+				r.fail(&ReplayMismatchError{Index: i, Expected: e, Got: *got})
+				return
+			}
+			ids[e.Id] = got.Id
+			continue
+		}
+		packet := append([]byte{}, e.Packet...)
+		if id, ok := ids[e.Id]; ok && e.IsReply() {
+			binary.BigEndian.PutUint32(packet[4:], uint32(id))
+			delete(ids, e.Id)
+		}
+		if _, err := r.vm.Write(packet); err != nil {
+			r.fail(err)
+			return
+		}
+	}
+
+	// Anything further is a departure from the recording
+	go func() {
+		got, err := r.receive()
+		if err == nil {
+			r.fail(&ReplayMismatchError{Index: len(r.entries), Got: *got})
+		}
+	}()
+}
+
+func (r *ReplayConn) receive() (*VMCommand, error) {
+	var hdr [HeaderLength]byte
+	if _, err := io.ReadFull(r.vm, hdr[:]); err != nil {
+		return nil, err
+	}
+	cmd := VMCommand{
+		Header: Header{
+			Length: binary.BigEndian.Uint32(hdr[0:]),
+			Id:     Id(binary.BigEndian.Uint32(hdr[4:])),
+			Flags:  hdr[8],
+		},
+		Set:     CommandSet(hdr[9]),
+		Command: Command(hdr[10]),
+	}
+	if cmd.Length < HeaderLength {
+		return nil, fmt.Errorf("replay: packet length %d is shorter than its header", cmd.Length)
+	}
+	cmd.Data = make([]byte, cmd.Length-HeaderLength)
+	if _, err := io.ReadFull(r.vm, cmd.Data); err != nil {
+		return nil, err
+	}
+	return &cmd, nil
+}
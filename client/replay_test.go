@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordSession captures a short session against a live agent: a version query, during
+// which a breakpoint event arrives, and a thread name lookup.
+func recordSession(t *testing.T) []*CaptureEntry {
+	here, there := net.Pipe()
+	a := &testAgent{t: t, conn: there}
+	go a.serve(func(p packet, reply func(uint16, []byte)) {
+		switch p.Set {
+		case VirtualMachine:
+			a.event(0x40000000, breakpointEvent)
+			reply(0, Seq().String("fake VM").Int(1).Int(8).String("1.8").String("fake").Marshal())
+		default:
+			reply(0, Seq().String("main").Marshal())
+		}
+	})
+	var capture bytes.Buffer
+	c, err := New(here, WithCapture(&capture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if _, err := c.Call(VirtualMachine, VirtualMachineVersion, []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	<-c.Events()
+	if _, err := c.Call(Thread, ThreadName, Seq().ThreadId(3).Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadCapture(&capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+var breakpointEvent = []byte{
+	1, 0, 0, 0, 1,
+	2, 0, 0, 0, 2,
+	0, 0, 0, 0, 0, 0, 0, 3,
+	1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 9, 0, 0, 0, 0, 0, 0, 0, 0,
+}
+
+func TestReplayServesRecording(t *testing.T) {
+	replay := NewReplayConn(recordSession(t))
+	c, err := New(replay)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer c.Close()
+
+	r, err := c.Call(VirtualMachine, VirtualMachineVersion, []byte{})
+	if assert.Nil(t, err) {
+		var v VersionReply
+		assert.Nil(t, Parse(r.Data, &v))
+		assert.Equal(t, "fake VM", v.Description)
+	}
+	e := <-c.Events()
+	assert.Equal(t, breakpointEvent, e.Data)
+
+	r, err = c.Call(Thread, ThreadName, Seq().ThreadId(3).Marshal())
+	if assert.Nil(t, err) {
+		var name string
+		assert.Nil(t, Parse(r.Data, &name))
+		assert.Equal(t, "main", name)
+	}
+	<-replay.Done()
+	assert.Nil(t, replay.Err())
+}
+
+func TestReplayReportsMismatch(t *testing.T) {
+	replay := NewReplayConn(recordSession(t))
+	c, err := New(replay)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer c.Close()
+
+	_, err = c.Call(VirtualMachine, VirtualMachineAllThreads, []byte{})
+	var mismatch *ReplayMismatchError
+	if assert.True(t, errors.As(err, &mismatch), "%v", err) {
+		assert.Equal(t, 2, mismatch.Index)
+		assert.Equal(t, VirtualMachineVersion, mismatch.Expected.Command)
+		assert.Equal(t, VirtualMachineAllThreads, mismatch.Got.Command)
+	}
+	assert.Equal(t, mismatch, replay.Err())
+	assert.Contains(t, c.Err().Error(), "replay mismatch at entry 2: expected command 1.1")
+}
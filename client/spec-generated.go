@@ -0,0 +1,3336 @@
+// Code generated by jdwpgen from the JDWP specification. DO NOT EDIT.
+
+package client
+
+// The VirtualMachine command set.
+const (
+	VirtualMachine                      = CommandSet(1)
+	VirtualMachineVersion               = Command(1)
+	VirtualMachineClassesBySignature    = Command(2)
+	VirtualMachineAllClasses            = Command(3)
+	VirtualMachineAllThreads            = Command(4)
+	VirtualMachineTopLevelThreadGroups  = Command(5)
+	VirtualMachineDispose               = Command(6)
+	VirtualMachineIDSizes               = Command(7)
+	VirtualMachineSuspend               = Command(8)
+	VirtualMachineResume                = Command(9)
+	VirtualMachineExit                  = Command(10)
+	VirtualMachineCreateString          = Command(11)
+	VirtualMachineCapabilities          = Command(12)
+	VirtualMachineClassPaths            = Command(13)
+	VirtualMachineDisposeObjects        = Command(14)
+	VirtualMachineHoldEvents            = Command(15)
+	VirtualMachineReleaseEvents         = Command(16)
+	VirtualMachineCapabilitiesNew       = Command(17)
+	VirtualMachineRedefineClasses       = Command(18)
+	VirtualMachineSetDefaultStratum     = Command(19)
+	VirtualMachineAllClassesWithGeneric = Command(20)
+	VirtualMachineInstanceCounts        = Command(21)
+	VirtualMachineAllModules            = Command(22)
+)
+
+// The ReferenceType command set.
+const (
+	ReferenceType                     = CommandSet(2)
+	ReferenceTypeSignature            = Command(1)
+	ReferenceTypeClassLoader          = Command(2)
+	ReferenceTypeModifiers            = Command(3)
+	ReferenceTypeFields               = Command(4)
+	ReferenceTypeMethods              = Command(5)
+	ReferenceTypeGetValues            = Command(6)
+	ReferenceTypeSourceFile           = Command(7)
+	ReferenceTypeNestedTypes          = Command(8)
+	ReferenceTypeStatus               = Command(9)
+	ReferenceTypeInterfaces           = Command(10)
+	ReferenceTypeClassObject          = Command(11)
+	ReferenceTypeSourceDebugExtension = Command(12)
+	ReferenceTypeSignatureWithGeneric = Command(13)
+	ReferenceTypeFieldsWithGeneric    = Command(14)
+	ReferenceTypeMethodsWithGeneric   = Command(15)
+	ReferenceTypeInstances            = Command(16)
+	ReferenceTypeClassFileVersion     = Command(17)
+	ReferenceTypeConstantPool         = Command(18)
+	ReferenceTypeModule               = Command(19)
+)
+
+// The ClassType command set.
+const (
+	ClassType             = CommandSet(3)
+	ClassTypeSuperclass   = Command(1)
+	ClassTypeSetValues    = Command(2)
+	ClassTypeInvokeMethod = Command(3)
+	ClassTypeNewInstance  = Command(4)
+)
+
+// The ArrayType command set.
+const (
+	ArrayType            = CommandSet(4)
+	ArrayTypeNewInstance = Command(1)
+)
+
+// The InterfaceType command set.
+const (
+	InterfaceType             = CommandSet(5)
+	InterfaceTypeInvokeMethod = Command(1)
+)
+
+// The Method command set.
+const (
+	Method                         = CommandSet(6)
+	MethodLineTable                = Command(1)
+	MethodVariableTable            = Command(2)
+	MethodBytecodes                = Command(3)
+	MethodIsObsolete               = Command(4)
+	MethodVariableTableWithGeneric = Command(5)
+)
+
+// The Field command set.
+const (
+	FieldCommandSet = CommandSet(8)
+)
+
+// The ObjectReference command set.
+const (
+	ObjectReference                  = CommandSet(9)
+	ObjectReferenceReferenceType     = Command(1)
+	ObjectReferenceGetValues         = Command(2)
+	ObjectReferenceSetValues         = Command(3)
+	ObjectReferenceMonitorInfo       = Command(5)
+	ObjectReferenceInvokeMethod      = Command(6)
+	ObjectReferenceDisableCollection = Command(7)
+	ObjectReferenceEnableCollection  = Command(8)
+	ObjectReferenceIsCollected       = Command(9)
+	ObjectReferenceReferringObjects  = Command(10)
+)
+
+// The StringReference command set.
+const (
+	StringReference      = CommandSet(10)
+	StringReferenceValue = Command(1)
+)
+
+// The ThreadReference command set.
+const (
+	ThreadReference                            = CommandSet(11)
+	ThreadReferenceName                        = Command(1)
+	ThreadReferenceSuspend                     = Command(2)
+	ThreadReferenceResume                      = Command(3)
+	ThreadReferenceStatus                      = Command(4)
+	ThreadReferenceThreadGroup                 = Command(5)
+	ThreadReferenceFrames                      = Command(6)
+	ThreadReferenceFrameCount                  = Command(7)
+	ThreadReferenceOwnedMonitors               = Command(8)
+	ThreadReferenceCurrentContendedMonitor     = Command(9)
+	ThreadReferenceStop                        = Command(10)
+	ThreadReferenceInterrupt                   = Command(11)
+	ThreadReferenceSuspendCount                = Command(12)
+	ThreadReferenceOwnedMonitorsStackDepthInfo = Command(13)
+	ThreadReferenceForceEarlyReturn            = Command(14)
+)
+
+// The ThreadGroupReference command set.
+const (
+	ThreadGroupReference         = CommandSet(12)
+	ThreadGroupReferenceName     = Command(1)
+	ThreadGroupReferenceParent   = Command(2)
+	ThreadGroupReferenceChildren = Command(3)
+)
+
+// The ArrayReference command set.
+const (
+	ArrayReference          = CommandSet(13)
+	ArrayReferenceLength    = Command(1)
+	ArrayReferenceGetValues = Command(2)
+	ArrayReferenceSetValues = Command(3)
+)
+
+// The ClassLoaderReference command set.
+const (
+	ClassLoaderReference               = CommandSet(14)
+	ClassLoaderReferenceVisibleClasses = Command(1)
+)
+
+// The EventRequest command set.
+const (
+	EventRequest                    = CommandSet(15)
+	EventRequestSetCommand          = Command(1)
+	EventRequestClearCommand        = Command(2)
+	EventRequestClearAllBreakpoints = Command(3)
+)
+
+// The StackFrame command set.
+const (
+	StackFrame           = CommandSet(16)
+	StackFrameGetValues  = Command(1)
+	StackFrameSetValues  = Command(2)
+	StackFrameThisObject = Command(3)
+	StackFramePopFrames  = Command(4)
+)
+
+// The ClassObjectReference command set.
+const (
+	ClassObjectReference              = CommandSet(17)
+	ClassObjectReferenceReflectedType = Command(1)
+)
+
+// The ModuleReference command set.
+const (
+	ModuleReference            = CommandSet(18)
+	ModuleReferenceName        = Command(1)
+	ModuleReferenceClassLoader = Command(2)
+)
+
+// The Event command set.
+const (
+	EventCommandSet = CommandSet(64)
+	EventComposite  = Command(100)
+)
+
+// specCommands names every command that a debugger can send.
+var specCommands = map[CommandKey]string{
+	{VirtualMachine, VirtualMachineVersion}:                       "VirtualMachine.Version",
+	{VirtualMachine, VirtualMachineClassesBySignature}:            "VirtualMachine.ClassesBySignature",
+	{VirtualMachine, VirtualMachineAllClasses}:                    "VirtualMachine.AllClasses",
+	{VirtualMachine, VirtualMachineAllThreads}:                    "VirtualMachine.AllThreads",
+	{VirtualMachine, VirtualMachineTopLevelThreadGroups}:          "VirtualMachine.TopLevelThreadGroups",
+	{VirtualMachine, VirtualMachineDispose}:                       "VirtualMachine.Dispose",
+	{VirtualMachine, VirtualMachineIDSizes}:                       "VirtualMachine.IDSizes",
+	{VirtualMachine, VirtualMachineSuspend}:                       "VirtualMachine.Suspend",
+	{VirtualMachine, VirtualMachineResume}:                        "VirtualMachine.Resume",
+	{VirtualMachine, VirtualMachineExit}:                          "VirtualMachine.Exit",
+	{VirtualMachine, VirtualMachineCreateString}:                  "VirtualMachine.CreateString",
+	{VirtualMachine, VirtualMachineCapabilities}:                  "VirtualMachine.Capabilities",
+	{VirtualMachine, VirtualMachineClassPaths}:                    "VirtualMachine.ClassPaths",
+	{VirtualMachine, VirtualMachineDisposeObjects}:                "VirtualMachine.DisposeObjects",
+	{VirtualMachine, VirtualMachineHoldEvents}:                    "VirtualMachine.HoldEvents",
+	{VirtualMachine, VirtualMachineReleaseEvents}:                 "VirtualMachine.ReleaseEvents",
+	{VirtualMachine, VirtualMachineCapabilitiesNew}:               "VirtualMachine.CapabilitiesNew",
+	{VirtualMachine, VirtualMachineRedefineClasses}:               "VirtualMachine.RedefineClasses",
+	{VirtualMachine, VirtualMachineSetDefaultStratum}:             "VirtualMachine.SetDefaultStratum",
+	{VirtualMachine, VirtualMachineAllClassesWithGeneric}:         "VirtualMachine.AllClassesWithGeneric",
+	{VirtualMachine, VirtualMachineInstanceCounts}:                "VirtualMachine.InstanceCounts",
+	{VirtualMachine, VirtualMachineAllModules}:                    "VirtualMachine.AllModules",
+	{ReferenceType, ReferenceTypeSignature}:                       "ReferenceType.Signature",
+	{ReferenceType, ReferenceTypeClassLoader}:                     "ReferenceType.ClassLoader",
+	{ReferenceType, ReferenceTypeModifiers}:                       "ReferenceType.Modifiers",
+	{ReferenceType, ReferenceTypeFields}:                          "ReferenceType.Fields",
+	{ReferenceType, ReferenceTypeMethods}:                         "ReferenceType.Methods",
+	{ReferenceType, ReferenceTypeGetValues}:                       "ReferenceType.GetValues",
+	{ReferenceType, ReferenceTypeSourceFile}:                      "ReferenceType.SourceFile",
+	{ReferenceType, ReferenceTypeNestedTypes}:                     "ReferenceType.NestedTypes",
+	{ReferenceType, ReferenceTypeStatus}:                          "ReferenceType.Status",
+	{ReferenceType, ReferenceTypeInterfaces}:                      "ReferenceType.Interfaces",
+	{ReferenceType, ReferenceTypeClassObject}:                     "ReferenceType.ClassObject",
+	{ReferenceType, ReferenceTypeSourceDebugExtension}:            "ReferenceType.SourceDebugExtension",
+	{ReferenceType, ReferenceTypeSignatureWithGeneric}:            "ReferenceType.SignatureWithGeneric",
+	{ReferenceType, ReferenceTypeFieldsWithGeneric}:               "ReferenceType.FieldsWithGeneric",
+	{ReferenceType, ReferenceTypeMethodsWithGeneric}:              "ReferenceType.MethodsWithGeneric",
+	{ReferenceType, ReferenceTypeInstances}:                       "ReferenceType.Instances",
+	{ReferenceType, ReferenceTypeClassFileVersion}:                "ReferenceType.ClassFileVersion",
+	{ReferenceType, ReferenceTypeConstantPool}:                    "ReferenceType.ConstantPool",
+	{ReferenceType, ReferenceTypeModule}:                          "ReferenceType.Module",
+	{ClassType, ClassTypeSuperclass}:                              "ClassType.Superclass",
+	{ClassType, ClassTypeSetValues}:                               "ClassType.SetValues",
+	{ClassType, ClassTypeInvokeMethod}:                            "ClassType.InvokeMethod",
+	{ClassType, ClassTypeNewInstance}:                             "ClassType.NewInstance",
+	{ArrayType, ArrayTypeNewInstance}:                             "ArrayType.NewInstance",
+	{InterfaceType, InterfaceTypeInvokeMethod}:                    "InterfaceType.InvokeMethod",
+	{Method, MethodLineTable}:                                     "Method.LineTable",
+	{Method, MethodVariableTable}:                                 "Method.VariableTable",
+	{Method, MethodBytecodes}:                                     "Method.Bytecodes",
+	{Method, MethodIsObsolete}:                                    "Method.IsObsolete",
+	{Method, MethodVariableTableWithGeneric}:                      "Method.VariableTableWithGeneric",
+	{ObjectReference, ObjectReferenceReferenceType}:               "ObjectReference.ReferenceType",
+	{ObjectReference, ObjectReferenceGetValues}:                   "ObjectReference.GetValues",
+	{ObjectReference, ObjectReferenceSetValues}:                   "ObjectReference.SetValues",
+	{ObjectReference, ObjectReferenceMonitorInfo}:                 "ObjectReference.MonitorInfo",
+	{ObjectReference, ObjectReferenceInvokeMethod}:                "ObjectReference.InvokeMethod",
+	{ObjectReference, ObjectReferenceDisableCollection}:           "ObjectReference.DisableCollection",
+	{ObjectReference, ObjectReferenceEnableCollection}:            "ObjectReference.EnableCollection",
+	{ObjectReference, ObjectReferenceIsCollected}:                 "ObjectReference.IsCollected",
+	{ObjectReference, ObjectReferenceReferringObjects}:            "ObjectReference.ReferringObjects",
+	{StringReference, StringReferenceValue}:                       "StringReference.Value",
+	{ThreadReference, ThreadReferenceName}:                        "ThreadReference.Name",
+	{ThreadReference, ThreadReferenceSuspend}:                     "ThreadReference.Suspend",
+	{ThreadReference, ThreadReferenceResume}:                      "ThreadReference.Resume",
+	{ThreadReference, ThreadReferenceStatus}:                      "ThreadReference.Status",
+	{ThreadReference, ThreadReferenceThreadGroup}:                 "ThreadReference.ThreadGroup",
+	{ThreadReference, ThreadReferenceFrames}:                      "ThreadReference.Frames",
+	{ThreadReference, ThreadReferenceFrameCount}:                  "ThreadReference.FrameCount",
+	{ThreadReference, ThreadReferenceOwnedMonitors}:               "ThreadReference.OwnedMonitors",
+	{ThreadReference, ThreadReferenceCurrentContendedMonitor}:     "ThreadReference.CurrentContendedMonitor",
+	{ThreadReference, ThreadReferenceStop}:                        "ThreadReference.Stop",
+	{ThreadReference, ThreadReferenceInterrupt}:                   "ThreadReference.Interrupt",
+	{ThreadReference, ThreadReferenceSuspendCount}:                "ThreadReference.SuspendCount",
+	{ThreadReference, ThreadReferenceOwnedMonitorsStackDepthInfo}: "ThreadReference.OwnedMonitorsStackDepthInfo",
+	{ThreadReference, ThreadReferenceForceEarlyReturn}:            "ThreadReference.ForceEarlyReturn",
+	{ThreadGroupReference, ThreadGroupReferenceName}:              "ThreadGroupReference.Name",
+	{ThreadGroupReference, ThreadGroupReferenceParent}:            "ThreadGroupReference.Parent",
+	{ThreadGroupReference, ThreadGroupReferenceChildren}:          "ThreadGroupReference.Children",
+	{ArrayReference, ArrayReferenceLength}:                        "ArrayReference.Length",
+	{ArrayReference, ArrayReferenceGetValues}:                     "ArrayReference.GetValues",
+	{ArrayReference, ArrayReferenceSetValues}:                     "ArrayReference.SetValues",
+	{ClassLoaderReference, ClassLoaderReferenceVisibleClasses}:    "ClassLoaderReference.VisibleClasses",
+	{EventRequest, EventRequestSetCommand}:                        "EventRequest.Set",
+	{EventRequest, EventRequestClearCommand}:                      "EventRequest.Clear",
+	{EventRequest, EventRequestClearAllBreakpoints}:               "EventRequest.ClearAllBreakpoints",
+	{StackFrame, StackFrameGetValues}:                             "StackFrame.GetValues",
+	{StackFrame, StackFrameSetValues}:                             "StackFrame.SetValues",
+	{StackFrame, StackFrameThisObject}:                            "StackFrame.ThisObject",
+	{StackFrame, StackFramePopFrames}:                             "StackFrame.PopFrames",
+	{ClassObjectReference, ClassObjectReferenceReflectedType}:     "ClassObjectReference.ReflectedType",
+	{ModuleReference, ModuleReferenceName}:                        "ModuleReference.Name",
+	{ModuleReference, ModuleReferenceClassLoader}:                 "ModuleReference.ClassLoader",
+}
+
+// specEvents names the commands that the VM sends.
+var specEvents = map[CommandKey]string{
+	{EventCommandSet, EventComposite}: "Event.Composite",
+}
+
+// CommandName is the specification's name for a command, such as "VirtualMachine.Version",
+// or "" if the specification has no such command.
+func CommandName(set CommandSet, cmd Command) string {
+	if name, ok := specCommands[CommandKey{set, cmd}]; ok {
+		return name
+	}
+	return specEvents[CommandKey{set, cmd}]
+}
+
+// The errors of the JDWP specification, returned for the error codes of replies.
+var (
+	// Passed thread is null, is not a valid thread or has exited.
+	ErrInvalidThread = err(10, "invalid thread")
+	// Thread group invalid.
+	ErrInvalidThreadGroup = err(11, "invalid thread group")
+	// Invalid priority.
+	ErrInvalidPriority = err(12, "invalid priority")
+	// If the specified thread has not been suspended by an event.
+	ErrThreadNotSuspended = err(13, "thread not suspended")
+	// Thread already suspended.
+	ErrThreadSuspended = err(14, "thread suspended")
+	// Thread has not been started or is now dead.
+	ErrThreadNotAlive = err(15, "thread not alive")
+	// If this reference type has been unloaded and garbage collected.
+	ErrInvalidObject = err(20, "invalid object")
+	// Invalid class.
+	ErrInvalidClass = err(21, "invalid class")
+	// Class has been loaded but not yet prepared.
+	ErrClassNotPrepared = err(22, "class not prepared")
+	// Invalid method.
+	ErrInvalidMethodId = err(23, "invalid method id")
+	// Invalid location.
+	ErrInvalidLocation = err(24, "invalid location")
+	// Invalid field.
+	ErrInvalidFieldId = err(25, "invalid field id")
+	// Invalid jframeID.
+	ErrInvalidFrameId = err(30, "invalid frame id")
+	// There are no more Java or JNI frames on the call stack.
+	ErrNoMoreFrames = err(31, "no more frames")
+	// Information about the frame is not available.
+	ErrOpaqueFrame = err(32, "opaque frame")
+	// Operation can only be performed on current frame.
+	ErrNotCurrentFrame = err(33, "not current frame")
+	// The variable is not an appropriate type for the function used.
+	ErrTypeMismatch = err(34, "type mismatch")
+	// Invalid slot.
+	ErrInvalidSlot = err(35, "invalid slot")
+	// Item already set.
+	ErrDuplicate = err(40, "duplicate")
+	// Desired element not found.
+	ErrNotFound = err(41, "not found")
+	// Invalid module.
+	ErrInvalidModule = err(42, "invalid module")
+	// Invalid monitor.
+	ErrInvalidMonitor = err(50, "invalid monitor")
+	// This thread doesn't own the monitor.
+	ErrNotMonitorOwner = err(51, "not monitor owner")
+	// The call has been interrupted before completion.
+	ErrInterrupt = err(52, "interrupt")
+	// The virtual machine attempted to read a class file and determined that the file is malformed
+	// or otherwise cannot be interpreted as a class file.
+	ErrInvalidClassFormat = err(60, "invalid class format")
+	// A circularity has been detected while initializing a class.
+	ErrCircularClassDefinition = err(61, "circular class definition")
+	// The verifier detected that a class file, though well formed, contained some sort of internal
+	// inconsistency or security problem.
+	ErrFailsVerification = err(62, "fails verification")
+	// Adding methods has not been implemented.
+	ErrAddMethodNotImplemented = err(63, "add method not implemented")
+	// Schema change has not been implemented.
+	ErrSchemaChangeNotImplemented = err(64, "schema change not implemented")
+	// The state of the thread has been modified, and is now inconsistent.
+	ErrInvalidTypestate = err(65, "invalid typestate")
+	// A direct superclass is different for the new class version, or the set of directly
+	// implemented interfaces is different and canUnrestrictedlyRedefineClasses is false.
+	ErrHierarchyChangeNotImplemented = err(66, "hierarchy change not implemented")
+	// The new class version does not declare a method declared in the old class version and
+	// canUnrestrictedlyRedefineClasses is false.
+	ErrDeleteMethodNotImplemented = err(67, "delete method not implemented")
+	// A class file has a version number not supported by this VM.
+	ErrUnsupportedVersion = err(68, "unsupported version")
+	// The class name defined in the new class file is different from the name in the old class
+	// object.
+	ErrNamesDontMatch = err(69, "names dont match")
+	// The new class version has different modifiers and and canUnrestrictedlyRedefineClasses is
+	// false.
+	ErrClassModifiersChangeNotImplemented = err(70, "class modifiers change not implemented")
+	// A method in the new class version has different modifiers than its counterpart in the old
+	// class version and and canUnrestrictedlyRedefineClasses is false.
+	ErrMethodModifiersChangeNotImplemented = err(71, "method modifiers change not implemented")
+	// The new class version has a different NestHost, NestMembers, PermittedSubclasses, or Record
+	// class attribute and canUnrestrictedlyRedefineClasses is false.
+	ErrClassAttributeChangeNotImplemented = err(72, "class attribute change not implemented")
+	// The functionality is not implemented in this virtual machine.
+	ErrNotImplemented = err(99, "not implemented")
+	// Invalid pointer.
+	ErrNullPointer = err(100, "null pointer")
+	// Desired information is not available.
+	ErrAbsentInformation = err(101, "absent information")
+	// The specified event type id is not recognized.
+	ErrInvalidEventType = err(102, "invalid event type")
+	// Illegal argument.
+	ErrIllegalArgument = err(103, "illegal argument")
+	// The function needed to allocate memory and no more memory was available for allocation.
+	ErrOutOfMemory = err(110, "out of memory")
+	// Debugging has not been enabled in this virtual machine.
+	ErrAccessDenied = err(111, "access denied")
+	// The virtual machine is not running.
+	ErrVmDead = err(112, "vm dead")
+	// An unexpected internal error has occurred.
+	ErrInternal = err(113, "internal")
+	// The thread being used to call this function is not attached to the virtual machine.
+	ErrUnattachedThread = err(115, "unattached thread")
+	// object type id or class tag.
+	ErrInvalidTag = err(500, "invalid tag")
+	// Previous invoke not complete.
+	ErrAlreadyInvoking = err(502, "already invoking")
+	// Index is invalid.
+	ErrInvalidIndex = err(503, "invalid index")
+	// The length is invalid.
+	ErrInvalidLength = err(504, "invalid length")
+	// The string is invalid.
+	ErrInvalidString = err(506, "invalid string")
+	// The class loader is invalid.
+	ErrInvalidClassLoader = err(507, "invalid class loader")
+	// The array is invalid.
+	ErrInvalidArray = err(508, "invalid array")
+	// Unable to load the transport.
+	ErrTransportLoad = err(509, "transport load")
+	// Unable to initialize the transport.
+	ErrTransportInit = err(510, "transport init")
+	ErrNativeMethod  = err(511, "native method")
+	// The count is invalid.
+	ErrInvalidCount = err(512, "invalid count")
+)
+
+// VirtualMachineVersionRequest is the VirtualMachine.Version command. Returns the JDWP version
+// implemented by the target VM. The version string format is implementation dependent. It can
+// fail with ErrVmDead.
+type VirtualMachineVersionRequest struct{}
+
+// VirtualMachineVersionReply is the reply to VirtualMachine.Version.
+type VirtualMachineVersionReply struct {
+	Description string // Text information on the VM version
+	JdwpMajor   int    // Major JDWP Version number
+	JdwpMinor   int    // Minor JDWP Version number
+	VmVersion   string // Target VM JRE version, as in the java.version property
+	VmName      string // Target VM name, as in the java.vm.name property
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineVersionRequest) Call(c Client) (*VirtualMachineVersionReply, error) {
+	var reply VirtualMachineVersionReply
+	if err := request(c, VirtualMachine, VirtualMachineVersion, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineClassesBySignatureRequest is the VirtualMachine.ClassesBySignature command.
+// Returns reference types for all the classes loaded by the target VM which match the given
+// signature. It can fail with ErrVmDead.
+type VirtualMachineClassesBySignatureRequest struct {
+	Signature string // JNI signature of the class to find (for example, "Ljava/lang/String;").
+}
+
+// VirtualMachineClassesBySignatureReply is the reply to VirtualMachine.ClassesBySignature.
+type VirtualMachineClassesBySignatureReply struct {
+	NumClasses int                                         // Number of reference types that follow.
+	Classes    []VirtualMachineClassesBySignatureClassInfo `jdwp:"counter:NumClasses"`
+}
+
+// VirtualMachineClassesBySignatureClassInfo is one of the Classes of
+// VirtualMachineClassesBySignatureReply.
+type VirtualMachineClassesBySignatureClassInfo struct {
+	RefTypeTag uint8   // Kind of following reference type.
+	TypeId     ClassId // Matching loaded reference type
+	Status     int     // The current class status.
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineClassesBySignatureRequest) Call(c Client) (*VirtualMachineClassesBySignatureReply, error) {
+	var reply VirtualMachineClassesBySignatureReply
+	if err := request(c, VirtualMachine, VirtualMachineClassesBySignature, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineAllClassesRequest is the VirtualMachine.AllClasses command. Returns reference
+// types for all classes currently loaded by the target VM. It can fail with ErrVmDead.
+type VirtualMachineAllClassesRequest struct{}
+
+// VirtualMachineAllClassesReply is the reply to VirtualMachine.AllClasses.
+type VirtualMachineAllClassesReply struct {
+	NumClasses int                                 // Number of reference types that follow.
+	Classes    []VirtualMachineAllClassesClassInfo `jdwp:"counter:NumClasses"`
+}
+
+// VirtualMachineAllClassesClassInfo is one of the Classes of VirtualMachineAllClassesReply.
+type VirtualMachineAllClassesClassInfo struct {
+	RefTypeTag uint8   // Kind of following reference type.
+	TypeId     ClassId // Loaded reference type
+	Signature  string  // The JNI signature of the loaded reference type
+	Status     int     // The current class status.
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineAllClassesRequest) Call(c Client) (*VirtualMachineAllClassesReply, error) {
+	var reply VirtualMachineAllClassesReply
+	if err := request(c, VirtualMachine, VirtualMachineAllClasses, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineAllThreadsRequest is the VirtualMachine.AllThreads command. Returns all threads
+// currently running in the target VM. It can fail with ErrVmDead.
+type VirtualMachineAllThreadsRequest struct{}
+
+// VirtualMachineAllThreadsReply is the reply to VirtualMachine.AllThreads.
+type VirtualMachineAllThreadsReply struct {
+	NumThreads int        // Number of threads that follow.
+	Threads    []ThreadId `jdwp:"counter:NumThreads"`
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineAllThreadsRequest) Call(c Client) (*VirtualMachineAllThreadsReply, error) {
+	var reply VirtualMachineAllThreadsReply
+	if err := request(c, VirtualMachine, VirtualMachineAllThreads, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineTopLevelThreadGroupsRequest is the VirtualMachine.TopLevelThreadGroups command.
+// Returns all thread groups that do not have a parent. It can fail with ErrVmDead.
+type VirtualMachineTopLevelThreadGroupsRequest struct{}
+
+// VirtualMachineTopLevelThreadGroupsReply is the reply to VirtualMachine.TopLevelThreadGroups.
+type VirtualMachineTopLevelThreadGroupsReply struct {
+	NumGroups int             // Number of thread groups that follow.
+	Groups    []ThreadGroupId `jdwp:"counter:NumGroups"`
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineTopLevelThreadGroupsRequest) Call(c Client) (*VirtualMachineTopLevelThreadGroupsReply, error) {
+	var reply VirtualMachineTopLevelThreadGroupsReply
+	if err := request(c, VirtualMachine, VirtualMachineTopLevelThreadGroups, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineDisposeRequest is the VirtualMachine.Dispose command. Invalidates this virtual
+// machine mirror. The communication channel to the target VM is closed, and the target VM
+// prepares to accept another subsequent connection from this debugger or another debugger.
+type VirtualMachineDisposeRequest struct{}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineDisposeRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineDispose, r, nil)
+}
+
+// VirtualMachineIDSizesRequest is the VirtualMachine.IDSizes command. Returns the sizes of
+// variably-sized data types in the target VM. It can fail with ErrVmDead.
+type VirtualMachineIDSizesRequest struct{}
+
+// VirtualMachineIDSizesReply is the reply to VirtualMachine.IDSizes.
+type VirtualMachineIDSizesReply struct {
+	FieldIDSize         int // fieldID size in bytes
+	MethodIDSize        int // methodID size in bytes
+	ObjectIDSize        int // objectID size in bytes
+	ReferenceTypeIDSize int // referenceTypeID size in bytes
+	FrameIDSize         int // frameID size in bytes
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineIDSizesRequest) Call(c Client) (*VirtualMachineIDSizesReply, error) {
+	var reply VirtualMachineIDSizesReply
+	if err := request(c, VirtualMachine, VirtualMachineIDSizes, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineSuspendRequest is the VirtualMachine.Suspend command. Suspends the execution of
+// the application running in the target VM. All Java threads currently running will be
+// suspended. It can fail with ErrVmDead.
+type VirtualMachineSuspendRequest struct{}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineSuspendRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineSuspend, r, nil)
+}
+
+// VirtualMachineResumeRequest is the VirtualMachine.Resume command. Resumes execution of the
+// application after the suspend command or an event has stopped it. It can fail with ErrVmDead.
+type VirtualMachineResumeRequest struct{}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineResumeRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineResume, r, nil)
+}
+
+// VirtualMachineExitRequest is the VirtualMachine.Exit command. Terminates the target VM with
+// the given exit code. It can fail with ErrVmDead.
+type VirtualMachineExitRequest struct {
+	ExitCode int // the exit code
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineExitRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineExit, r, nil)
+}
+
+// VirtualMachineCreateStringRequest is the VirtualMachine.CreateString command. Creates a new
+// string object in the target VM and returns its id. It can fail with ErrVmDead.
+type VirtualMachineCreateStringRequest struct {
+	Utf string // UTF-8 characters to use in the created string.
+}
+
+// VirtualMachineCreateStringReply is the reply to VirtualMachine.CreateString.
+type VirtualMachineCreateStringReply struct {
+	StringObject StringId // Created string (instance of java.lang.String)
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineCreateStringRequest) Call(c Client) (*VirtualMachineCreateStringReply, error) {
+	var reply VirtualMachineCreateStringReply
+	if err := request(c, VirtualMachine, VirtualMachineCreateString, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineCapabilitiesRequest is the VirtualMachine.Capabilities command. Retrieve this
+// VM's capabilities. It can fail with ErrVmDead.
+type VirtualMachineCapabilitiesRequest struct{}
+
+// VirtualMachineCapabilitiesReply is the reply to VirtualMachine.Capabilities.
+type VirtualMachineCapabilitiesReply struct {
+	CanWatchFieldModification     bool // Can the VM watch field modification, and therefore can it send the Modification Watchpoint Event?
+	CanWatchFieldAccess           bool // Can the VM watch field access, and therefore can it send the Access Watchpoint Event?
+	CanGetBytecodes               bool // Can the VM get the bytecodes of a given method?
+	CanGetSyntheticAttribute      bool // Can the VM determine whether a field or method is synthetic?
+	CanGetOwnedMonitorInfo        bool // Can the VM get the owned monitors information for a thread?
+	CanGetCurrentContendedMonitor bool // Can the VM get the current contended monitor of a thread?
+	CanGetMonitorInfo             bool // Can the VM get the monitor information for a given object?
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineCapabilitiesRequest) Call(c Client) (*VirtualMachineCapabilitiesReply, error) {
+	var reply VirtualMachineCapabilitiesReply
+	if err := request(c, VirtualMachine, VirtualMachineCapabilities, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineClassPathsRequest is the VirtualMachine.ClassPaths command. Retrieve the
+// classpath and bootclasspath of the target VM. It can fail with ErrVmDead.
+type VirtualMachineClassPathsRequest struct{}
+
+// VirtualMachineClassPathsReply is the reply to VirtualMachine.ClassPaths.
+type VirtualMachineClassPathsReply struct {
+	BaseDir           string   // Base directory used to resolve relative paths in either of the following lists.
+	NumClasspaths     int      // Number of paths in classpath.
+	Classpaths        []string `jdwp:"counter:NumClasspaths"`
+	NumBootclasspaths int      // Number of paths in bootclasspath.
+	Bootclasspaths    []string `jdwp:"counter:NumBootclasspaths"`
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineClassPathsRequest) Call(c Client) (*VirtualMachineClassPathsReply, error) {
+	var reply VirtualMachineClassPathsReply
+	if err := request(c, VirtualMachine, VirtualMachineClassPaths, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineDisposeObjectsRequest is the VirtualMachine.DisposeObjects command. Releases a
+// list of object IDs. It can fail with ErrVmDead.
+type VirtualMachineDisposeObjectsRequest struct {
+	NumRequests int                                        // Number of object dispose requests that follow
+	Requests    []VirtualMachineDisposeObjectsRequestGroup `jdwp:"counter:NumRequests"`
+}
+
+// VirtualMachineDisposeObjectsRequestGroup is one of the Requests of
+// VirtualMachineDisposeObjectsRequest.
+type VirtualMachineDisposeObjectsRequestGroup struct {
+	Object ObjectId // The object ID
+	RefCnt int      // The number of times this object ID has been part of a packet received from the back-end.
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineDisposeObjectsRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineDisposeObjects, r, nil)
+}
+
+// VirtualMachineHoldEventsRequest is the VirtualMachine.HoldEvents command. Tells the target VM
+// to stop sending events. It can fail with ErrVmDead.
+type VirtualMachineHoldEventsRequest struct{}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineHoldEventsRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineHoldEvents, r, nil)
+}
+
+// VirtualMachineReleaseEventsRequest is the VirtualMachine.ReleaseEvents command. Tells the
+// target VM to continue sending events. It can fail with ErrVmDead.
+type VirtualMachineReleaseEventsRequest struct{}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineReleaseEventsRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineReleaseEvents, r, nil)
+}
+
+// VirtualMachineCapabilitiesNewRequest is the VirtualMachine.CapabilitiesNew command. Retrieve
+// all of this VM's capabilities. It can fail with ErrVmDead.
+type VirtualMachineCapabilitiesNewRequest struct{}
+
+// VirtualMachineCapabilitiesNewReply is the reply to VirtualMachine.CapabilitiesNew.
+type VirtualMachineCapabilitiesNewReply struct {
+	CanWatchFieldModification        bool // Can the VM watch field modification?
+	CanWatchFieldAccess              bool // Can the VM watch field access?
+	CanGetBytecodes                  bool // Can the VM get the bytecodes of a given method?
+	CanGetSyntheticAttribute         bool // Can the VM determine whether a field or method is synthetic?
+	CanGetOwnedMonitorInfo           bool // Can the VM get the owned monitors information for a thread?
+	CanGetCurrentContendedMonitor    bool // Can the VM get the current contended monitor of a thread?
+	CanGetMonitorInfo                bool // Can the VM get the monitor information for a given object?
+	CanRedefineClasses               bool // Can the VM redefine classes?
+	CanAddMethod                     bool // Can the VM add methods when redefining classes?
+	CanUnrestrictedlyRedefineClasses bool // Can the VM redefine classes in ways that are normally restricted?
+	CanPopFrames                     bool // Can the VM pop stack frames?
+	CanUseInstanceFilters            bool // Can the VM filter events by specific object?
+	CanGetSourceDebugExtension       bool // Can the VM get the source debug extension?
+	CanRequestVMDeathEvent           bool // Can the VM request VM death events?
+	CanSetDefaultStratum             bool // Can the VM set a default stratum?
+	CanGetInstanceInfo               bool // Can the VM return instances, counts of instances of classes and referring objects?
+	CanRequestMonitorEvents          bool // Can the VM request monitor events?
+	CanGetMonitorFrameInfo           bool // Can the VM get monitors with frame depth info?
+	CanUseSourceNameFilters          bool // Can the VM filter class prepare events by source name?
+	CanGetConstantPool               bool // Can the VM return the constant pool information?
+	CanForceEarlyReturn              bool // Can the VM force early return from a method?
+	Reserved22                       bool // Reserved for future capability
+	Reserved23                       bool // Reserved for future capability
+	Reserved24                       bool // Reserved for future capability
+	Reserved25                       bool // Reserved for future capability
+	Reserved26                       bool // Reserved for future capability
+	Reserved27                       bool // Reserved for future capability
+	Reserved28                       bool // Reserved for future capability
+	Reserved29                       bool // Reserved for future capability
+	Reserved30                       bool // Reserved for future capability
+	Reserved31                       bool // Reserved for future capability
+	Reserved32                       bool // Reserved for future capability
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineCapabilitiesNewRequest) Call(c Client) (*VirtualMachineCapabilitiesNewReply, error) {
+	var reply VirtualMachineCapabilitiesNewReply
+	if err := request(c, VirtualMachine, VirtualMachineCapabilitiesNew, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineRedefineClassesRequest is the VirtualMachine.RedefineClasses command. Installs
+// new class definitions. It can fail with ErrInvalidClass, ErrInvalidObject,
+// ErrUnsupportedVersion, ErrInvalidClassFormat, ErrCircularClassDefinition,
+// ErrFailsVerification, ErrNamesDontMatch, ErrNotImplemented, ErrAddMethodNotImplemented,
+// ErrSchemaChangeNotImplemented, ErrHierarchyChangeNotImplemented,
+// ErrDeleteMethodNotImplemented, ErrClassModifiersChangeNotImplemented,
+// ErrMethodModifiersChangeNotImplemented, ErrClassAttributeChangeNotImplemented, ErrVmDead.
+type VirtualMachineRedefineClassesRequest struct {
+	NumClasses int                                     // Number of reference types that follow.
+	Classes    []VirtualMachineRedefineClassesClassDef `jdwp:"counter:NumClasses"`
+}
+
+// VirtualMachineRedefineClassesClassDef is one of the Classes of
+// VirtualMachineRedefineClassesRequest.
+type VirtualMachineRedefineClassesClassDef struct {
+	RefType      ClassId // The reference type.
+	NumClassfile int     // Number of bytes defining class (below)
+	Classfile    []uint8 `jdwp:"counter:NumClassfile"`
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineRedefineClassesRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineRedefineClasses, r, nil)
+}
+
+// VirtualMachineSetDefaultStratumRequest is the VirtualMachine.SetDefaultStratum command. Set
+// the default stratum. It can fail with ErrNotImplemented, ErrVmDead.
+type VirtualMachineSetDefaultStratumRequest struct {
+	StratumId string // default stratum, or empty string to use reference type default.
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineSetDefaultStratumRequest) Call(c Client) error {
+	return request(c, VirtualMachine, VirtualMachineSetDefaultStratum, r, nil)
+}
+
+// VirtualMachineAllClassesWithGenericRequest is the VirtualMachine.AllClassesWithGeneric
+// command. Returns reference types for all classes currently loaded by the target VM, with
+// their generic signatures. It can fail with ErrVmDead.
+type VirtualMachineAllClassesWithGenericRequest struct{}
+
+// VirtualMachineAllClassesWithGenericReply is the reply to
+// VirtualMachine.AllClassesWithGeneric.
+type VirtualMachineAllClassesWithGenericReply struct {
+	NumClasses int                                            // Number of reference types that follow.
+	Classes    []VirtualMachineAllClassesWithGenericClassInfo `jdwp:"counter:NumClasses"`
+}
+
+// VirtualMachineAllClassesWithGenericClassInfo is one of the Classes of
+// VirtualMachineAllClassesWithGenericReply.
+type VirtualMachineAllClassesWithGenericClassInfo struct {
+	RefTypeTag       uint8   // Kind of following reference type.
+	TypeId           ClassId // Loaded reference type
+	Signature        string  // The JNI signature of the loaded reference type.
+	GenericSignature string  // The generic signature of the loaded reference type or an empty string if there is none.
+	Status           int     // The current class status.
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineAllClassesWithGenericRequest) Call(c Client) (*VirtualMachineAllClassesWithGenericReply, error) {
+	var reply VirtualMachineAllClassesWithGenericReply
+	if err := request(c, VirtualMachine, VirtualMachineAllClassesWithGeneric, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineInstanceCountsRequest is the VirtualMachine.InstanceCounts command. Returns the
+// number of instances of each reference type in the input list. It can fail with
+// ErrIllegalArgument, ErrNotImplemented, ErrVmDead.
+type VirtualMachineInstanceCountsRequest struct {
+	NumRefTypesCount int       // Number of reference types that follow.
+	RefTypesCount    []ClassId `jdwp:"counter:NumRefTypesCount"`
+}
+
+// VirtualMachineInstanceCountsReply is the reply to VirtualMachine.InstanceCounts.
+type VirtualMachineInstanceCountsReply struct {
+	NumCounts int     // The number of counts that follow.
+	Counts    []int64 `jdwp:"counter:NumCounts"`
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineInstanceCountsRequest) Call(c Client) (*VirtualMachineInstanceCountsReply, error) {
+	var reply VirtualMachineInstanceCountsReply
+	if err := request(c, VirtualMachine, VirtualMachineInstanceCounts, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VirtualMachineAllModulesRequest is the VirtualMachine.AllModules command. Returns all modules
+// in the target VM. Since JDWP version 9. It can fail with ErrNotImplemented, ErrVmDead.
+type VirtualMachineAllModulesRequest struct{}
+
+// VirtualMachineAllModulesReply is the reply to VirtualMachine.AllModules.
+type VirtualMachineAllModulesReply struct {
+	NumModules int        // The number of the modules that follow.
+	Modules    []ModuleId `jdwp:"counter:NumModules"`
+}
+
+// Call sends the command, returning its reply.
+func (r VirtualMachineAllModulesRequest) Call(c Client) (*VirtualMachineAllModulesReply, error) {
+	var reply VirtualMachineAllModulesReply
+	if err := request(c, VirtualMachine, VirtualMachineAllModules, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ReferenceTypeSignatureRequest is the ReferenceType.Signature command. Returns the type
+// signature of a reference type. It can fail with ErrInvalidClass, ErrInvalidObject, ErrVmDead.
+type ReferenceTypeSignatureRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeSignatureReply is the reply to ReferenceType.Signature.
+type ReferenceTypeSignatureReply struct {
+	Signature string // The JNI signature for the reference type.
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeSignatureRequest) Call(c Client) (*ReferenceTypeSignatureReply, error) {
+	var reply ReferenceTypeSignatureReply
+	if err := request(c, ReferenceType, ReferenceTypeSignature, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ReferenceTypeSignature sends ReferenceType.Signature. Returns the type signature of a
+// reference type.
+func (id ClassId) ReferenceTypeSignature(c Client) (*ReferenceTypeSignatureReply, error) {
+	return ReferenceTypeSignatureRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeClassLoaderRequest is the ReferenceType.ClassLoader command. Returns the
+// instance of java.lang.ClassLoader which loaded a given reference type. It can fail with
+// ErrInvalidClass, ErrInvalidObject, ErrVmDead.
+type ReferenceTypeClassLoaderRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeClassLoaderReply is the reply to ReferenceType.ClassLoader.
+type ReferenceTypeClassLoaderReply struct {
+	ClassLoader ClassLoaderId // The class loader for the reference type.
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeClassLoaderRequest) Call(c Client) (*ReferenceTypeClassLoaderReply, error) {
+	var reply ReferenceTypeClassLoaderReply
+	if err := request(c, ReferenceType, ReferenceTypeClassLoader, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ClassLoader sends ReferenceType.ClassLoader. Returns the instance of java.lang.ClassLoader
+// which loaded a given reference type.
+func (id ClassId) ClassLoader(c Client) (*ReferenceTypeClassLoaderReply, error) {
+	return ReferenceTypeClassLoaderRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeModifiersRequest is the ReferenceType.Modifiers command. Returns the modifiers
+// (also known as access flags) for a reference type. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrClassNotPrepared, ErrVmDead.
+type ReferenceTypeModifiersRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeModifiersReply is the reply to ReferenceType.Modifiers.
+type ReferenceTypeModifiersReply struct {
+	ModBits int // Modifier bits as defined in Chapter 4 of The Java Virtual Machine Specification
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeModifiersRequest) Call(c Client) (*ReferenceTypeModifiersReply, error) {
+	var reply ReferenceTypeModifiersReply
+	if err := request(c, ReferenceType, ReferenceTypeModifiers, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Modifiers sends ReferenceType.Modifiers. Returns the modifiers (also known as access flags)
+// for a reference type.
+func (id ClassId) Modifiers(c Client) (*ReferenceTypeModifiersReply, error) {
+	return ReferenceTypeModifiersRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeFieldsRequest is the ReferenceType.Fields command. Returns information for each
+// field in a reference type. It can fail with ErrInvalidClass, ErrInvalidObject,
+// ErrClassNotPrepared, ErrVmDead.
+type ReferenceTypeFieldsRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeFieldsReply is the reply to ReferenceType.Fields.
+type ReferenceTypeFieldsReply struct {
+	NumDeclared int                            // Number of declared fields.
+	Declared    []ReferenceTypeFieldsFieldInfo `jdwp:"counter:NumDeclared"`
+}
+
+// ReferenceTypeFieldsFieldInfo is one of the Declared of ReferenceTypeFieldsReply.
+type ReferenceTypeFieldsFieldInfo struct {
+	FieldId   FieldId // Field ID.
+	Name      string  // Name of field.
+	Signature string  // JNI Signature of field.
+	ModBits   int     // The modifier bit flags (also known as access flags).
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeFieldsRequest) Call(c Client) (*ReferenceTypeFieldsReply, error) {
+	var reply ReferenceTypeFieldsReply
+	if err := request(c, ReferenceType, ReferenceTypeFields, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ReferenceTypeFields sends ReferenceType.Fields. Returns information for each field in a
+// reference type.
+func (id ClassId) ReferenceTypeFields(c Client) (*ReferenceTypeFieldsReply, error) {
+	return ReferenceTypeFieldsRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeMethodsRequest is the ReferenceType.Methods command. Returns information for
+// each method in a reference type. It can fail with ErrInvalidClass, ErrInvalidObject,
+// ErrClassNotPrepared, ErrVmDead.
+type ReferenceTypeMethodsRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeMethodsReply is the reply to ReferenceType.Methods.
+type ReferenceTypeMethodsReply struct {
+	NumDeclared int                              // Number of declared methods.
+	Declared    []ReferenceTypeMethodsMethodInfo `jdwp:"counter:NumDeclared"`
+}
+
+// ReferenceTypeMethodsMethodInfo is one of the Declared of ReferenceTypeMethodsReply.
+type ReferenceTypeMethodsMethodInfo struct {
+	MethodId  MethodId // Method ID.
+	Name      string   // Name of method.
+	Signature string   // JNI signature of method.
+	ModBits   int      // The modifier bit flags (also known as access flags).
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeMethodsRequest) Call(c Client) (*ReferenceTypeMethodsReply, error) {
+	var reply ReferenceTypeMethodsReply
+	if err := request(c, ReferenceType, ReferenceTypeMethods, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ReferenceTypeMethods sends ReferenceType.Methods. Returns information for each method in a
+// reference type.
+func (id ClassId) ReferenceTypeMethods(c Client) (*ReferenceTypeMethodsReply, error) {
+	return ReferenceTypeMethodsRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeGetValuesRequest is the ReferenceType.GetValues command. Returns the value of
+// one or more static fields of the reference type. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrInvalidFieldId, ErrVmDead.
+type ReferenceTypeGetValuesRequest struct {
+	RefType   ClassId                       // The reference type ID.
+	NumFields int                           // The number of values to get
+	Fields    []ReferenceTypeGetValuesField `jdwp:"counter:NumFields"`
+}
+
+// ReferenceTypeGetValuesField is one of the Fields of ReferenceTypeGetValuesRequest.
+type ReferenceTypeGetValuesField struct {
+	FieldId FieldId // A field to get
+}
+
+// ReferenceTypeGetValuesReply is the reply to ReferenceType.GetValues.
+type ReferenceTypeGetValuesReply struct {
+	NumValues int           // The number of values returned, always equal to fields, the number of values to get.
+	Values    []TaggedValue `jdwp:"counter:NumValues"`
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeGetValuesRequest) Call(c Client) (*ReferenceTypeGetValuesReply, error) {
+	var reply ReferenceTypeGetValuesReply
+	if err := request(c, ReferenceType, ReferenceTypeGetValues, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// GetValues sends ReferenceType.GetValues. Returns the value of one or more static fields of
+// the reference type.
+func (id ClassId) GetValues(c Client, fields []ReferenceTypeGetValuesField) (*ReferenceTypeGetValuesReply, error) {
+	return ReferenceTypeGetValuesRequest{RefType: id, Fields: fields}.Call(c)
+}
+
+// ReferenceTypeSourceFileRequest is the ReferenceType.SourceFile command. Returns the name of
+// source file in which a reference type was declared. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrAbsentInformation, ErrVmDead.
+type ReferenceTypeSourceFileRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeSourceFileReply is the reply to ReferenceType.SourceFile.
+type ReferenceTypeSourceFileReply struct {
+	SourceFile string // The source file name.
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeSourceFileRequest) Call(c Client) (*ReferenceTypeSourceFileReply, error) {
+	var reply ReferenceTypeSourceFileReply
+	if err := request(c, ReferenceType, ReferenceTypeSourceFile, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// SourceFile sends ReferenceType.SourceFile. Returns the name of source file in which a
+// reference type was declared.
+func (id ClassId) SourceFile(c Client) (*ReferenceTypeSourceFileReply, error) {
+	return ReferenceTypeSourceFileRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeNestedTypesRequest is the ReferenceType.NestedTypes command. Returns the classes
+// and interfaces directly nested within this type. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrVmDead.
+type ReferenceTypeNestedTypesRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeNestedTypesReply is the reply to ReferenceType.NestedTypes.
+type ReferenceTypeNestedTypesReply struct {
+	NumClasses int                                // The number of nested classes and interfaces
+	Classes    []ReferenceTypeNestedTypesTypeInfo `jdwp:"counter:NumClasses"`
+}
+
+// ReferenceTypeNestedTypesTypeInfo is one of the Classes of ReferenceTypeNestedTypesReply.
+type ReferenceTypeNestedTypesTypeInfo struct {
+	RefTypeTag uint8   // Kind of following reference type.
+	TypeId     ClassId // The nested class or interface ID.
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeNestedTypesRequest) Call(c Client) (*ReferenceTypeNestedTypesReply, error) {
+	var reply ReferenceTypeNestedTypesReply
+	if err := request(c, ReferenceType, ReferenceTypeNestedTypes, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// NestedTypes sends ReferenceType.NestedTypes. Returns the classes and interfaces directly
+// nested within this type.
+func (id ClassId) NestedTypes(c Client) (*ReferenceTypeNestedTypesReply, error) {
+	return ReferenceTypeNestedTypesRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeStatusRequest is the ReferenceType.Status command. Returns the current status of
+// the reference type. It can fail with ErrInvalidClass, ErrInvalidObject, ErrVmDead.
+type ReferenceTypeStatusRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeStatusReply is the reply to ReferenceType.Status.
+type ReferenceTypeStatusReply struct {
+	Status int // Status bits: See JDWP.ClassStatus
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeStatusRequest) Call(c Client) (*ReferenceTypeStatusReply, error) {
+	var reply ReferenceTypeStatusReply
+	if err := request(c, ReferenceType, ReferenceTypeStatus, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Status sends ReferenceType.Status. Returns the current status of the reference type.
+func (id ClassId) Status(c Client) (*ReferenceTypeStatusReply, error) {
+	return ReferenceTypeStatusRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeInterfacesRequest is the ReferenceType.Interfaces command. Returns the
+// interfaces declared as implemented by this class. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrClassNotPrepared, ErrVmDead.
+type ReferenceTypeInterfacesRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeInterfacesReply is the reply to ReferenceType.Interfaces.
+type ReferenceTypeInterfacesReply struct {
+	NumInterfaces int           // The number of implemented interfaces
+	Interfaces    []InterfaceId `jdwp:"counter:NumInterfaces"`
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeInterfacesRequest) Call(c Client) (*ReferenceTypeInterfacesReply, error) {
+	var reply ReferenceTypeInterfacesReply
+	if err := request(c, ReferenceType, ReferenceTypeInterfaces, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Interfaces sends ReferenceType.Interfaces. Returns the interfaces declared as implemented by
+// this class.
+func (id ClassId) Interfaces(c Client) (*ReferenceTypeInterfacesReply, error) {
+	return ReferenceTypeInterfacesRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeClassObjectRequest is the ReferenceType.ClassObject command. Returns the class
+// object corresponding to this type. It can fail with ErrInvalidClass, ErrInvalidObject,
+// ErrVmDead.
+type ReferenceTypeClassObjectRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeClassObjectReply is the reply to ReferenceType.ClassObject.
+type ReferenceTypeClassObjectReply struct {
+	ClassObject ClassObjectId // class object.
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeClassObjectRequest) Call(c Client) (*ReferenceTypeClassObjectReply, error) {
+	var reply ReferenceTypeClassObjectReply
+	if err := request(c, ReferenceType, ReferenceTypeClassObject, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ClassObject sends ReferenceType.ClassObject. Returns the class object corresponding to this
+// type.
+func (id ClassId) ClassObject(c Client) (*ReferenceTypeClassObjectReply, error) {
+	return ReferenceTypeClassObjectRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeSourceDebugExtensionRequest is the ReferenceType.SourceDebugExtension command.
+// Returns the value of the SourceDebugExtension attribute. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrAbsentInformation, ErrNotImplemented, ErrVmDead.
+type ReferenceTypeSourceDebugExtensionRequest struct {
+	RefType ClassId // The type.
+}
+
+// ReferenceTypeSourceDebugExtensionReply is the reply to ReferenceType.SourceDebugExtension.
+type ReferenceTypeSourceDebugExtensionReply struct {
+	Extension string // extension attribute
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeSourceDebugExtensionRequest) Call(c Client) (*ReferenceTypeSourceDebugExtensionReply, error) {
+	var reply ReferenceTypeSourceDebugExtensionReply
+	if err := request(c, ReferenceType, ReferenceTypeSourceDebugExtension, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// SourceDebugExtension sends ReferenceType.SourceDebugExtension. Returns the value of the
+// SourceDebugExtension attribute.
+func (id ClassId) SourceDebugExtension(c Client) (*ReferenceTypeSourceDebugExtensionReply, error) {
+	return ReferenceTypeSourceDebugExtensionRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeSignatureWithGenericRequest is the ReferenceType.SignatureWithGeneric command.
+// Returns the JNI signature of a reference type along with the generic signature if there is
+// one. It can fail with ErrInvalidClass, ErrInvalidObject, ErrVmDead.
+type ReferenceTypeSignatureWithGenericRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeSignatureWithGenericReply is the reply to ReferenceType.SignatureWithGeneric.
+type ReferenceTypeSignatureWithGenericReply struct {
+	Signature        string // The JNI signature for the reference type.
+	GenericSignature string // The generic signature for the reference type or an empty string if there is none.
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeSignatureWithGenericRequest) Call(c Client) (*ReferenceTypeSignatureWithGenericReply, error) {
+	var reply ReferenceTypeSignatureWithGenericReply
+	if err := request(c, ReferenceType, ReferenceTypeSignatureWithGeneric, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// SignatureWithGeneric sends ReferenceType.SignatureWithGeneric. Returns the JNI signature of a
+// reference type along with the generic signature if there is one.
+func (id ClassId) SignatureWithGeneric(c Client) (*ReferenceTypeSignatureWithGenericReply, error) {
+	return ReferenceTypeSignatureWithGenericRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeFieldsWithGenericRequest is the ReferenceType.FieldsWithGeneric command. Returns
+// information, including the generic signature if any, for each field in a reference type. It
+// can fail with ErrInvalidClass, ErrInvalidObject, ErrClassNotPrepared, ErrVmDead.
+type ReferenceTypeFieldsWithGenericRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeFieldsWithGenericReply is the reply to ReferenceType.FieldsWithGeneric.
+type ReferenceTypeFieldsWithGenericReply struct {
+	NumDeclared int                                       // Number of declared fields.
+	Declared    []ReferenceTypeFieldsWithGenericFieldInfo `jdwp:"counter:NumDeclared"`
+}
+
+// ReferenceTypeFieldsWithGenericFieldInfo is one of the Declared of
+// ReferenceTypeFieldsWithGenericReply.
+type ReferenceTypeFieldsWithGenericFieldInfo struct {
+	FieldId          FieldId // Field ID.
+	Name             string  // The name of the field.
+	Signature        string  // The JNI signature of the field.
+	GenericSignature string  // The generic signature of the field, or an empty string if there is none.
+	ModBits          int     // The modifier bit flags (also known as access flags).
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeFieldsWithGenericRequest) Call(c Client) (*ReferenceTypeFieldsWithGenericReply, error) {
+	var reply ReferenceTypeFieldsWithGenericReply
+	if err := request(c, ReferenceType, ReferenceTypeFieldsWithGeneric, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// FieldsWithGeneric sends ReferenceType.FieldsWithGeneric. Returns information, including the
+// generic signature if any, for each field in a reference type.
+func (id ClassId) FieldsWithGeneric(c Client) (*ReferenceTypeFieldsWithGenericReply, error) {
+	return ReferenceTypeFieldsWithGenericRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeMethodsWithGenericRequest is the ReferenceType.MethodsWithGeneric command.
+// Returns information, including the generic signature if any, for each method in a reference
+// type. It can fail with ErrInvalidClass, ErrInvalidObject, ErrClassNotPrepared, ErrVmDead.
+type ReferenceTypeMethodsWithGenericRequest struct {
+	RefType ClassId // The reference type ID.
+}
+
+// ReferenceTypeMethodsWithGenericReply is the reply to ReferenceType.MethodsWithGeneric.
+type ReferenceTypeMethodsWithGenericReply struct {
+	NumDeclared int                                         // Number of declared methods.
+	Declared    []ReferenceTypeMethodsWithGenericMethodInfo `jdwp:"counter:NumDeclared"`
+}
+
+// ReferenceTypeMethodsWithGenericMethodInfo is one of the Declared of
+// ReferenceTypeMethodsWithGenericReply.
+type ReferenceTypeMethodsWithGenericMethodInfo struct {
+	MethodId         MethodId // Method ID.
+	Name             string   // The name of the method.
+	Signature        string   // The JNI signature of the method.
+	GenericSignature string   // The generic signature of the method, or an empty string if there is none.
+	ModBits          int      // The modifier bit flags (also known as access flags).
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeMethodsWithGenericRequest) Call(c Client) (*ReferenceTypeMethodsWithGenericReply, error) {
+	var reply ReferenceTypeMethodsWithGenericReply
+	if err := request(c, ReferenceType, ReferenceTypeMethodsWithGeneric, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// MethodsWithGeneric sends ReferenceType.MethodsWithGeneric. Returns information, including the
+// generic signature if any, for each method in a reference type.
+func (id ClassId) MethodsWithGeneric(c Client) (*ReferenceTypeMethodsWithGenericReply, error) {
+	return ReferenceTypeMethodsWithGenericRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeInstancesRequest is the ReferenceType.Instances command. Returns instances of
+// this reference type. It can fail with ErrIllegalArgument, ErrInvalidClass, ErrInvalidObject,
+// ErrNotImplemented, ErrVmDead.
+type ReferenceTypeInstancesRequest struct {
+	RefType      ClassId // The reference type ID.
+	MaxInstances int     // Maximum number of instances to return.
+}
+
+// ReferenceTypeInstancesReply is the reply to ReferenceType.Instances.
+type ReferenceTypeInstancesReply struct {
+	NumInstances int              // The number of instances that follow.
+	Instances    []TaggedObjectId `jdwp:"counter:NumInstances"`
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeInstancesRequest) Call(c Client) (*ReferenceTypeInstancesReply, error) {
+	var reply ReferenceTypeInstancesReply
+	if err := request(c, ReferenceType, ReferenceTypeInstances, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Instances sends ReferenceType.Instances. Returns instances of this reference type.
+func (id ClassId) Instances(c Client, maxInstances int) (*ReferenceTypeInstancesReply, error) {
+	return ReferenceTypeInstancesRequest{RefType: id, MaxInstances: maxInstances}.Call(c)
+}
+
+// ReferenceTypeClassFileVersionRequest is the ReferenceType.ClassFileVersion command. Returns
+// the class file major and minor version numbers, as defined in the class file format of the
+// Java Virtual Machine specification. It can fail with ErrInvalidClass, ErrInvalidObject,
+// ErrAbsentInformation, ErrNotImplemented, ErrVmDead.
+type ReferenceTypeClassFileVersionRequest struct {
+	RefType ClassId // The class.
+}
+
+// ReferenceTypeClassFileVersionReply is the reply to ReferenceType.ClassFileVersion.
+type ReferenceTypeClassFileVersionReply struct {
+	MajorVersion int // Major version number
+	MinorVersion int // Minor version number
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeClassFileVersionRequest) Call(c Client) (*ReferenceTypeClassFileVersionReply, error) {
+	var reply ReferenceTypeClassFileVersionReply
+	if err := request(c, ReferenceType, ReferenceTypeClassFileVersion, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ClassFileVersion sends ReferenceType.ClassFileVersion. Returns the class file major and minor
+// version numbers, as defined in the class file format of the Java Virtual Machine
+// specification.
+func (id ClassId) ClassFileVersion(c Client) (*ReferenceTypeClassFileVersionReply, error) {
+	return ReferenceTypeClassFileVersionRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeConstantPoolRequest is the ReferenceType.ConstantPool command. Return the raw
+// bytes of the constant pool in the format of the constant_pool item of the Class File Format
+// in The Java Virtual Machine Specification. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrAbsentInformation, ErrNotImplemented, ErrVmDead.
+type ReferenceTypeConstantPoolRequest struct {
+	RefType ClassId // The class.
+}
+
+// ReferenceTypeConstantPoolReply is the reply to ReferenceType.ConstantPool.
+type ReferenceTypeConstantPoolReply struct {
+	Count    int     // Total number of constant pool entries plus one.
+	NumBytes int     // Number of bytes that follow.
+	Bytes    []uint8 `jdwp:"counter:NumBytes"`
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeConstantPoolRequest) Call(c Client) (*ReferenceTypeConstantPoolReply, error) {
+	var reply ReferenceTypeConstantPoolReply
+	if err := request(c, ReferenceType, ReferenceTypeConstantPool, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ConstantPool sends ReferenceType.ConstantPool. Return the raw bytes of the constant pool in
+// the format of the constant_pool item of the Class File Format in The Java Virtual Machine
+// Specification.
+func (id ClassId) ConstantPool(c Client) (*ReferenceTypeConstantPoolReply, error) {
+	return ReferenceTypeConstantPoolRequest{RefType: id}.Call(c)
+}
+
+// ReferenceTypeModuleRequest is the ReferenceType.Module command. Returns the module that this
+// reference type belongs to. Since JDWP version 9. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrNotImplemented, ErrVmDead.
+type ReferenceTypeModuleRequest struct {
+	RefType ClassId // The reference type.
+}
+
+// ReferenceTypeModuleReply is the reply to ReferenceType.Module.
+type ReferenceTypeModuleReply struct {
+	Module ModuleId // The module this reference type belongs to.
+}
+
+// Call sends the command, returning its reply.
+func (r ReferenceTypeModuleRequest) Call(c Client) (*ReferenceTypeModuleReply, error) {
+	var reply ReferenceTypeModuleReply
+	if err := request(c, ReferenceType, ReferenceTypeModule, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Module sends ReferenceType.Module. Returns the module that this reference type belongs to.
+func (id ClassId) Module(c Client) (*ReferenceTypeModuleReply, error) {
+	return ReferenceTypeModuleRequest{RefType: id}.Call(c)
+}
+
+// ClassTypeSuperclassRequest is the ClassType.Superclass command. Returns the immediate
+// superclass of a class. It can fail with ErrInvalidClass, ErrInvalidObject, ErrVmDead.
+type ClassTypeSuperclassRequest struct {
+	Clazz ClassId // The class type ID.
+}
+
+// ClassTypeSuperclassReply is the reply to ClassType.Superclass.
+type ClassTypeSuperclassReply struct {
+	Superclass ClassId // The superclass (null if the class ID for java.lang.Object is specified).
+}
+
+// Call sends the command, returning its reply.
+func (r ClassTypeSuperclassRequest) Call(c Client) (*ClassTypeSuperclassReply, error) {
+	var reply ClassTypeSuperclassReply
+	if err := request(c, ClassType, ClassTypeSuperclass, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Superclass sends ClassType.Superclass. Returns the immediate superclass of a class.
+func (id ClassId) Superclass(c Client) (*ClassTypeSuperclassReply, error) {
+	return ClassTypeSuperclassRequest{Clazz: id}.Call(c)
+}
+
+// ClassTypeSetValuesRequest is the ClassType.SetValues command. Sets the value of one or more
+// static fields. It can fail with ErrInvalidClass, ErrClassNotPrepared, ErrInvalidObject,
+// ErrInvalidFieldId, ErrVmDead.
+type ClassTypeSetValuesRequest struct {
+	Clazz     ClassId                        // The class type ID.
+	NumValues int                            // The number of fields to set.
+	Values    []ClassTypeSetValuesFieldValue `jdwp:"counter:NumValues"`
+}
+
+// ClassTypeSetValuesFieldValue is one of the Values of ClassTypeSetValuesRequest.
+type ClassTypeSetValuesFieldValue struct {
+	FieldId FieldId       // Field to set.
+	Value   UntaggedValue // Value to put in the field.
+}
+
+// Call sends the command, returning its reply.
+func (r ClassTypeSetValuesRequest) Call(c Client) error {
+	return request(c, ClassType, ClassTypeSetValues, r, nil)
+}
+
+// SetValues sends ClassType.SetValues. Sets the value of one or more static fields.
+func (id ClassId) SetValues(c Client, values []ClassTypeSetValuesFieldValue) error {
+	return ClassTypeSetValuesRequest{Clazz: id, Values: values}.Call(c)
+}
+
+// ClassTypeInvokeMethodRequest is the ClassType.InvokeMethod command. Invokes a static method.
+// It can fail with ErrInvalidClass, ErrInvalidObject, ErrInvalidMethodId, ErrInvalidThread,
+// ErrThreadNotSuspended, ErrVmDead.
+type ClassTypeInvokeMethodRequest struct {
+	Clazz        ClassId       // The class type ID.
+	Thread       ThreadId      // The thread in which to invoke.
+	MethodId     MethodId      // The method to invoke.
+	NumArguments int           // The number of arguments.
+	Arguments    []TaggedValue `jdwp:"counter:NumArguments"`
+	Options      int           // Invocation options
+}
+
+// ClassTypeInvokeMethodReply is the reply to ClassType.InvokeMethod.
+type ClassTypeInvokeMethodReply struct {
+	ReturnValue TaggedValue    // The returned value.
+	Exception   TaggedObjectId // The thrown exception.
+}
+
+// Call sends the command, returning its reply.
+func (r ClassTypeInvokeMethodRequest) Call(c Client) (*ClassTypeInvokeMethodReply, error) {
+	var reply ClassTypeInvokeMethodReply
+	if err := request(c, ClassType, ClassTypeInvokeMethod, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// InvokeMethod sends ClassType.InvokeMethod. Invokes a static method.
+func (id ClassId) InvokeMethod(c Client, thread ThreadId, methodId MethodId, arguments []TaggedValue, options int) (*ClassTypeInvokeMethodReply, error) {
+	return ClassTypeInvokeMethodRequest{Clazz: id, Thread: thread, MethodId: methodId, Arguments: arguments, Options: options}.Call(c)
+}
+
+// ClassTypeNewInstanceRequest is the ClassType.NewInstance command. Creates a new object of
+// this type, invoking the specified constructor. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrInvalidMethodId, ErrInvalidThread, ErrThreadNotSuspended, ErrVmDead.
+type ClassTypeNewInstanceRequest struct {
+	Clazz        ClassId       // The class type ID.
+	Thread       ThreadId      // The thread in which to invoke the constructor.
+	MethodId     MethodId      // The constructor to invoke.
+	NumArguments int           // The number of arguments.
+	Arguments    []TaggedValue `jdwp:"counter:NumArguments"`
+	Options      int           // Constructor invocation options
+}
+
+// ClassTypeNewInstanceReply is the reply to ClassType.NewInstance.
+type ClassTypeNewInstanceReply struct {
+	NewObject TaggedObjectId // The newly created object, or null if the constructor threw an exception.
+	Exception TaggedObjectId // The thrown exception, if any; otherwise, null.
+}
+
+// Call sends the command, returning its reply.
+func (r ClassTypeNewInstanceRequest) Call(c Client) (*ClassTypeNewInstanceReply, error) {
+	var reply ClassTypeNewInstanceReply
+	if err := request(c, ClassType, ClassTypeNewInstance, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// NewInstance sends ClassType.NewInstance. Creates a new object of this type, invoking the
+// specified constructor.
+func (id ClassId) NewInstance(c Client, thread ThreadId, methodId MethodId, arguments []TaggedValue, options int) (*ClassTypeNewInstanceReply, error) {
+	return ClassTypeNewInstanceRequest{Clazz: id, Thread: thread, MethodId: methodId, Arguments: arguments, Options: options}.Call(c)
+}
+
+// ArrayTypeNewInstanceRequest is the ArrayType.NewInstance command. Creates a new array object
+// of this type with a given length. It can fail with ErrInvalidArray, ErrInvalidObject,
+// ErrVmDead.
+type ArrayTypeNewInstanceRequest struct {
+	ArrType ArrayTypeId // The array type of the new instance.
+	Length  int         // The length of the array.
+}
+
+// ArrayTypeNewInstanceReply is the reply to ArrayType.NewInstance.
+type ArrayTypeNewInstanceReply struct {
+	NewArray TaggedObjectId // The newly created array object.
+}
+
+// Call sends the command, returning its reply.
+func (r ArrayTypeNewInstanceRequest) Call(c Client) (*ArrayTypeNewInstanceReply, error) {
+	var reply ArrayTypeNewInstanceReply
+	if err := request(c, ArrayType, ArrayTypeNewInstance, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// NewInstance sends ArrayType.NewInstance. Creates a new array object of this type with a given
+// length.
+func (id ArrayTypeId) NewInstance(c Client, length int) (*ArrayTypeNewInstanceReply, error) {
+	return ArrayTypeNewInstanceRequest{ArrType: id, Length: length}.Call(c)
+}
+
+// InterfaceTypeInvokeMethodRequest is the InterfaceType.InvokeMethod command. Invokes a static
+// method. Since JDWP version 1.8 It can fail with ErrInvalidClass, ErrInvalidObject,
+// ErrInvalidMethodId, ErrInvalidThread, ErrThreadNotSuspended, ErrVmDead.
+type InterfaceTypeInvokeMethodRequest struct {
+	Clazz        InterfaceId   // The interface type ID.
+	Thread       ThreadId      // The thread in which to invoke.
+	MethodId     MethodId      // The method to invoke.
+	NumArguments int           // The number of arguments.
+	Arguments    []TaggedValue `jdwp:"counter:NumArguments"`
+	Options      int           // Invocation options
+}
+
+// InterfaceTypeInvokeMethodReply is the reply to InterfaceType.InvokeMethod.
+type InterfaceTypeInvokeMethodReply struct {
+	ReturnValue TaggedValue    // The returned value.
+	Exception   TaggedObjectId // The thrown exception.
+}
+
+// Call sends the command, returning its reply.
+func (r InterfaceTypeInvokeMethodRequest) Call(c Client) (*InterfaceTypeInvokeMethodReply, error) {
+	var reply InterfaceTypeInvokeMethodReply
+	if err := request(c, InterfaceType, InterfaceTypeInvokeMethod, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// InvokeMethod sends InterfaceType.InvokeMethod. Invokes a static method.
+func (id InterfaceId) InvokeMethod(c Client, thread ThreadId, methodId MethodId, arguments []TaggedValue, options int) (*InterfaceTypeInvokeMethodReply, error) {
+	return InterfaceTypeInvokeMethodRequest{Clazz: id, Thread: thread, MethodId: methodId, Arguments: arguments, Options: options}.Call(c)
+}
+
+// MethodLineTableRequest is the Method.LineTable command. Returns line number information for
+// the method, if present. It can fail with ErrInvalidClass, ErrInvalidObject,
+// ErrInvalidMethodId, ErrAbsentInformation, ErrNativeMethod, ErrVmDead.
+type MethodLineTableRequest struct {
+	RefType  ClassId  // The class.
+	MethodId MethodId // The method.
+}
+
+// MethodLineTableReply is the reply to Method.LineTable.
+type MethodLineTableReply struct {
+	Start    int64                     // Lowest valid code index for the method, >=0, or -1 if the method is native
+	End      int64                     // Highest valid code index for the method, >=0, or -1 if the method is native
+	NumLines int                       // The number of entries in the line table for this method.
+	Lines    []MethodLineTableLineInfo `jdwp:"counter:NumLines"`
+}
+
+// MethodLineTableLineInfo is one of the Lines of MethodLineTableReply.
+type MethodLineTableLineInfo struct {
+	LineCodeIndex int64 // Initial code index of the line, start <= lineCodeIndex < end
+	LineNumber    int   // Line number.
+}
+
+// Call sends the command, returning its reply.
+func (r MethodLineTableRequest) Call(c Client) (*MethodLineTableReply, error) {
+	var reply MethodLineTableReply
+	if err := request(c, Method, MethodLineTable, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// MethodLineTable sends Method.LineTable. Returns line number information for the method, if
+// present.
+func (m MethodId) MethodLineTable(c Client) (*MethodLineTableReply, error) {
+	return MethodLineTableRequest{RefType: m.ref, MethodId: m}.Call(c)
+}
+
+// MethodVariableTableRequest is the Method.VariableTable command. Returns variable information
+// for the method. It can fail with ErrInvalidClass, ErrInvalidObject, ErrInvalidMethodId,
+// ErrAbsentInformation, ErrNativeMethod, ErrVmDead.
+type MethodVariableTableRequest struct {
+	RefType  ClassId  // The class.
+	MethodId MethodId // The method.
+}
+
+// MethodVariableTableReply is the reply to Method.VariableTable.
+type MethodVariableTableReply struct {
+	ArgCnt   int                           // The number of words in the frame used by arguments.
+	NumSlots int                           // The number of variables.
+	Slots    []MethodVariableTableSlotInfo `jdwp:"counter:NumSlots"`
+}
+
+// MethodVariableTableSlotInfo is one of the Slots of MethodVariableTableReply.
+type MethodVariableTableSlotInfo struct {
+	CodeIndex int64  // First code index at which the variable is visible (unsigned).
+	Name      string // The variable's name.
+	Signature string // The variable type's JNI signature.
+	Length    int    // Unsigned value used in conjunction with codeIndex.
+	Slot      int    // The local variable's index in its frame
+}
+
+// Call sends the command, returning its reply.
+func (r MethodVariableTableRequest) Call(c Client) (*MethodVariableTableReply, error) {
+	var reply MethodVariableTableReply
+	if err := request(c, Method, MethodVariableTable, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// MethodVariableTable sends Method.VariableTable. Returns variable information for the method.
+func (m MethodId) MethodVariableTable(c Client) (*MethodVariableTableReply, error) {
+	return MethodVariableTableRequest{RefType: m.ref, MethodId: m}.Call(c)
+}
+
+// MethodBytecodesRequest is the Method.Bytecodes command. Retrieve the method's bytecodes as
+// defined in The Java Virtual Machine Specification. It can fail with ErrInvalidClass,
+// ErrInvalidObject, ErrInvalidMethodId, ErrNotImplemented, ErrVmDead.
+type MethodBytecodesRequest struct {
+	RefType  ClassId  // The class.
+	MethodId MethodId // The method.
+}
+
+// MethodBytecodesReply is the reply to Method.Bytecodes.
+type MethodBytecodesReply struct {
+	NumBytes int     // Number of bytes that follow
+	Bytes    []uint8 `jdwp:"counter:NumBytes"`
+}
+
+// Call sends the command, returning its reply.
+func (r MethodBytecodesRequest) Call(c Client) (*MethodBytecodesReply, error) {
+	var reply MethodBytecodesReply
+	if err := request(c, Method, MethodBytecodes, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Bytecodes sends Method.Bytecodes. Retrieve the method's bytecodes as defined in The Java
+// Virtual Machine Specification.
+func (m MethodId) Bytecodes(c Client) (*MethodBytecodesReply, error) {
+	return MethodBytecodesRequest{RefType: m.ref, MethodId: m}.Call(c)
+}
+
+// MethodIsObsoleteRequest is the Method.IsObsolete command. Determine if this method is
+// obsolete. It can fail with ErrInvalidClass, ErrInvalidObject, ErrInvalidMethodId,
+// ErrNotImplemented, ErrVmDead.
+type MethodIsObsoleteRequest struct {
+	RefType  ClassId  // The class.
+	MethodId MethodId // The method ID.
+}
+
+// MethodIsObsoleteReply is the reply to Method.IsObsolete.
+type MethodIsObsoleteReply struct {
+	IsObsolete bool // true if this method has been replaced by a non-equivalent method using the RedefineClasses command.
+}
+
+// Call sends the command, returning its reply.
+func (r MethodIsObsoleteRequest) Call(c Client) (*MethodIsObsoleteReply, error) {
+	var reply MethodIsObsoleteReply
+	if err := request(c, Method, MethodIsObsolete, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// IsObsolete sends Method.IsObsolete. Determine if this method is obsolete.
+func (m MethodId) IsObsolete(c Client) (*MethodIsObsoleteReply, error) {
+	return MethodIsObsoleteRequest{RefType: m.ref, MethodId: m}.Call(c)
+}
+
+// MethodVariableTableWithGenericRequest is the Method.VariableTableWithGeneric command. Returns
+// variable information for the method, including generic signatures for the variables. It can
+// fail with ErrInvalidClass, ErrInvalidObject, ErrInvalidMethodId, ErrAbsentInformation,
+// ErrNativeMethod, ErrVmDead.
+type MethodVariableTableWithGenericRequest struct {
+	RefType  ClassId  // The class.
+	MethodId MethodId // The method.
+}
+
+// MethodVariableTableWithGenericReply is the reply to Method.VariableTableWithGeneric.
+type MethodVariableTableWithGenericReply struct {
+	ArgCnt   int                                      // The number of words in the frame used by arguments.
+	NumSlots int                                      // The number of variables.
+	Slots    []MethodVariableTableWithGenericSlotInfo `jdwp:"counter:NumSlots"`
+}
+
+// MethodVariableTableWithGenericSlotInfo is one of the Slots of
+// MethodVariableTableWithGenericReply.
+type MethodVariableTableWithGenericSlotInfo struct {
+	CodeIndex        int64  // First code index at which the variable is visible (unsigned).
+	Name             string // The variable's name.
+	Signature        string // The variable type's JNI signature.
+	GenericSignature string // The variable type's generic signature or an empty string if there is none.
+	Length           int    // Unsigned value used in conjunction with codeIndex.
+	Slot             int    // The local variable's index in its frame
+}
+
+// Call sends the command, returning its reply.
+func (r MethodVariableTableWithGenericRequest) Call(c Client) (*MethodVariableTableWithGenericReply, error) {
+	var reply MethodVariableTableWithGenericReply
+	if err := request(c, Method, MethodVariableTableWithGeneric, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VariableTableWithGeneric sends Method.VariableTableWithGeneric. Returns variable information
+// for the method, including generic signatures for the variables.
+func (m MethodId) VariableTableWithGeneric(c Client) (*MethodVariableTableWithGenericReply, error) {
+	return MethodVariableTableWithGenericRequest{RefType: m.ref, MethodId: m}.Call(c)
+}
+
+// ObjectReferenceReferenceTypeRequest is the ObjectReference.ReferenceType command. Returns the
+// runtime type of the object. It can fail with ErrInvalidObject, ErrVmDead.
+type ObjectReferenceReferenceTypeRequest struct {
+	Object ObjectId // The object ID
+}
+
+// ObjectReferenceReferenceTypeReply is the reply to ObjectReference.ReferenceType.
+type ObjectReferenceReferenceTypeReply struct {
+	RefTypeTag uint8   // Kind of following reference type.
+	TypeId     ClassId // The runtime reference type.
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceReferenceTypeRequest) Call(c Client) (*ObjectReferenceReferenceTypeReply, error) {
+	var reply ObjectReferenceReferenceTypeReply
+	if err := request(c, ObjectReference, ObjectReferenceReferenceType, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ObjectReferenceReferenceType sends ObjectReference.ReferenceType. Returns the runtime type of
+// the object.
+func (id ObjectId) ObjectReferenceReferenceType(c Client) (*ObjectReferenceReferenceTypeReply, error) {
+	return ObjectReferenceReferenceTypeRequest{Object: id}.Call(c)
+}
+
+// ObjectReferenceGetValuesRequest is the ObjectReference.GetValues command. Returns the value
+// of one or more instance fields. It can fail with ErrInvalidObject, ErrInvalidFieldId,
+// ErrVmDead.
+type ObjectReferenceGetValuesRequest struct {
+	Object    ObjectId                        // The object ID
+	NumFields int                             // The number of values to get
+	Fields    []ObjectReferenceGetValuesField `jdwp:"counter:NumFields"`
+}
+
+// ObjectReferenceGetValuesField is one of the Fields of ObjectReferenceGetValuesRequest.
+type ObjectReferenceGetValuesField struct {
+	FieldId FieldId // Field to get.
+}
+
+// ObjectReferenceGetValuesReply is the reply to ObjectReference.GetValues.
+type ObjectReferenceGetValuesReply struct {
+	NumValues int           // The number of values returned, always equal to 'fields', the number of values to get.
+	Values    []TaggedValue `jdwp:"counter:NumValues"`
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceGetValuesRequest) Call(c Client) (*ObjectReferenceGetValuesReply, error) {
+	var reply ObjectReferenceGetValuesReply
+	if err := request(c, ObjectReference, ObjectReferenceGetValues, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// GetValues sends ObjectReference.GetValues. Returns the value of one or more instance fields.
+func (id ObjectId) GetValues(c Client, fields []ObjectReferenceGetValuesField) (*ObjectReferenceGetValuesReply, error) {
+	return ObjectReferenceGetValuesRequest{Object: id, Fields: fields}.Call(c)
+}
+
+// ObjectReferenceSetValuesRequest is the ObjectReference.SetValues command. Sets the value of
+// one or more instance fields. It can fail with ErrInvalidObject, ErrInvalidFieldId, ErrVmDead.
+type ObjectReferenceSetValuesRequest struct {
+	Object    ObjectId                             // The object ID
+	NumValues int                                  // The number of fields to set.
+	Values    []ObjectReferenceSetValuesFieldValue `jdwp:"counter:NumValues"`
+}
+
+// ObjectReferenceSetValuesFieldValue is one of the Values of ObjectReferenceSetValuesRequest.
+type ObjectReferenceSetValuesFieldValue struct {
+	FieldId FieldId       // Field to set.
+	Value   UntaggedValue // Value to put in the field.
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceSetValuesRequest) Call(c Client) error {
+	return request(c, ObjectReference, ObjectReferenceSetValues, r, nil)
+}
+
+// SetValues sends ObjectReference.SetValues. Sets the value of one or more instance fields.
+func (id ObjectId) SetValues(c Client, values []ObjectReferenceSetValuesFieldValue) error {
+	return ObjectReferenceSetValuesRequest{Object: id, Values: values}.Call(c)
+}
+
+// ObjectReferenceMonitorInfoRequest is the ObjectReference.MonitorInfo command. Returns monitor
+// information for an object. It can fail with ErrInvalidObject, ErrNotImplemented, ErrVmDead.
+type ObjectReferenceMonitorInfoRequest struct {
+	Object ObjectId // The object ID
+}
+
+// ObjectReferenceMonitorInfoReply is the reply to ObjectReference.MonitorInfo.
+type ObjectReferenceMonitorInfoReply struct {
+	Owner      ThreadId   // The monitor owner, or null if it is not currently owned.
+	EntryCount int        // The number of times the monitor has been entered.
+	NumWaiters int        // The number of threads that are waiting for the monitor 0 if there is no current owner
+	Waiters    []ThreadId `jdwp:"counter:NumWaiters"`
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceMonitorInfoRequest) Call(c Client) (*ObjectReferenceMonitorInfoReply, error) {
+	var reply ObjectReferenceMonitorInfoReply
+	if err := request(c, ObjectReference, ObjectReferenceMonitorInfo, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// MonitorInfo sends ObjectReference.MonitorInfo. Returns monitor information for an object.
+func (id ObjectId) MonitorInfo(c Client) (*ObjectReferenceMonitorInfoReply, error) {
+	return ObjectReferenceMonitorInfoRequest{Object: id}.Call(c)
+}
+
+// ObjectReferenceInvokeMethodRequest is the ObjectReference.InvokeMethod command. Invokes a
+// instance method. It can fail with ErrInvalidObject, ErrInvalidClass, ErrInvalidMethodId,
+// ErrInvalidThread, ErrThreadNotSuspended, ErrVmDead.
+type ObjectReferenceInvokeMethodRequest struct {
+	Object       ObjectId      // The object ID
+	Thread       ThreadId      // The thread in which to invoke.
+	Clazz        ClassId       // The class type.
+	MethodId     MethodId      // The method to invoke.
+	NumArguments int           // The number of arguments.
+	Arguments    []TaggedValue `jdwp:"counter:NumArguments"`
+	Options      int           // Invocation options
+}
+
+// ObjectReferenceInvokeMethodReply is the reply to ObjectReference.InvokeMethod.
+type ObjectReferenceInvokeMethodReply struct {
+	ReturnValue TaggedValue    // The returned value, or null if an exception is thrown.
+	Exception   TaggedObjectId // The thrown exception, if any.
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceInvokeMethodRequest) Call(c Client) (*ObjectReferenceInvokeMethodReply, error) {
+	var reply ObjectReferenceInvokeMethodReply
+	if err := request(c, ObjectReference, ObjectReferenceInvokeMethod, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// InvokeMethod sends ObjectReference.InvokeMethod. Invokes a instance method.
+func (id ObjectId) InvokeMethod(c Client, thread ThreadId, clazz ClassId, methodId MethodId, arguments []TaggedValue, options int) (*ObjectReferenceInvokeMethodReply, error) {
+	return ObjectReferenceInvokeMethodRequest{Object: id, Thread: thread, Clazz: clazz, MethodId: methodId, Arguments: arguments, Options: options}.Call(c)
+}
+
+// ObjectReferenceDisableCollectionRequest is the ObjectReference.DisableCollection command.
+// Prevents garbage collection for the given object. It can fail with ErrInvalidObject,
+// ErrVmDead.
+type ObjectReferenceDisableCollectionRequest struct {
+	Object ObjectId // The object ID
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceDisableCollectionRequest) Call(c Client) error {
+	return request(c, ObjectReference, ObjectReferenceDisableCollection, r, nil)
+}
+
+// DisableCollection sends ObjectReference.DisableCollection. Prevents garbage collection for
+// the given object.
+func (id ObjectId) DisableCollection(c Client) error {
+	return ObjectReferenceDisableCollectionRequest{Object: id}.Call(c)
+}
+
+// ObjectReferenceEnableCollectionRequest is the ObjectReference.EnableCollection command.
+// Permits garbage collection for this object. It can fail with ErrVmDead.
+type ObjectReferenceEnableCollectionRequest struct {
+	Object ObjectId // The object ID
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceEnableCollectionRequest) Call(c Client) error {
+	return request(c, ObjectReference, ObjectReferenceEnableCollection, r, nil)
+}
+
+// EnableCollection sends ObjectReference.EnableCollection. Permits garbage collection for this
+// object.
+func (id ObjectId) EnableCollection(c Client) error {
+	return ObjectReferenceEnableCollectionRequest{Object: id}.Call(c)
+}
+
+// ObjectReferenceIsCollectedRequest is the ObjectReference.IsCollected command. Determines
+// whether an object has been garbage collected in the target VM. It can fail with
+// ErrInvalidObject, ErrVmDead.
+type ObjectReferenceIsCollectedRequest struct {
+	Object ObjectId // The object ID
+}
+
+// ObjectReferenceIsCollectedReply is the reply to ObjectReference.IsCollected.
+type ObjectReferenceIsCollectedReply struct {
+	IsCollected bool // true if the object has been collected; false otherwise
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceIsCollectedRequest) Call(c Client) (*ObjectReferenceIsCollectedReply, error) {
+	var reply ObjectReferenceIsCollectedReply
+	if err := request(c, ObjectReference, ObjectReferenceIsCollected, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// IsCollected sends ObjectReference.IsCollected. Determines whether an object has been garbage
+// collected in the target VM.
+func (id ObjectId) IsCollected(c Client) (*ObjectReferenceIsCollectedReply, error) {
+	return ObjectReferenceIsCollectedRequest{Object: id}.Call(c)
+}
+
+// ObjectReferenceReferringObjectsRequest is the ObjectReference.ReferringObjects command.
+// Returns objects that directly reference this object. It can fail with ErrInvalidObject,
+// ErrIllegalArgument, ErrNotImplemented, ErrVmDead.
+type ObjectReferenceReferringObjectsRequest struct {
+	Object       ObjectId // The object ID
+	MaxReferrers int      // Maximum number of referring objects to return.
+}
+
+// ObjectReferenceReferringObjectsReply is the reply to ObjectReference.ReferringObjects.
+type ObjectReferenceReferringObjectsReply struct {
+	NumReferringObjects int              // The number of objects that follow.
+	ReferringObjects    []TaggedObjectId `jdwp:"counter:NumReferringObjects"`
+}
+
+// Call sends the command, returning its reply.
+func (r ObjectReferenceReferringObjectsRequest) Call(c Client) (*ObjectReferenceReferringObjectsReply, error) {
+	var reply ObjectReferenceReferringObjectsReply
+	if err := request(c, ObjectReference, ObjectReferenceReferringObjects, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ReferringObjects sends ObjectReference.ReferringObjects. Returns objects that directly
+// reference this object.
+func (id ObjectId) ReferringObjects(c Client, maxReferrers int) (*ObjectReferenceReferringObjectsReply, error) {
+	return ObjectReferenceReferringObjectsRequest{Object: id, MaxReferrers: maxReferrers}.Call(c)
+}
+
+// StringReferenceValueRequest is the StringReference.Value command. Returns the characters
+// contained in the string. It can fail with ErrInvalidString, ErrInvalidObject, ErrVmDead.
+type StringReferenceValueRequest struct {
+	StringObject ObjectId // The String object ID.
+}
+
+// StringReferenceValueReply is the reply to StringReference.Value.
+type StringReferenceValueReply struct {
+	StringValue string // UTF-8 representation of the string value.
+}
+
+// Call sends the command, returning its reply.
+func (r StringReferenceValueRequest) Call(c Client) (*StringReferenceValueReply, error) {
+	var reply StringReferenceValueReply
+	if err := request(c, StringReference, StringReferenceValue, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Value sends StringReference.Value. Returns the characters contained in the string.
+func (id StringId) Value(c Client) (*StringReferenceValueReply, error) {
+	return StringReferenceValueRequest{StringObject: ObjectId(id)}.Call(c)
+}
+
+// ThreadReferenceNameRequest is the ThreadReference.Name command. Returns the thread name. It
+// can fail with ErrInvalidThread, ErrInvalidObject, ErrVmDead.
+type ThreadReferenceNameRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// ThreadReferenceNameReply is the reply to ThreadReference.Name.
+type ThreadReferenceNameReply struct {
+	ThreadName string // The thread name.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceNameRequest) Call(c Client) (*ThreadReferenceNameReply, error) {
+	var reply ThreadReferenceNameReply
+	if err := request(c, ThreadReference, ThreadReferenceName, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Name sends ThreadReference.Name. Returns the thread name.
+func (id ThreadId) Name(c Client) (*ThreadReferenceNameReply, error) {
+	return ThreadReferenceNameRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceSuspendRequest is the ThreadReference.Suspend command. Suspends the thread. It
+// can fail with ErrInvalidThread, ErrInvalidObject, ErrVmDead.
+type ThreadReferenceSuspendRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceSuspendRequest) Call(c Client) error {
+	return request(c, ThreadReference, ThreadReferenceSuspend, r, nil)
+}
+
+// Suspend sends ThreadReference.Suspend. Suspends the thread.
+func (id ThreadId) Suspend(c Client) error {
+	return ThreadReferenceSuspendRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceResumeRequest is the ThreadReference.Resume command. Resumes the execution of
+// a given thread. It can fail with ErrInvalidThread, ErrInvalidObject, ErrVmDead.
+type ThreadReferenceResumeRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceResumeRequest) Call(c Client) error {
+	return request(c, ThreadReference, ThreadReferenceResume, r, nil)
+}
+
+// Resume sends ThreadReference.Resume. Resumes the execution of a given thread.
+func (id ThreadId) Resume(c Client) error {
+	return ThreadReferenceResumeRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceStatusRequest is the ThreadReference.Status command. Returns the current
+// status of a thread. It can fail with ErrInvalidThread, ErrInvalidObject, ErrVmDead.
+type ThreadReferenceStatusRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// ThreadReferenceStatusReply is the reply to ThreadReference.Status.
+type ThreadReferenceStatusReply struct {
+	ThreadStatus  int // One of the thread status codes See JDWP.ThreadStatus
+	SuspendStatus int // One of the suspend status codes See JDWP.SuspendStatus
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceStatusRequest) Call(c Client) (*ThreadReferenceStatusReply, error) {
+	var reply ThreadReferenceStatusReply
+	if err := request(c, ThreadReference, ThreadReferenceStatus, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Status sends ThreadReference.Status. Returns the current status of a thread.
+func (id ThreadId) Status(c Client) (*ThreadReferenceStatusReply, error) {
+	return ThreadReferenceStatusRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceThreadGroupRequest is the ThreadReference.ThreadGroup command. Returns the
+// thread group that contains a given thread. It can fail with ErrInvalidThread,
+// ErrInvalidObject, ErrVmDead.
+type ThreadReferenceThreadGroupRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// ThreadReferenceThreadGroupReply is the reply to ThreadReference.ThreadGroup.
+type ThreadReferenceThreadGroupReply struct {
+	Group ThreadGroupId // The thread group of this thread.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceThreadGroupRequest) Call(c Client) (*ThreadReferenceThreadGroupReply, error) {
+	var reply ThreadReferenceThreadGroupReply
+	if err := request(c, ThreadReference, ThreadReferenceThreadGroup, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ThreadGroup sends ThreadReference.ThreadGroup. Returns the thread group that contains a given
+// thread.
+func (id ThreadId) ThreadGroup(c Client) (*ThreadReferenceThreadGroupReply, error) {
+	return ThreadReferenceThreadGroupRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceFramesRequest is the ThreadReference.Frames command. Returns the current call
+// stack of a suspended thread. It can fail with ErrInvalidThread, ErrInvalidObject,
+// ErrThreadNotSuspended, ErrInvalidIndex, ErrInvalidLength, ErrVmDead.
+type ThreadReferenceFramesRequest struct {
+	Thread     ThreadId // The thread object ID.
+	StartFrame int      // The index of the first frame to retrieve.
+	Length     int      // The count of frames to retrieve (-1 means all remaining).
+}
+
+// ThreadReferenceFramesReply is the reply to ThreadReference.Frames.
+type ThreadReferenceFramesReply struct {
+	NumFrames int                              // The number of frames retreived
+	Frames    []ThreadReferenceFramesFrameInfo `jdwp:"counter:NumFrames"`
+}
+
+// ThreadReferenceFramesFrameInfo is one of the Frames of ThreadReferenceFramesReply.
+type ThreadReferenceFramesFrameInfo struct {
+	FrameId  FrameId  // The ID of this frame.
+	Location Location // The current location of this frame
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceFramesRequest) Call(c Client) (*ThreadReferenceFramesReply, error) {
+	var reply ThreadReferenceFramesReply
+	if err := request(c, ThreadReference, ThreadReferenceFrames, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ThreadReferenceFrames sends ThreadReference.Frames. Returns the current call stack of a
+// suspended thread.
+func (id ThreadId) ThreadReferenceFrames(c Client, startFrame int, length int) (*ThreadReferenceFramesReply, error) {
+	return ThreadReferenceFramesRequest{Thread: id, StartFrame: startFrame, Length: length}.Call(c)
+}
+
+// ThreadReferenceFrameCountRequest is the ThreadReference.FrameCount command. Returns the count
+// of frames on this thread's stack. It can fail with ErrInvalidThread, ErrInvalidObject,
+// ErrThreadNotSuspended, ErrVmDead.
+type ThreadReferenceFrameCountRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// ThreadReferenceFrameCountReply is the reply to ThreadReference.FrameCount.
+type ThreadReferenceFrameCountReply struct {
+	FrameCount int // The count of frames on this thread's stack.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceFrameCountRequest) Call(c Client) (*ThreadReferenceFrameCountReply, error) {
+	var reply ThreadReferenceFrameCountReply
+	if err := request(c, ThreadReference, ThreadReferenceFrameCount, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// FrameCount sends ThreadReference.FrameCount. Returns the count of frames on this thread's
+// stack.
+func (id ThreadId) FrameCount(c Client) (*ThreadReferenceFrameCountReply, error) {
+	return ThreadReferenceFrameCountRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceOwnedMonitorsRequest is the ThreadReference.OwnedMonitors command. Returns the
+// objects whose monitors have been entered by this thread. It can fail with ErrInvalidThread,
+// ErrInvalidObject, ErrThreadNotSuspended, ErrNotImplemented, ErrVmDead.
+type ThreadReferenceOwnedMonitorsRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// ThreadReferenceOwnedMonitorsReply is the reply to ThreadReference.OwnedMonitors.
+type ThreadReferenceOwnedMonitorsReply struct {
+	NumOwned int              // The number of owned monitors
+	Owned    []TaggedObjectId `jdwp:"counter:NumOwned"`
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceOwnedMonitorsRequest) Call(c Client) (*ThreadReferenceOwnedMonitorsReply, error) {
+	var reply ThreadReferenceOwnedMonitorsReply
+	if err := request(c, ThreadReference, ThreadReferenceOwnedMonitors, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// OwnedMonitors sends ThreadReference.OwnedMonitors. Returns the objects whose monitors have
+// been entered by this thread.
+func (id ThreadId) OwnedMonitors(c Client) (*ThreadReferenceOwnedMonitorsReply, error) {
+	return ThreadReferenceOwnedMonitorsRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceCurrentContendedMonitorRequest is the ThreadReference.CurrentContendedMonitor
+// command. Returns the object, if any, for which this thread is waiting. It can fail with
+// ErrInvalidThread, ErrInvalidObject, ErrThreadNotSuspended, ErrNotImplemented, ErrVmDead.
+type ThreadReferenceCurrentContendedMonitorRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// ThreadReferenceCurrentContendedMonitorReply is the reply to
+// ThreadReference.CurrentContendedMonitor.
+type ThreadReferenceCurrentContendedMonitorReply struct {
+	Monitor TaggedObjectId // The contended monitor, or null if there is no current contended monitor.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceCurrentContendedMonitorRequest) Call(c Client) (*ThreadReferenceCurrentContendedMonitorReply, error) {
+	var reply ThreadReferenceCurrentContendedMonitorReply
+	if err := request(c, ThreadReference, ThreadReferenceCurrentContendedMonitor, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// CurrentContendedMonitor sends ThreadReference.CurrentContendedMonitor. Returns the object, if
+// any, for which this thread is waiting.
+func (id ThreadId) CurrentContendedMonitor(c Client) (*ThreadReferenceCurrentContendedMonitorReply, error) {
+	return ThreadReferenceCurrentContendedMonitorRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceStopRequest is the ThreadReference.Stop command. Stops the thread with an
+// asynchronous exception. It can fail with ErrInvalidThread, ErrInvalidObject, ErrVmDead.
+type ThreadReferenceStopRequest struct {
+	Thread    ThreadId // The thread object ID.
+	Throwable ObjectId // Asynchronous exception.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceStopRequest) Call(c Client) error {
+	return request(c, ThreadReference, ThreadReferenceStop, r, nil)
+}
+
+// Stop sends ThreadReference.Stop. Stops the thread with an asynchronous exception.
+func (id ThreadId) Stop(c Client, throwable ObjectId) error {
+	return ThreadReferenceStopRequest{Thread: id, Throwable: throwable}.Call(c)
+}
+
+// ThreadReferenceInterruptRequest is the ThreadReference.Interrupt command. Interrupt the
+// thread, as if done by java.lang.Thread.interrupt It can fail with ErrInvalidThread,
+// ErrInvalidObject, ErrVmDead.
+type ThreadReferenceInterruptRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceInterruptRequest) Call(c Client) error {
+	return request(c, ThreadReference, ThreadReferenceInterrupt, r, nil)
+}
+
+// Interrupt sends ThreadReference.Interrupt. Interrupt the thread, as if done by
+// java.lang.Thread.interrupt.
+func (id ThreadId) Interrupt(c Client) error {
+	return ThreadReferenceInterruptRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceSuspendCountRequest is the ThreadReference.SuspendCount command. Get the
+// suspend count for this thread. It can fail with ErrInvalidThread, ErrInvalidObject,
+// ErrVmDead.
+type ThreadReferenceSuspendCountRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// ThreadReferenceSuspendCountReply is the reply to ThreadReference.SuspendCount.
+type ThreadReferenceSuspendCountReply struct {
+	SuspendCount int // The number of outstanding suspends of this thread.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceSuspendCountRequest) Call(c Client) (*ThreadReferenceSuspendCountReply, error) {
+	var reply ThreadReferenceSuspendCountReply
+	if err := request(c, ThreadReference, ThreadReferenceSuspendCount, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// SuspendCount sends ThreadReference.SuspendCount. Get the suspend count for this thread.
+func (id ThreadId) SuspendCount(c Client) (*ThreadReferenceSuspendCountReply, error) {
+	return ThreadReferenceSuspendCountRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceOwnedMonitorsStackDepthInfoRequest is the
+// ThreadReference.OwnedMonitorsStackDepthInfo command. Returns monitor objects owned by the
+// thread, along with stack depth at which the monitor was acquired. Since JDWP version 1.6. It
+// can fail with ErrInvalidThread, ErrInvalidObject, ErrThreadNotSuspended, ErrNotImplemented,
+// ErrVmDead.
+type ThreadReferenceOwnedMonitorsStackDepthInfoRequest struct {
+	Thread ThreadId // The thread object ID.
+}
+
+// ThreadReferenceOwnedMonitorsStackDepthInfoReply is the reply to
+// ThreadReference.OwnedMonitorsStackDepthInfo.
+type ThreadReferenceOwnedMonitorsStackDepthInfoReply struct {
+	NumOwned int                                                 // The number of owned monitors
+	Owned    []ThreadReferenceOwnedMonitorsStackDepthInfoMonitor `jdwp:"counter:NumOwned"`
+}
+
+// ThreadReferenceOwnedMonitorsStackDepthInfoMonitor is one of the Owned of
+// ThreadReferenceOwnedMonitorsStackDepthInfoReply.
+type ThreadReferenceOwnedMonitorsStackDepthInfoMonitor struct {
+	Monitor    TaggedObjectId // An owned monitor
+	StackDepth int            // Stack depth location where monitor was acquired
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceOwnedMonitorsStackDepthInfoRequest) Call(c Client) (*ThreadReferenceOwnedMonitorsStackDepthInfoReply, error) {
+	var reply ThreadReferenceOwnedMonitorsStackDepthInfoReply
+	if err := request(c, ThreadReference, ThreadReferenceOwnedMonitorsStackDepthInfo, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// OwnedMonitorsStackDepthInfo sends ThreadReference.OwnedMonitorsStackDepthInfo. Returns
+// monitor objects owned by the thread, along with stack depth at which the monitor was
+// acquired.
+func (id ThreadId) OwnedMonitorsStackDepthInfo(c Client) (*ThreadReferenceOwnedMonitorsStackDepthInfoReply, error) {
+	return ThreadReferenceOwnedMonitorsStackDepthInfoRequest{Thread: id}.Call(c)
+}
+
+// ThreadReferenceForceEarlyReturnRequest is the ThreadReference.ForceEarlyReturn command. Force
+// a method to return before it reaches a return statement. Since JDWP version 1.6. It can fail
+// with ErrInvalidThread, ErrInvalidObject, ErrThreadNotSuspended, ErrThreadNotAlive,
+// ErrOpaqueFrame, ErrNoMoreFrames, ErrNotImplemented, ErrTypeMismatch, ErrVmDead.
+type ThreadReferenceForceEarlyReturnRequest struct {
+	Thread ThreadId    // The thread object ID.
+	Value  TaggedValue // The value to return.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadReferenceForceEarlyReturnRequest) Call(c Client) error {
+	return request(c, ThreadReference, ThreadReferenceForceEarlyReturn, r, nil)
+}
+
+// ForceEarlyReturn sends ThreadReference.ForceEarlyReturn. Force a method to return before it
+// reaches a return statement.
+func (id ThreadId) ForceEarlyReturn(c Client, value TaggedValue) error {
+	return ThreadReferenceForceEarlyReturnRequest{Thread: id, Value: value}.Call(c)
+}
+
+// ThreadGroupReferenceNameRequest is the ThreadGroupReference.Name command. Returns the thread
+// group name. It can fail with ErrInvalidThreadGroup, ErrInvalidObject, ErrVmDead.
+type ThreadGroupReferenceNameRequest struct {
+	Group ThreadGroupId // The thread group object ID.
+}
+
+// ThreadGroupReferenceNameReply is the reply to ThreadGroupReference.Name.
+type ThreadGroupReferenceNameReply struct {
+	GroupName string // The thread group's name.
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadGroupReferenceNameRequest) Call(c Client) (*ThreadGroupReferenceNameReply, error) {
+	var reply ThreadGroupReferenceNameReply
+	if err := request(c, ThreadGroupReference, ThreadGroupReferenceName, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Name sends ThreadGroupReference.Name. Returns the thread group name.
+func (id ThreadGroupId) Name(c Client) (*ThreadGroupReferenceNameReply, error) {
+	return ThreadGroupReferenceNameRequest{Group: id}.Call(c)
+}
+
+// ThreadGroupReferenceParentRequest is the ThreadGroupReference.Parent command. Returns the
+// thread group, if any, which contains a given thread group. It can fail with
+// ErrInvalidThreadGroup, ErrInvalidObject, ErrVmDead.
+type ThreadGroupReferenceParentRequest struct {
+	Group ThreadGroupId // The thread group object ID.
+}
+
+// ThreadGroupReferenceParentReply is the reply to ThreadGroupReference.Parent.
+type ThreadGroupReferenceParentReply struct {
+	ParentGroup ThreadGroupId // The parent thread group object, or null if the given thread group is a top-level thread group
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadGroupReferenceParentRequest) Call(c Client) (*ThreadGroupReferenceParentReply, error) {
+	var reply ThreadGroupReferenceParentReply
+	if err := request(c, ThreadGroupReference, ThreadGroupReferenceParent, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Parent sends ThreadGroupReference.Parent. Returns the thread group, if any, which contains a
+// given thread group.
+func (id ThreadGroupId) Parent(c Client) (*ThreadGroupReferenceParentReply, error) {
+	return ThreadGroupReferenceParentRequest{Group: id}.Call(c)
+}
+
+// ThreadGroupReferenceChildrenRequest is the ThreadGroupReference.Children command. Returns the
+// live threads and active thread groups directly contained in this thread group. It can fail
+// with ErrInvalidThreadGroup, ErrInvalidObject, ErrVmDead.
+type ThreadGroupReferenceChildrenRequest struct {
+	Group ThreadGroupId // The thread group object ID.
+}
+
+// ThreadGroupReferenceChildrenReply is the reply to ThreadGroupReference.Children.
+type ThreadGroupReferenceChildrenReply struct {
+	NumChildThreads int             // The number of live child threads.
+	ChildThreads    []ThreadId      `jdwp:"counter:NumChildThreads"`
+	NumChildGroups  int             // The number of active child thread groups.
+	ChildGroups     []ThreadGroupId `jdwp:"counter:NumChildGroups"`
+}
+
+// Call sends the command, returning its reply.
+func (r ThreadGroupReferenceChildrenRequest) Call(c Client) (*ThreadGroupReferenceChildrenReply, error) {
+	var reply ThreadGroupReferenceChildrenReply
+	if err := request(c, ThreadGroupReference, ThreadGroupReferenceChildren, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Children sends ThreadGroupReference.Children. Returns the live threads and active thread
+// groups directly contained in this thread group.
+func (id ThreadGroupId) Children(c Client) (*ThreadGroupReferenceChildrenReply, error) {
+	return ThreadGroupReferenceChildrenRequest{Group: id}.Call(c)
+}
+
+// ArrayReferenceLengthRequest is the ArrayReference.Length command. Returns the number of
+// components in a given array. It can fail with ErrInvalidObject, ErrInvalidArray, ErrVmDead.
+type ArrayReferenceLengthRequest struct {
+	ArrayObject ArrayId // The array object ID.
+}
+
+// ArrayReferenceLengthReply is the reply to ArrayReference.Length.
+type ArrayReferenceLengthReply struct {
+	ArrayLength int // The length of the array.
+}
+
+// Call sends the command, returning its reply.
+func (r ArrayReferenceLengthRequest) Call(c Client) (*ArrayReferenceLengthReply, error) {
+	var reply ArrayReferenceLengthReply
+	if err := request(c, ArrayReference, ArrayReferenceLength, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Length sends ArrayReference.Length. Returns the number of components in a given array.
+func (id ArrayId) Length(c Client) (*ArrayReferenceLengthReply, error) {
+	return ArrayReferenceLengthRequest{ArrayObject: id}.Call(c)
+}
+
+// ArrayReferenceGetValuesRequest is the ArrayReference.GetValues command. Returns a range of
+// array components. It can fail with ErrInvalidLength, ErrInvalidObject, ErrInvalidArray,
+// ErrVmDead.
+type ArrayReferenceGetValuesRequest struct {
+	ArrayObject ArrayId // The array object ID.
+	FirstIndex  int     // The first index to retrieve.
+	Length      int     // The number of components to retrieve.
+}
+
+// ArrayReferenceGetValuesReply is the reply to ArrayReference.GetValues.
+type ArrayReferenceGetValuesReply struct {
+	Values ArrayRegion // The retrieved values.
+}
+
+// Call sends the command, returning its reply.
+func (r ArrayReferenceGetValuesRequest) Call(c Client) (*ArrayReferenceGetValuesReply, error) {
+	var reply ArrayReferenceGetValuesReply
+	if err := request(c, ArrayReference, ArrayReferenceGetValues, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// GetValues sends ArrayReference.GetValues. Returns a range of array components.
+func (id ArrayId) GetValues(c Client, firstIndex int, length int) (*ArrayReferenceGetValuesReply, error) {
+	return ArrayReferenceGetValuesRequest{ArrayObject: id, FirstIndex: firstIndex, Length: length}.Call(c)
+}
+
+// ArrayReferenceSetValuesRequest is the ArrayReference.SetValues command. Sets a range of array
+// components. It can fail with ErrInvalidLength, ErrInvalidObject, ErrInvalidArray, ErrVmDead.
+type ArrayReferenceSetValuesRequest struct {
+	ArrayObject ArrayId         // The array object ID.
+	FirstIndex  int             // The first index to set.
+	NumValues   int             // The number of values to set.
+	Values      []UntaggedValue `jdwp:"counter:NumValues"`
+}
+
+// Call sends the command, returning its reply.
+func (r ArrayReferenceSetValuesRequest) Call(c Client) error {
+	return request(c, ArrayReference, ArrayReferenceSetValues, r, nil)
+}
+
+// SetValues sends ArrayReference.SetValues. Sets a range of array components.
+func (id ArrayId) SetValues(c Client, firstIndex int, values []UntaggedValue) error {
+	return ArrayReferenceSetValuesRequest{ArrayObject: id, FirstIndex: firstIndex, Values: values}.Call(c)
+}
+
+// ClassLoaderReferenceVisibleClassesRequest is the ClassLoaderReference.VisibleClasses command.
+// Returns a list of all classes which this class loader has been requested to load by the Java
+// Virtual Machine. It can fail with ErrInvalidClassLoader, ErrInvalidObject, ErrVmDead.
+type ClassLoaderReferenceVisibleClassesRequest struct {
+	ClassLoaderObject ClassLoaderId // The class loader object ID.
+}
+
+// ClassLoaderReferenceVisibleClassesReply is the reply to ClassLoaderReference.VisibleClasses.
+type ClassLoaderReferenceVisibleClassesReply struct {
+	NumClasses int                                           // The number of visible classes.
+	Classes    []ClassLoaderReferenceVisibleClassesClassInfo `jdwp:"counter:NumClasses"`
+}
+
+// ClassLoaderReferenceVisibleClassesClassInfo is one of the Classes of
+// ClassLoaderReferenceVisibleClassesReply.
+type ClassLoaderReferenceVisibleClassesClassInfo struct {
+	RefTypeTag uint8   // Kind of following reference type.
+	TypeId     ClassId // A class visible to this class loader.
+}
+
+// Call sends the command, returning its reply.
+func (r ClassLoaderReferenceVisibleClassesRequest) Call(c Client) (*ClassLoaderReferenceVisibleClassesReply, error) {
+	var reply ClassLoaderReferenceVisibleClassesReply
+	if err := request(c, ClassLoaderReference, ClassLoaderReferenceVisibleClasses, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// VisibleClasses sends ClassLoaderReference.VisibleClasses. Returns a list of all classes which
+// this class loader has been requested to load by the Java Virtual Machine.
+func (id ClassLoaderId) VisibleClasses(c Client) (*ClassLoaderReferenceVisibleClassesReply, error) {
+	return ClassLoaderReferenceVisibleClassesRequest{ClassLoaderObject: id}.Call(c)
+}
+
+// EventRequestSetRequest is the EventRequest.Set command. Set an event request. When the event
+// described by this request occurs, an event is sent from the target VM. It can fail with
+// ErrInvalidThread, ErrThreadNotSuspended, ErrInvalidObject, ErrInvalidClass, ErrInvalidString,
+// ErrInvalidCount, ErrInvalidFieldId, ErrInvalidMethodId, ErrInvalidLocation,
+// ErrInvalidEventType, ErrNotImplemented, ErrVmDead.
+type EventRequestSetRequest struct {
+	EventKind     uint8                    // Event kind to request.
+	SuspendPolicy uint8                    // What threads are suspended when this event occurs?
+	NumModifiers  int                      // Constraints used to control the number of generated events.
+	Modifiers     []EventRequestSetModKind `jdwp:"counter:NumModifiers"`
+}
+
+// EventRequestSetModKind is one of the Modifiers of EventRequestSetRequest, each preceded by
+// the modKind selecting it. Modifier kind.
+type EventRequestSetModKind interface {
+	alternative() uint8
+}
+
+// EventRequestSetCount is the EventRequestSetModKind of modKind 1. Limit the requested event to
+// be reported at most once after a given number of occurrences.
+type EventRequestSetCount struct {
+	Count int // Count before event.
+}
+
+func (*EventRequestSetCount) alternative() uint8 {
+	return 1
+}
+
+// EventRequestSetConditional is the EventRequestSetModKind of modKind 2. Conditional on
+// expression.
+type EventRequestSetConditional struct {
+	ExprId int // For the future
+}
+
+func (*EventRequestSetConditional) alternative() uint8 {
+	return 2
+}
+
+// EventRequestSetThreadOnly is the EventRequestSetModKind of modKind 3. Restricts reported
+// events to those in the given thread.
+type EventRequestSetThreadOnly struct {
+	Thread ThreadId // Required thread
+}
+
+func (*EventRequestSetThreadOnly) alternative() uint8 {
+	return 3
+}
+
+// EventRequestSetClassOnly is the EventRequestSetModKind of modKind 4. For class prepare
+// events, restricts the events generated by this request to be the preparation of the given
+// reference type and any subtypes.
+type EventRequestSetClassOnly struct {
+	Clazz ClassId // Required class
+}
+
+func (*EventRequestSetClassOnly) alternative() uint8 {
+	return 4
+}
+
+// EventRequestSetClassMatch is the EventRequestSetModKind of modKind 5. Restricts reported
+// events to those for classes whose name matches the given restricted regular expression.
+type EventRequestSetClassMatch struct {
+	ClassPattern string // Required class pattern.
+}
+
+func (*EventRequestSetClassMatch) alternative() uint8 {
+	return 5
+}
+
+// EventRequestSetClassExclude is the EventRequestSetModKind of modKind 6. Restricts reported
+// events to those for classes whose name does not match the given restricted regular
+// expression.
+type EventRequestSetClassExclude struct {
+	ClassPattern string // Disallowed class pattern.
+}
+
+func (*EventRequestSetClassExclude) alternative() uint8 {
+	return 6
+}
+
+// EventRequestSetLocationOnly is the EventRequestSetModKind of modKind 7. Restricts reported
+// events to those that occur at the given location.
+type EventRequestSetLocationOnly struct {
+	Loc Location // Required location
+}
+
+func (*EventRequestSetLocationOnly) alternative() uint8 {
+	return 7
+}
+
+// EventRequestSetExceptionOnly is the EventRequestSetModKind of modKind 8. Restricts reported
+// exceptions by their class and whether they are caught or uncaught.
+type EventRequestSetExceptionOnly struct {
+	ExceptionOrNull ClassId // Exception to report.
+	Caught          bool    // Report caught exceptions
+	Uncaught        bool    // Report uncaught exceptions.
+}
+
+func (*EventRequestSetExceptionOnly) alternative() uint8 {
+	return 8
+}
+
+// EventRequestSetFieldOnly is the EventRequestSetModKind of modKind 9. Restricts reported
+// events to those that occur for a given field.
+type EventRequestSetFieldOnly struct {
+	Declaring ClassId // Type in which field is declared.
+	FieldId   FieldId // Required field
+}
+
+func (*EventRequestSetFieldOnly) alternative() uint8 {
+	return 9
+}
+
+// EventRequestSetStep is the EventRequestSetModKind of modKind 10. Restricts reported step
+// events to those which satisfy depth and size constraints.
+type EventRequestSetStep struct {
+	Thread ThreadId // Thread to step
+	Size   int      // size of each step.
+	Depth  int      // relative call stack limit.
+}
+
+func (*EventRequestSetStep) alternative() uint8 {
+	return 10
+}
+
+// EventRequestSetInstanceOnly is the EventRequestSetModKind of modKind 11. Restricts reported
+// events to those whose active 'this' object is the given object.
+type EventRequestSetInstanceOnly struct {
+	Instance ObjectId // Required 'this' object
+}
+
+func (*EventRequestSetInstanceOnly) alternative() uint8 {
+	return 11
+}
+
+// EventRequestSetSourceNameMatch is the EventRequestSetModKind of modKind 12. Restricts
+// reported class prepare events to those for reference types which have a source name which
+// matches the given restricted regular expression.
+type EventRequestSetSourceNameMatch struct {
+	SourceNamePattern string // Required source name pattern.
+}
+
+func (*EventRequestSetSourceNameMatch) alternative() uint8 {
+	return 12
+}
+
+// EventRequestSetReply is the reply to EventRequest.Set.
+type EventRequestSetReply struct {
+	RequestId int // ID of created request
+}
+
+// Call sends the command, returning its reply.
+func (r EventRequestSetRequest) Call(c Client) (*EventRequestSetReply, error) {
+	var reply EventRequestSetReply
+	if err := request(c, EventRequest, EventRequestSetCommand, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// EventRequestClearRequest is the EventRequest.Clear command. Clear an event request. It can
+// fail with ErrInvalidEventType, ErrVmDead.
+type EventRequestClearRequest struct {
+	EventKind uint8 // Event kind to clear
+	RequestId int   // ID of request to clear
+}
+
+// Call sends the command, returning its reply.
+func (r EventRequestClearRequest) Call(c Client) error {
+	return request(c, EventRequest, EventRequestClearCommand, r, nil)
+}
+
+// EventRequestClearAllBreakpointsRequest is the EventRequest.ClearAllBreakpoints command.
+// Removes all set breakpoints, a no-op if there are no breakpoints set. It can fail with
+// ErrVmDead.
+type EventRequestClearAllBreakpointsRequest struct{}
+
+// Call sends the command, returning its reply.
+func (r EventRequestClearAllBreakpointsRequest) Call(c Client) error {
+	return request(c, EventRequest, EventRequestClearAllBreakpoints, r, nil)
+}
+
+// StackFrameGetValuesRequest is the StackFrame.GetValues command. Returns the value of one or
+// more local variables in a given frame. It can fail with ErrInvalidThread, ErrInvalidObject,
+// ErrInvalidFrameId, ErrInvalidSlot, ErrVmDead.
+type StackFrameGetValuesRequest struct {
+	Thread   ThreadId                      // The frame's thread.
+	Frame    FrameId                       // The frame ID.
+	NumSlots int                           // The number of values to get.
+	Slots    []StackFrameGetValuesSlotInfo `jdwp:"counter:NumSlots"`
+}
+
+// StackFrameGetValuesSlotInfo is one of the Slots of StackFrameGetValuesRequest.
+type StackFrameGetValuesSlotInfo struct {
+	Slot    int   // The local variable's index in the frame.
+	Sigbyte uint8 // A tag identifying the type of the variable
+}
+
+// StackFrameGetValuesReply is the reply to StackFrame.GetValues.
+type StackFrameGetValuesReply struct {
+	NumValues int           // The number of values retrieved, always equal to slots, the number of values to get.
+	Values    []TaggedValue `jdwp:"counter:NumValues"`
+}
+
+// Call sends the command, returning its reply.
+func (r StackFrameGetValuesRequest) Call(c Client) (*StackFrameGetValuesReply, error) {
+	var reply StackFrameGetValuesReply
+	if err := request(c, StackFrame, StackFrameGetValues, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// StackFrameGetValues sends StackFrame.GetValues. Returns the value of one or more local
+// variables in a given frame.
+func (f Frame) StackFrameGetValues(c Client, slots []StackFrameGetValuesSlotInfo) (*StackFrameGetValuesReply, error) {
+	return StackFrameGetValuesRequest{Thread: f.thr, Frame: f.FrameId, Slots: slots}.Call(c)
+}
+
+// StackFrameSetValuesRequest is the StackFrame.SetValues command. Sets the value of one or more
+// local variables. It can fail with ErrInvalidThread, ErrInvalidObject, ErrInvalidFrameId,
+// ErrVmDead.
+type StackFrameSetValuesRequest struct {
+	Thread        ThreadId                      // The frame's thread.
+	Frame         FrameId                       // The frame ID.
+	NumSlotValues int                           // The number of values to set.
+	SlotValues    []StackFrameSetValuesSlotInfo `jdwp:"counter:NumSlotValues"`
+}
+
+// StackFrameSetValuesSlotInfo is one of the SlotValues of StackFrameSetValuesRequest.
+type StackFrameSetValuesSlotInfo struct {
+	Slot      int         // The slot ID.
+	SlotValue TaggedValue // The value to set.
+}
+
+// Call sends the command, returning its reply.
+func (r StackFrameSetValuesRequest) Call(c Client) error {
+	return request(c, StackFrame, StackFrameSetValues, r, nil)
+}
+
+// SetValues sends StackFrame.SetValues. Sets the value of one or more local variables.
+func (f Frame) SetValues(c Client, slotValues []StackFrameSetValuesSlotInfo) error {
+	return StackFrameSetValuesRequest{Thread: f.thr, Frame: f.FrameId, SlotValues: slotValues}.Call(c)
+}
+
+// StackFrameThisObjectRequest is the StackFrame.ThisObject command. Returns the value of the
+// 'this' reference for this frame. It can fail with ErrInvalidThread, ErrInvalidObject,
+// ErrInvalidFrameId, ErrVmDead.
+type StackFrameThisObjectRequest struct {
+	Thread ThreadId // The frame's thread.
+	Frame  FrameId  // The frame ID.
+}
+
+// StackFrameThisObjectReply is the reply to StackFrame.ThisObject.
+type StackFrameThisObjectReply struct {
+	ObjectThis TaggedObjectId // The 'this' object for this frame.
+}
+
+// Call sends the command, returning its reply.
+func (r StackFrameThisObjectRequest) Call(c Client) (*StackFrameThisObjectReply, error) {
+	var reply StackFrameThisObjectReply
+	if err := request(c, StackFrame, StackFrameThisObject, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ThisObject sends StackFrame.ThisObject. Returns the value of the 'this' reference for this
+// frame.
+func (f Frame) ThisObject(c Client) (*StackFrameThisObjectReply, error) {
+	return StackFrameThisObjectRequest{Thread: f.thr, Frame: f.FrameId}.Call(c)
+}
+
+// StackFramePopFramesRequest is the StackFrame.PopFrames command. Pop the top-most stack frames
+// of the thread stack, up to, and including 'frame'. It can fail with ErrInvalidThread,
+// ErrInvalidObject, ErrInvalidFrameId, ErrThreadNotSuspended, ErrNoMoreFrames,
+// ErrNotImplemented, ErrVmDead.
+type StackFramePopFramesRequest struct {
+	Thread ThreadId // The thread object ID.
+	Frame  FrameId  // The frame ID.
+}
+
+// Call sends the command, returning its reply.
+func (r StackFramePopFramesRequest) Call(c Client) error {
+	return request(c, StackFrame, StackFramePopFrames, r, nil)
+}
+
+// PopFrames sends StackFrame.PopFrames. Pop the top-most stack frames of the thread stack, up
+// to, and including 'frame'.
+func (f Frame) PopFrames(c Client) error {
+	return StackFramePopFramesRequest{Thread: f.thr, Frame: f.FrameId}.Call(c)
+}
+
+// ClassObjectReferenceReflectedTypeRequest is the ClassObjectReference.ReflectedType command.
+// Returns the reference type reflected by this class object. It can fail with ErrInvalidObject,
+// ErrVmDead.
+type ClassObjectReferenceReflectedTypeRequest struct {
+	ClassObject ClassObjectId // The class object.
+}
+
+// ClassObjectReferenceReflectedTypeReply is the reply to ClassObjectReference.ReflectedType.
+type ClassObjectReferenceReflectedTypeReply struct {
+	RefTypeTag uint8   // Kind of following reference type.
+	TypeId     ClassId // reflected reference type
+}
+
+// Call sends the command, returning its reply.
+func (r ClassObjectReferenceReflectedTypeRequest) Call(c Client) (*ClassObjectReferenceReflectedTypeReply, error) {
+	var reply ClassObjectReferenceReflectedTypeReply
+	if err := request(c, ClassObjectReference, ClassObjectReferenceReflectedType, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ReflectedType sends ClassObjectReference.ReflectedType. Returns the reference type reflected
+// by this class object.
+func (id ClassObjectId) ReflectedType(c Client) (*ClassObjectReferenceReflectedTypeReply, error) {
+	return ClassObjectReferenceReflectedTypeRequest{ClassObject: id}.Call(c)
+}
+
+// ModuleReferenceNameRequest is the ModuleReference.Name command. Returns the name of this
+// module. Since JDWP version 9. It can fail with ErrInvalidModule, ErrNotImplemented,
+// ErrVmDead.
+type ModuleReferenceNameRequest struct {
+	Module ModuleId // This module.
+}
+
+// ModuleReferenceNameReply is the reply to ModuleReference.Name.
+type ModuleReferenceNameReply struct {
+	Name string // The module's name.
+}
+
+// Call sends the command, returning its reply.
+func (r ModuleReferenceNameRequest) Call(c Client) (*ModuleReferenceNameReply, error) {
+	var reply ModuleReferenceNameReply
+	if err := request(c, ModuleReference, ModuleReferenceName, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Name sends ModuleReference.Name. Returns the name of this module.
+func (id ModuleId) Name(c Client) (*ModuleReferenceNameReply, error) {
+	return ModuleReferenceNameRequest{Module: id}.Call(c)
+}
+
+// ModuleReferenceClassLoaderRequest is the ModuleReference.ClassLoader command. Returns the
+// class loader of this module. Since JDWP version 9. It can fail with ErrInvalidModule,
+// ErrNotImplemented, ErrVmDead.
+type ModuleReferenceClassLoaderRequest struct {
+	Module ModuleId // This module.
+}
+
+// ModuleReferenceClassLoaderReply is the reply to ModuleReference.ClassLoader.
+type ModuleReferenceClassLoaderReply struct {
+	ClassLoader ClassLoaderId // The module's class loader.
+}
+
+// Call sends the command, returning its reply.
+func (r ModuleReferenceClassLoaderRequest) Call(c Client) (*ModuleReferenceClassLoaderReply, error) {
+	var reply ModuleReferenceClassLoaderReply
+	if err := request(c, ModuleReference, ModuleReferenceClassLoader, r, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// ClassLoader sends ModuleReference.ClassLoader. Returns the class loader of this module.
+func (id ModuleId) ClassLoader(c Client) (*ModuleReferenceClassLoaderReply, error) {
+	return ModuleReferenceClassLoaderRequest{Module: id}.Call(c)
+}
+
+// EventCompositeEvent is the Event.Composite command, sent by the VM. Several events may occur
+// at a given time in the target VM, and are sent together in one composite command.
+type EventCompositeEvent struct {
+	SuspendPolicy uint8                     // Which threads where suspended by this composite event?
+	NumEvents     int                       // Events in set.
+	Events        []EventCompositeEventKind `jdwp:"counter:NumEvents"`
+}
+
+// EventCompositeEventKind is one of the Events of EventCompositeEvent, each preceded by the
+// eventKind selecting it. Event kind selector.
+type EventCompositeEventKind interface {
+	alternative() uint8
+}
+
+// EventCompositeVMStart is the EventCompositeEventKind of eventKind 90. Notification of
+// initialization of a target VM.
+type EventCompositeVMStart struct {
+	RequestId int      // Request that generated event (or 0 if this event is automatically generated.
+	Thread    ThreadId // Initial thread
+}
+
+func (*EventCompositeVMStart) alternative() uint8 {
+	return 90
+}
+
+// EventCompositeSingleStep is the EventCompositeEventKind of eventKind 1. Notification of step
+// completion in the target VM.
+type EventCompositeSingleStep struct {
+	RequestId int      // Request that generated event
+	Thread    ThreadId // Stepped thread
+	Location  Location // Location stepped to
+}
+
+func (*EventCompositeSingleStep) alternative() uint8 {
+	return 1
+}
+
+// EventCompositeBreakpoint is the EventCompositeEventKind of eventKind 2. Notification of a
+// breakpoint in the target VM.
+type EventCompositeBreakpoint struct {
+	RequestId int      // Request that generated event
+	Thread    ThreadId // Thread which hit breakpoint
+	Location  Location // Location hit
+}
+
+func (*EventCompositeBreakpoint) alternative() uint8 {
+	return 2
+}
+
+// EventCompositeMethodEntry is the EventCompositeEventKind of eventKind 40. Notification of a
+// method invocation in the target VM.
+type EventCompositeMethodEntry struct {
+	RequestId int      // Request that generated event
+	Thread    ThreadId // Thread which entered method
+	Location  Location // The initial executable location in the method.
+}
+
+func (*EventCompositeMethodEntry) alternative() uint8 {
+	return 40
+}
+
+// EventCompositeMethodExit is the EventCompositeEventKind of eventKind 41. Notification of a
+// method return in the target VM.
+type EventCompositeMethodExit struct {
+	RequestId int      // Request that generated event
+	Thread    ThreadId // Thread which exited method
+	Location  Location // Location of exit
+}
+
+func (*EventCompositeMethodExit) alternative() uint8 {
+	return 41
+}
+
+// EventCompositeMethodExitWithReturnValue is the EventCompositeEventKind of eventKind 42.
+// Notification of a method return in the target VM, with the value returned.
+type EventCompositeMethodExitWithReturnValue struct {
+	RequestId int         // Request that generated event
+	Thread    ThreadId    // Thread which exited method
+	Location  Location    // Location of exit
+	Value     TaggedValue // Value that will be returned by the method
+}
+
+func (*EventCompositeMethodExitWithReturnValue) alternative() uint8 {
+	return 42
+}
+
+// EventCompositeMonitorContendedEnter is the EventCompositeEventKind of eventKind 43.
+// Notification that a thread in the target VM is attempting to enter a monitor that is already
+// acquired by another thread.
+type EventCompositeMonitorContendedEnter struct {
+	RequestId int            // Request that generated event
+	Thread    ThreadId       // Thread which is trying to enter the monitor
+	Object    TaggedObjectId // Monitor object reference
+	Location  Location       // Location of contended monitor enter
+}
+
+func (*EventCompositeMonitorContendedEnter) alternative() uint8 {
+	return 43
+}
+
+// EventCompositeMonitorContendedEntered is the EventCompositeEventKind of eventKind 44.
+// Notification of a thread in the target VM is entering a monitor after waiting for it to be
+// released by another thread.
+type EventCompositeMonitorContendedEntered struct {
+	RequestId int            // Request that generated event
+	Thread    ThreadId       // Thread which entered monitor
+	Object    TaggedObjectId // Monitor object reference
+	Location  Location       // Location of contended monitor enter
+}
+
+func (*EventCompositeMonitorContendedEntered) alternative() uint8 {
+	return 44
+}
+
+// EventCompositeMonitorWait is the EventCompositeEventKind of eventKind 45. Notification of a
+// thread about to wait on a monitor object.
+type EventCompositeMonitorWait struct {
+	RequestId int            // Request that generated event
+	Thread    ThreadId       // Thread which is about to wait
+	Object    TaggedObjectId // Monitor object reference
+	Location  Location       // Location at which the wait will occur
+	Timeout   int64          // Thread wait time in milliseconds
+}
+
+func (*EventCompositeMonitorWait) alternative() uint8 {
+	return 45
+}
+
+// EventCompositeMonitorWaited is the EventCompositeEventKind of eventKind 46. Notification that
+// a thread in the target VM has finished waiting on a monitor object.
+type EventCompositeMonitorWaited struct {
+	RequestId int            // Request that generated event
+	Thread    ThreadId       // Thread which waited
+	Object    TaggedObjectId // Monitor object reference
+	Location  Location       // Location at which the wait occured
+	TimedOut  bool           // True if timed out
+}
+
+func (*EventCompositeMonitorWaited) alternative() uint8 {
+	return 46
+}
+
+// EventCompositeException is the EventCompositeEventKind of eventKind 4. Notification of an
+// exception in the target VM.
+type EventCompositeException struct {
+	RequestId     int            // Request that generated event
+	Thread        ThreadId       // Thread with exception
+	Location      Location       // Location of exception throw (or first non-native location after throw if thrown from a native method)
+	Exception     TaggedObjectId // Thrown exception
+	CatchLocation Location       // Location of catch, or 0 if not caught.
+}
+
+func (*EventCompositeException) alternative() uint8 {
+	return 4
+}
+
+// EventCompositeThreadStart is the EventCompositeEventKind of eventKind 6. Notification of a
+// new running thread in the target VM.
+type EventCompositeThreadStart struct {
+	RequestId int      // Request that generated event
+	Thread    ThreadId // Started thread
+}
+
+func (*EventCompositeThreadStart) alternative() uint8 {
+	return 6
+}
+
+// EventCompositeThreadDeath is the EventCompositeEventKind of eventKind 7. Notification of a
+// completed thread in the target VM.
+type EventCompositeThreadDeath struct {
+	RequestId int      // Request that generated event
+	Thread    ThreadId // Ending thread
+}
+
+func (*EventCompositeThreadDeath) alternative() uint8 {
+	return 7
+}
+
+// EventCompositeClassPrepare is the EventCompositeEventKind of eventKind 8. Notification of a
+// class prepare in the target VM.
+type EventCompositeClassPrepare struct {
+	RequestId  int      // Request that generated event
+	Thread     ThreadId // Preparing thread.
+	RefTypeTag uint8    // Kind of reference type.
+	TypeId     ClassId  // Type being prepared
+	Signature  string   // Type signature
+	Status     int      // Status of type.
+}
+
+func (*EventCompositeClassPrepare) alternative() uint8 {
+	return 8
+}
+
+// EventCompositeClassUnload is the EventCompositeEventKind of eventKind 9. Notification of a
+// class unload in the target VM.
+type EventCompositeClassUnload struct {
+	RequestId int    // Request that generated event
+	Signature string // Type signature
+}
+
+func (*EventCompositeClassUnload) alternative() uint8 {
+	return 9
+}
+
+// EventCompositeFieldAccess is the EventCompositeEventKind of eventKind 20. Notification of a
+// field access in the target VM.
+type EventCompositeFieldAccess struct {
+	RequestId  int            // Request that generated event
+	Thread     ThreadId       // Accessing thread
+	Location   Location       // Location of access
+	RefTypeTag uint8          // Kind of reference type.
+	TypeId     ClassId        // Type of field
+	FieldId    FieldId        // Field being accessed
+	Object     TaggedObjectId // Object being accessed (null=0 for statics
+}
+
+func (*EventCompositeFieldAccess) alternative() uint8 {
+	return 20
+}
+
+// EventCompositeFieldModification is the EventCompositeEventKind of eventKind 21. Notification
+// of a field modification in the target VM.
+type EventCompositeFieldModification struct {
+	RequestId  int            // Request that generated event
+	Thread     ThreadId       // Modifying thread
+	Location   Location       // Location of modify
+	RefTypeTag uint8          // Kind of reference type.
+	TypeId     ClassId        // Type of field
+	FieldId    FieldId        // Field being modified
+	Object     TaggedObjectId // Object being modified (null=0 for statics
+	ValueToBe  TaggedValue    // Value to be assigned
+}
+
+func (*EventCompositeFieldModification) alternative() uint8 {
+	return 21
+}
+
+// EventCompositeVMDeath is the EventCompositeEventKind of eventKind 99. Notification of target
+// VM termination.
+type EventCompositeVMDeath struct {
+	RequestId int // Request that generated event
+}
+
+func (*EventCompositeVMDeath) alternative() uint8 {
+	return 99
+}
+
+// specReplies make the values that the replies to commands are parsed into.
+var specReplies = map[CommandKey]func() interface{}{
+	{VirtualMachine, VirtualMachineVersion}:                       func() interface{} { return &VirtualMachineVersionReply{} },
+	{VirtualMachine, VirtualMachineClassesBySignature}:            func() interface{} { return &VirtualMachineClassesBySignatureReply{} },
+	{VirtualMachine, VirtualMachineAllClasses}:                    func() interface{} { return &VirtualMachineAllClassesReply{} },
+	{VirtualMachine, VirtualMachineAllThreads}:                    func() interface{} { return &VirtualMachineAllThreadsReply{} },
+	{VirtualMachine, VirtualMachineTopLevelThreadGroups}:          func() interface{} { return &VirtualMachineTopLevelThreadGroupsReply{} },
+	{VirtualMachine, VirtualMachineIDSizes}:                       func() interface{} { return &VirtualMachineIDSizesReply{} },
+	{VirtualMachine, VirtualMachineCreateString}:                  func() interface{} { return &VirtualMachineCreateStringReply{} },
+	{VirtualMachine, VirtualMachineCapabilities}:                  func() interface{} { return &VirtualMachineCapabilitiesReply{} },
+	{VirtualMachine, VirtualMachineClassPaths}:                    func() interface{} { return &VirtualMachineClassPathsReply{} },
+	{VirtualMachine, VirtualMachineCapabilitiesNew}:               func() interface{} { return &VirtualMachineCapabilitiesNewReply{} },
+	{VirtualMachine, VirtualMachineAllClassesWithGeneric}:         func() interface{} { return &VirtualMachineAllClassesWithGenericReply{} },
+	{VirtualMachine, VirtualMachineInstanceCounts}:                func() interface{} { return &VirtualMachineInstanceCountsReply{} },
+	{VirtualMachine, VirtualMachineAllModules}:                    func() interface{} { return &VirtualMachineAllModulesReply{} },
+	{ReferenceType, ReferenceTypeSignature}:                       func() interface{} { return &ReferenceTypeSignatureReply{} },
+	{ReferenceType, ReferenceTypeClassLoader}:                     func() interface{} { return &ReferenceTypeClassLoaderReply{} },
+	{ReferenceType, ReferenceTypeModifiers}:                       func() interface{} { return &ReferenceTypeModifiersReply{} },
+	{ReferenceType, ReferenceTypeFields}:                          func() interface{} { return &ReferenceTypeFieldsReply{} },
+	{ReferenceType, ReferenceTypeMethods}:                         func() interface{} { return &ReferenceTypeMethodsReply{} },
+	{ReferenceType, ReferenceTypeGetValues}:                       func() interface{} { return &ReferenceTypeGetValuesReply{} },
+	{ReferenceType, ReferenceTypeSourceFile}:                      func() interface{} { return &ReferenceTypeSourceFileReply{} },
+	{ReferenceType, ReferenceTypeNestedTypes}:                     func() interface{} { return &ReferenceTypeNestedTypesReply{} },
+	{ReferenceType, ReferenceTypeStatus}:                          func() interface{} { return &ReferenceTypeStatusReply{} },
+	{ReferenceType, ReferenceTypeInterfaces}:                      func() interface{} { return &ReferenceTypeInterfacesReply{} },
+	{ReferenceType, ReferenceTypeClassObject}:                     func() interface{} { return &ReferenceTypeClassObjectReply{} },
+	{ReferenceType, ReferenceTypeSourceDebugExtension}:            func() interface{} { return &ReferenceTypeSourceDebugExtensionReply{} },
+	{ReferenceType, ReferenceTypeSignatureWithGeneric}:            func() interface{} { return &ReferenceTypeSignatureWithGenericReply{} },
+	{ReferenceType, ReferenceTypeFieldsWithGeneric}:               func() interface{} { return &ReferenceTypeFieldsWithGenericReply{} },
+	{ReferenceType, ReferenceTypeMethodsWithGeneric}:              func() interface{} { return &ReferenceTypeMethodsWithGenericReply{} },
+	{ReferenceType, ReferenceTypeInstances}:                       func() interface{} { return &ReferenceTypeInstancesReply{} },
+	{ReferenceType, ReferenceTypeClassFileVersion}:                func() interface{} { return &ReferenceTypeClassFileVersionReply{} },
+	{ReferenceType, ReferenceTypeConstantPool}:                    func() interface{} { return &ReferenceTypeConstantPoolReply{} },
+	{ReferenceType, ReferenceTypeModule}:                          func() interface{} { return &ReferenceTypeModuleReply{} },
+	{ClassType, ClassTypeSuperclass}:                              func() interface{} { return &ClassTypeSuperclassReply{} },
+	{ClassType, ClassTypeInvokeMethod}:                            func() interface{} { return &ClassTypeInvokeMethodReply{} },
+	{ClassType, ClassTypeNewInstance}:                             func() interface{} { return &ClassTypeNewInstanceReply{} },
+	{ArrayType, ArrayTypeNewInstance}:                             func() interface{} { return &ArrayTypeNewInstanceReply{} },
+	{InterfaceType, InterfaceTypeInvokeMethod}:                    func() interface{} { return &InterfaceTypeInvokeMethodReply{} },
+	{Method, MethodLineTable}:                                     func() interface{} { return &MethodLineTableReply{} },
+	{Method, MethodVariableTable}:                                 func() interface{} { return &MethodVariableTableReply{} },
+	{Method, MethodBytecodes}:                                     func() interface{} { return &MethodBytecodesReply{} },
+	{Method, MethodIsObsolete}:                                    func() interface{} { return &MethodIsObsoleteReply{} },
+	{Method, MethodVariableTableWithGeneric}:                      func() interface{} { return &MethodVariableTableWithGenericReply{} },
+	{ObjectReference, ObjectReferenceReferenceType}:               func() interface{} { return &ObjectReferenceReferenceTypeReply{} },
+	{ObjectReference, ObjectReferenceGetValues}:                   func() interface{} { return &ObjectReferenceGetValuesReply{} },
+	{ObjectReference, ObjectReferenceMonitorInfo}:                 func() interface{} { return &ObjectReferenceMonitorInfoReply{} },
+	{ObjectReference, ObjectReferenceInvokeMethod}:                func() interface{} { return &ObjectReferenceInvokeMethodReply{} },
+	{ObjectReference, ObjectReferenceIsCollected}:                 func() interface{} { return &ObjectReferenceIsCollectedReply{} },
+	{ObjectReference, ObjectReferenceReferringObjects}:            func() interface{} { return &ObjectReferenceReferringObjectsReply{} },
+	{StringReference, StringReferenceValue}:                       func() interface{} { return &StringReferenceValueReply{} },
+	{ThreadReference, ThreadReferenceName}:                        func() interface{} { return &ThreadReferenceNameReply{} },
+	{ThreadReference, ThreadReferenceStatus}:                      func() interface{} { return &ThreadReferenceStatusReply{} },
+	{ThreadReference, ThreadReferenceThreadGroup}:                 func() interface{} { return &ThreadReferenceThreadGroupReply{} },
+	{ThreadReference, ThreadReferenceFrames}:                      func() interface{} { return &ThreadReferenceFramesReply{} },
+	{ThreadReference, ThreadReferenceFrameCount}:                  func() interface{} { return &ThreadReferenceFrameCountReply{} },
+	{ThreadReference, ThreadReferenceOwnedMonitors}:               func() interface{} { return &ThreadReferenceOwnedMonitorsReply{} },
+	{ThreadReference, ThreadReferenceCurrentContendedMonitor}:     func() interface{} { return &ThreadReferenceCurrentContendedMonitorReply{} },
+	{ThreadReference, ThreadReferenceSuspendCount}:                func() interface{} { return &ThreadReferenceSuspendCountReply{} },
+	{ThreadReference, ThreadReferenceOwnedMonitorsStackDepthInfo}: func() interface{} { return &ThreadReferenceOwnedMonitorsStackDepthInfoReply{} },
+	{ThreadGroupReference, ThreadGroupReferenceName}:              func() interface{} { return &ThreadGroupReferenceNameReply{} },
+	{ThreadGroupReference, ThreadGroupReferenceParent}:            func() interface{} { return &ThreadGroupReferenceParentReply{} },
+	{ThreadGroupReference, ThreadGroupReferenceChildren}:          func() interface{} { return &ThreadGroupReferenceChildrenReply{} },
+	{ArrayReference, ArrayReferenceLength}:                        func() interface{} { return &ArrayReferenceLengthReply{} },
+	{ArrayReference, ArrayReferenceGetValues}:                     func() interface{} { return &ArrayReferenceGetValuesReply{} },
+	{ClassLoaderReference, ClassLoaderReferenceVisibleClasses}:    func() interface{} { return &ClassLoaderReferenceVisibleClassesReply{} },
+	{EventRequest, EventRequestSetCommand}:                        func() interface{} { return &EventRequestSetReply{} },
+	{StackFrame, StackFrameGetValues}:                             func() interface{} { return &StackFrameGetValuesReply{} },
+	{StackFrame, StackFrameThisObject}:                            func() interface{} { return &StackFrameThisObjectReply{} },
+	{ClassObjectReference, ClassObjectReferenceReflectedType}:     func() interface{} { return &ClassObjectReferenceReflectedTypeReply{} },
+	{ModuleReference, ModuleReferenceName}:                        func() interface{} { return &ModuleReferenceNameReply{} },
+	{ModuleReference, ModuleReferenceClassLoader}:                 func() interface{} { return &ModuleReferenceClassLoaderReply{} },
+}
+
+// NewReply returns a new value of the type that the reply to a command is parsed into, or nil
+// if the command has no reply body or the specification has no such command.
+func NewReply(set CommandSet, cmd Command) interface{} {
+	if f, ok := specReplies[CommandKey{set, cmd}]; ok {
+		return f()
+	}
+	return nil
+}
+
+// eventRequestSetModKindAlternatives are the alternatives of EventRequestSetModKind, by the
+// byte selecting each.
+var eventRequestSetModKindAlternatives = map[uint8]func() interface{}{
+	1:  func() interface{} { return &EventRequestSetCount{} },
+	2:  func() interface{} { return &EventRequestSetConditional{} },
+	3:  func() interface{} { return &EventRequestSetThreadOnly{} },
+	4:  func() interface{} { return &EventRequestSetClassOnly{} },
+	5:  func() interface{} { return &EventRequestSetClassMatch{} },
+	6:  func() interface{} { return &EventRequestSetClassExclude{} },
+	7:  func() interface{} { return &EventRequestSetLocationOnly{} },
+	8:  func() interface{} { return &EventRequestSetExceptionOnly{} },
+	9:  func() interface{} { return &EventRequestSetFieldOnly{} },
+	10: func() interface{} { return &EventRequestSetStep{} },
+	11: func() interface{} { return &EventRequestSetInstanceOnly{} },
+	12: func() interface{} { return &EventRequestSetSourceNameMatch{} },
+}
+
+// eventCompositeEventKindAlternatives are the alternatives of EventCompositeEventKind, by the
+// byte selecting each.
+var eventCompositeEventKindAlternatives = map[uint8]func() interface{}{
+	1:  func() interface{} { return &EventCompositeSingleStep{} },
+	2:  func() interface{} { return &EventCompositeBreakpoint{} },
+	4:  func() interface{} { return &EventCompositeException{} },
+	6:  func() interface{} { return &EventCompositeThreadStart{} },
+	7:  func() interface{} { return &EventCompositeThreadDeath{} },
+	8:  func() interface{} { return &EventCompositeClassPrepare{} },
+	9:  func() interface{} { return &EventCompositeClassUnload{} },
+	20: func() interface{} { return &EventCompositeFieldAccess{} },
+	21: func() interface{} { return &EventCompositeFieldModification{} },
+	40: func() interface{} { return &EventCompositeMethodEntry{} },
+	41: func() interface{} { return &EventCompositeMethodExit{} },
+	42: func() interface{} { return &EventCompositeMethodExitWithReturnValue{} },
+	43: func() interface{} { return &EventCompositeMonitorContendedEnter{} },
+	44: func() interface{} { return &EventCompositeMonitorContendedEntered{} },
+	45: func() interface{} { return &EventCompositeMonitorWait{} },
+	46: func() interface{} { return &EventCompositeMonitorWaited{} },
+	90: func() interface{} { return &EventCompositeVMStart{} },
+	99: func() interface{} { return &EventCompositeVMDeath{} },
+}
+
+func init() {
+	RegisterFactory([]EventRequestSetModKind{}, alternatives(eventRequestSetModKindAlternatives))
+	RegisterFactory([]EventCompositeEventKind{}, alternatives(eventCompositeEventKindAlternatives))
+}
@@ -0,0 +1,110 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+func TestGeneratedCommands(t *testing.T) {
+	vm := jdwptest.NewVM()
+	p := newProgram(vm)
+	c := connect(t, jdwptest.NewAgent(vm))
+	defer c.Close()
+
+	v, err := client.VirtualMachineVersionRequest{}.Call(c)
+	require.NoError(t, err)
+	assert.Equal(t, vm.Version.Description, v.Description)
+	assert.Equal(t, vm.Version.VmVersion, v.VmVersion)
+
+	name, err := p.thread.Id.Name(c)
+	require.NoError(t, err)
+	assert.Equal(t, "main", name.ThreadName)
+
+	_, err = client.ThreadId(999).Name(c)
+	assert.Equal(t, client.ErrInvalidThread, err)
+
+	// The class object is found through the object's type, as the specification has it
+	co, err := p.self.Id.ClassObject(c)
+	require.NoError(t, err)
+	assert.Equal(t, p.main.Object, co)
+
+	run := method(t, c, p.main.Id, "run")
+	lines, err := run.MethodId.MethodLineTable(c)
+	require.NoError(t, err)
+	assert.Equal(t, []client.MethodLineTableLineInfo{{0, 10}, {8, 11}, {16, 12}}, lines.Lines)
+	assert.Equal(t, 3, lines.NumLines)
+}
+
+func TestGeneratedRequestMatchesBuilder(t *testing.T) {
+	vm := jdwptest.NewVM()
+	p := newProgram(vm)
+	c := connect(t, jdwptest.NewAgent(vm))
+	defer c.Close()
+
+	run := method(t, c, p.main.Id, "run")
+	loc := client.Location{TypeTag: client.TypeTagClass, ClassId: p.main.Id, MethodId: run.MethodId, Index: 8}
+	req := client.EventRequestSetRequest{
+		EventKind:     uint8(client.EventKindBreakpoint),
+		SuspendPolicy: uint8(client.SuspendPolicyAll),
+		Modifiers: []client.EventRequestSetModKind{
+			&client.EventRequestSetCount{Count: 1},
+			&client.EventRequestSetLocationOnly{Loc: loc},
+		},
+	}
+	data, err := c.IDSizes().Marshal(req)
+	require.NoError(t, err)
+	built := client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyAll).
+		WithMod(client.ModKindCount).WithInt(1).
+		WithMod(client.ModKindLocation).WithLocation(loc)
+	assert.Equal(t, built.Marshal(c.IDSizes()), data)
+
+	reply, err := req.Call(c)
+	require.NoError(t, err)
+	rs := vm.Requests()
+	require.Len(t, rs, 1)
+	assert.Equal(t, int(rs[0].Id), reply.RequestId)
+}
+
+func TestParseGeneratedComposite(t *testing.T) {
+	sizes := client.IDSizes{FieldIDSize: 4, MethodIDSize: 4, ObjectIDSize: 4, ReferenceTypeIDSize: 4, FrameIDSize: 4}
+	data := []byte{
+		1,
+		0, 0, 0, 1,
+		2,          // EventKind.Breakpoint
+		0, 0, 0, 2, // requestId
+		0, 0, 0, 3, // threadId
+		1,          // TypeTag = CLASS
+		0, 0, 0, 2, // ClassId
+		0, 0, 0, 9, // MethodId
+		0, 0, 0, 0, 0, 0, 0, 4, // Index
+	}
+	var ev client.EventCompositeEvent
+	require.NoError(t, sizes.Parse(data, &ev))
+	require.Len(t, ev.Events, 1)
+	if bp, ok := ev.Events[0].(*client.EventCompositeBreakpoint); assert.True(t, ok) {
+		assert.Equal(t, 2, bp.RequestId)
+		assert.Equal(t, client.ThreadId(3), bp.Thread)
+		assert.Equal(t, client.ClassId(2), bp.Location.ClassId)
+		assert.Equal(t, uint64(4), bp.Location.Index)
+	}
+
+	again, err := sizes.Marshal(&ev)
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestCommandNamesAndReplies(t *testing.T) {
+	assert.Equal(t, "VirtualMachine.Version", client.CommandName(client.VirtualMachine, client.VirtualMachineVersion))
+	assert.Equal(t, "ReferenceType.ClassObject", client.CommandName(client.ReferenceType, client.ReferenceTypeClassObject))
+	assert.Equal(t, "Event.Composite", client.CommandName(client.EventCommandSet, client.CompositeCommands))
+	assert.Equal(t, "", client.CommandName(client.VirtualMachine, 99))
+
+	assert.IsType(t, &client.VirtualMachineVersionReply{}, client.NewReply(client.VirtualMachine, client.VirtualMachineVersion))
+	assert.Nil(t, client.NewReply(client.VirtualMachine, client.VirtualMachineDispose))
+	assert.Nil(t, client.NewReply(client.VirtualMachine, 99))
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The tables written by hand must cover the specification, and only the specification.
+
+func TestMutatingCoversSpecification(t *testing.T) {
+	for k, name := range specCommands {
+		_, ok := mutating[k]
+		assert.True(t, ok, "%s is missing from mutating", name)
+	}
+	for k := range mutating {
+		_, ok := specCommands[k]
+		assert.True(t, ok, "%d.%d is not in the specification", k.Set, k.Command)
+	}
+}
+
+// eventFields are the EventFields by which the fields of the generated events are passed over.
+var eventFields = map[reflect.Type]EventField{
+	reflect.TypeOf(ThreadId(0)):                EventFieldThread,
+	reflect.TypeOf(Location{}):                 EventFieldLocation,
+	reflect.TypeOf(TaggedObjectId{}):           EventFieldTaggedObject,
+	reflect.TypeOf((*TaggedValue)(nil)).Elem(): EventFieldValue,
+	reflect.TypeOf(uint8(0)):                   EventFieldByte,
+	reflect.TypeOf(false):                      EventFieldByte,
+	reflect.TypeOf(0):                          EventFieldInt,
+	reflect.TypeOf(int64(0)):                   EventFieldLong,
+	reflect.TypeOf(ClassId(0)):                 EventFieldReferenceType,
+	reflect.TypeOf(FieldId(0)):                 EventFieldField,
+	reflect.TypeOf(""):                         EventFieldString,
+}
+
+func TestEventLayoutsCoverSpecification(t *testing.T) {
+	for kind, newEvent := range eventCompositeEventKindAlternatives {
+		et := reflect.TypeOf(newEvent()).Elem()
+		layout, ok := eventLayouts[EventKind(kind)]
+		if !assert.True(t, ok, "%s has no layout", et) {
+			continue
+		}
+		var fields []EventField
+		for i := 1; i < et.NumField(); i++ { // after the request ID
+			f, ok := eventFields[et.Field(i).Type]
+			require.True(t, ok, "%s.%s", et, et.Field(i).Name)
+			fields = append(fields, f)
+		}
+		if len(fields) > 0 || len(layout) > 0 {
+			assert.Equal(t, fields, layout, "%s", et)
+		}
+	}
+	for kind := range eventLayouts {
+		if kind < EventKindVM_DISCONNECTED {
+			_, ok := eventCompositeEventKindAlternatives[uint8(kind)]
+			assert.True(t, ok, "event kind %d is not in the specification", kind)
+		}
+	}
+}
+
+func TestBuiltinEventsMatchSpecification(t *testing.T) {
+	for kind, newEvent := range builtinEvents {
+		et := reflect.TypeOf(newEvent()).Elem()
+		newAlt, ok := eventCompositeEventKindAlternatives[uint8(kind)]
+		if !ok {
+			assert.True(t, kind >= EventKindVM_DISCONNECTED, "%s is not in the specification", et)
+			continue
+		}
+		gt := reflect.TypeOf(newAlt()).Elem()
+		require.Equal(t, gt.NumField(), et.NumField(), "%s", et)
+		for i := 0; i < gt.NumField(); i++ {
+			assert.Equal(t, gt.Field(i).Name, et.Field(i).Name, "%s", et)
+			assert.Equal(t, gt.Field(i).Type, et.Field(i).Type, "%s.%s", et, et.Field(i).Name)
+		}
+	}
+}
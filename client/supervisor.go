@@ -0,0 +1,495 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNotConnected is returned by a Supervisor asked to talk to the VM between connections.
+var ErrNotConnected = errors.New("jdwp supervisor is not connected")
+
+// Backoff spaces out attempts to reconnect: the delay starts at Min, and doubles with each
+// consecutive failure up to Max.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+var DefaultBackoff = Backoff{Min: 100 * time.Millisecond, Max: 30 * time.Second}
+
+func (b Backoff) delay(attempt int) time.Duration {
+	d := b.Min
+	for i := 1; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+type LifecycleKind int
+
+const (
+	LifecycleConnected   LifecycleKind = iota // a connection was made, and before any re-arming
+	LifecycleLost                             // the connection ended
+	LifecycleDialFailed                       // an attempt to connect failed
+	LifecycleRearmed                          // a request was re-issued on a new connection
+	LifecycleRearmFailed                      // a request could not be re-issued
+)
+
+func (k LifecycleKind) String() string {
+	switch k {
+	case LifecycleConnected:
+		return "connected"
+	case LifecycleLost:
+		return "lost"
+	case LifecycleDialFailed:
+		return "dial failed"
+	case LifecycleRearmed:
+		return "re-armed"
+	case LifecycleRearmFailed:
+		return "re-arm failed"
+	}
+	return fmt.Sprintf("LifecycleKind(%d)", int(k))
+}
+
+// Lifecycle is a notification of a change in a Supervisor's connection.
+type Lifecycle struct {
+	Kind    LifecycleKind
+	Client  Client             // for Connected and Lost, the connection concerned
+	Request *SupervisedRequest // for Rearmed and RearmFailed
+	Attempt int                // for DialFailed, the number of consecutive failures
+	Err     error              // for Lost, why; for DialFailed and RearmFailed, what went wrong
+}
+
+// Supervisor keeps a connection to a VM that may restart. When the connection drops, it
+// dials again, with backoff, and re-issues the event requests made through it. Class and
+// method IDs do not survive a restart, so requests are recorded by class signature and
+// method name, and resolved afresh on each connection.
+//
+// A class that the new VM has not yet loaded cannot be resolved; its requests are reported
+// with LifecycleRearmFailed, and may be retried with Rearm.
+type Supervisor struct {
+	dial      func() (Client, error)
+	backoff   Backoff
+	mu        sync.Mutex // held only briefly, never while talking to the VM
+	current   Client
+	requests  []*SupervisedRequest
+	arming    sync.Mutex // held while requests are re-issued, so that each is issued once
+	stop      chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+	e         chan *Event
+	events    *packetQueue
+	l         chan Lifecycle
+	lifecycle *packetQueue
+}
+
+// Supervise starts connecting with dial, which is typically a closure around Dial.
+func Supervise(dial func() (Client, error), backoff Backoff) *Supervisor {
+	s := &Supervisor{
+		dial:      dial,
+		backoff:   backoff,
+		stop:      make(chan struct{}),
+		e:         make(chan *Event),
+		events:    newPacketQueue("supervised events", DefaultEventQueueSize, DropOldest, DefaultLogger),
+		l:         make(chan Lifecycle),
+		lifecycle: newPacketQueue("lifecycle", DefaultEventQueueSize, DropOldest, DefaultLogger),
+	}
+	s.wg.Add(3)
+	go s.run()
+	go func() {
+		defer s.wg.Done()
+		defer close(s.e)
+		s.events.deliver(s.stop, func(e interface{}) bool {
+			select {
+			case s.e <- e.(*Event):
+				return true
+			case <-s.stop:
+				return false
+			}
+		})
+	}()
+	go func() {
+		defer s.wg.Done()
+		defer close(s.l)
+		s.lifecycle.deliver(s.stop, func(l interface{}) bool {
+			select {
+			case s.l <- l.(Lifecycle):
+				return true
+			case <-s.stop:
+				return false
+			}
+		})
+	}()
+	return s
+}
+
+// Events carries the events of every connection in turn, each ending with a VM_DISCONNECTED
+// event. Request IDs in the events are those of the connection they arrived on; see
+// SupervisedRequest.Id.
+func (s *Supervisor) Events() <-chan *Event {
+	return s.e
+}
+
+func (s *Supervisor) Lifecycle() <-chan Lifecycle {
+	return s.l
+}
+
+// Client is the current connection, or nil between connections.
+func (s *Supervisor) Client() Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// Close stops reconnecting and closes the current connection.
+func (s *Supervisor) Close() error {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.mu.Lock()
+	c := s.current
+	s.mu.Unlock()
+	var err error
+	if c != nil {
+		err = c.Close()
+	}
+	s.wg.Wait()
+	return err
+}
+
+func (s *Supervisor) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Supervisor) notify(l Lifecycle) {
+	s.lifecycle.push(l)
+}
+
+func (s *Supervisor) run() {
+	defer s.wg.Done()
+	defer s.events.end()
+	defer s.lifecycle.end()
+	attempt := 0
+	for !s.stopped() {
+		c, err := s.dial()
+		if err != nil {
+			attempt++
+			s.notify(Lifecycle{Kind: LifecycleDialFailed, Attempt: attempt, Err: err})
+			select {
+			case <-time.After(s.backoff.delay(attempt)):
+			case <-s.stop:
+			}
+			continue
+		}
+		attempt = 0
+
+		s.mu.Lock()
+		if s.stopped() {
+			s.mu.Unlock()
+			c.Close()
+			return
+		}
+		s.current = c
+		s.notify(Lifecycle{Kind: LifecycleConnected, Client: c})
+		s.mu.Unlock()
+		s.rearm(c)
+
+		for e := range c.Events() {
+			s.events.push(e)
+		}
+
+		s.mu.Lock()
+		s.current = nil
+		for _, r := range s.requests {
+			r.setId(0)
+		}
+		s.mu.Unlock()
+		s.notify(Lifecycle{Kind: LifecycleLost, Client: c, Err: c.Err()})
+		c.Close()
+	}
+}
+
+// rearm issues every recorded request not yet set on c.
+func (s *Supervisor) rearm(c Client) error {
+	s.arming.Lock()
+	defer s.arming.Unlock()
+	s.mu.Lock()
+	requests := append([]*SupervisedRequest(nil), s.requests...)
+	s.mu.Unlock()
+	var first error
+	for _, r := range requests {
+		if r.Id() != 0 {
+			continue
+		}
+		id, err := r.arm(c)
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			s.notify(Lifecycle{Kind: LifecycleRearmFailed, Request: r, Err: err})
+			continue
+		}
+		if !s.armed(c, r, id) {
+			continue
+		}
+		s.notify(Lifecycle{Kind: LifecycleRearmed, Request: r})
+	}
+	return first
+}
+
+// armed records that r was set on c as id, unless r has been cleared or c has gone since,
+// in which case the request is cleared again as best it can be.
+func (s *Supervisor) armed(c Client, r *SupervisedRequest, id int) bool {
+	s.mu.Lock()
+	wanted := s.current == c && s.recorded(r)
+	if wanted {
+		r.setId(id)
+	}
+	s.mu.Unlock()
+	if !wanted && c.Err() == nil {
+		EventRequestClear{EventKind: r.kind, RequestId: id}.Clear(c)
+	}
+	return wanted
+}
+
+// recorded reports whether r is among the requests re-issued on each connection. It is
+// called with s.mu held.
+func (s *Supervisor) recorded(r *SupervisedRequest) bool {
+	for _, rr := range s.requests {
+		if rr == r {
+			return true
+		}
+	}
+	return false
+}
+
+// Rearm retries the requests that are not set on the current connection, returning the
+// first error encountered.
+func (s *Supervisor) Rearm() error {
+	c := s.Client()
+	if c == nil {
+		return ErrNotConnected
+	}
+	return s.rearm(c)
+}
+
+// Set issues an event request on the current connection, and records it to be issued
+// again on each later one. Counts, class patterns, classes, locations and the flags of an
+// Exception modifier are carried across a restart; modifiers naming a thread (ThreadOnly and
+// Step), an object or a field cannot be, and are refused.
+func (s *Supervisor) Set(req *EventRequestSet) (*SupervisedRequest, error) {
+	c := s.Client()
+	if c == nil {
+		return nil, ErrNotConnected
+	}
+	r, err := record(c, req)
+	if err != nil {
+		return nil, err
+	}
+	id, err := req.Set(c)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	current := s.current
+	if current == c {
+		r.setId(id)
+	}
+	s.mu.Unlock()
+	if current != c && current != nil {
+		// The connection was replaced while the request was being set, after its requests
+		// were re-issued
+		s.rearm(current)
+	}
+	return r, nil
+}
+
+// Clear forgets a request, clearing it on the current connection if it is set there.
+func (s *Supervisor) Clear(r *SupervisedRequest) error {
+	s.mu.Lock()
+	for i, rr := range s.requests {
+		if rr == r {
+			s.requests = append(s.requests[:i], s.requests[i+1:]...)
+			break
+		}
+	}
+	id := r.Id()
+	r.setId(0)
+	c := s.current
+	s.mu.Unlock()
+	if c == nil || id == 0 {
+		return nil
+	}
+	return EventRequestClear{EventKind: r.kind, RequestId: id}.Clear(c)
+}
+
+// SupervisedRequest is an event request that a Supervisor re-issues on each connection.
+type SupervisedRequest struct {
+	kind   EventKind
+	policy SuspendPolicy
+	mods   []Mod // with IDs replaced by classRef and methodRef
+	mu     sync.Mutex
+	id     int
+}
+
+// Id is the request's ID on the current connection, or 0 if it is not set there.
+func (r *SupervisedRequest) Id() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.id
+}
+
+func (r *SupervisedRequest) setId(id int) {
+	r.mu.Lock()
+	r.id = id
+	r.mu.Unlock()
+}
+
+func (r *SupervisedRequest) EventKind() EventKind {
+	return r.kind
+}
+
+// classRef is a reference type, recorded by signature.
+type classRef struct {
+	signature string
+}
+
+// methodRef is a location, recorded by class signature and method name and signature.
+type methodRef struct {
+	class     classRef
+	tag       TypeTag
+	name      string
+	signature string
+	index     uint64
+}
+
+// record turns the IDs in req into names that will mean the same thing after a restart.
+func record(c Client, req *EventRequestSet) (*SupervisedRequest, error) {
+	r := &SupervisedRequest{kind: req.EventKind, policy: req.SuspendPolicy}
+	for _, m := range req.Mods {
+		rm := Mod{ModKind: m.ModKind}
+		for _, v := range m.Values {
+			switch v := v.(type) {
+			case int32, string, bool:
+				rm.Values = append(rm.Values, v)
+			case ThreadId:
+				return nil, fmt.Errorf("cannot supervise a request with modifier %d, whose thread does not survive a restart", m.ModKind)
+			case ReferenceTypeId:
+				if m.ModKind != ModKindClass && m.ModKind != ModKindException {
+					return nil, fmt.Errorf("cannot supervise a request with modifier %d, whose IDs do not survive a restart", m.ModKind)
+				}
+				cr, err := recordClass(c, ClassId(v))
+				if err != nil {
+					return nil, err
+				}
+				rm.Values = append(rm.Values, cr)
+			case Location:
+				mr, err := recordLocation(c, v)
+				if err != nil {
+					return nil, err
+				}
+				rm.Values = append(rm.Values, mr)
+			default:
+				return nil, fmt.Errorf("cannot supervise event request modifier value %#v", v)
+			}
+		}
+		r.mods = append(r.mods, rm)
+	}
+	return r, nil
+}
+
+func recordClass(c Client, id ClassId) (classRef, error) {
+	if id == 0 {
+		return classRef{}, nil
+	}
+	sig, err := id.Signature(c)
+	return classRef{signature: sig}, err
+}
+
+func recordLocation(c Client, l Location) (methodRef, error) {
+	cr, err := recordClass(c, l.ClassId)
+	if err != nil {
+		return methodRef{}, err
+	}
+	ms, err := l.ClassId.Methods(c)
+	if err != nil {
+		return methodRef{}, err
+	}
+	for _, m := range ms {
+		if m.MethodId.MethodId == l.MethodId.MethodId {
+			return methodRef{class: cr, tag: l.TypeTag, name: m.Name, signature: m.Signature, index: l.Index}, nil
+		}
+	}
+	return methodRef{}, fmt.Errorf("method %d not found in %s", l.MethodId.MethodId, cr.signature)
+}
+
+// arm resolves the request's names on c, and sets it there.
+func (r *SupervisedRequest) arm(c Client) (int, error) {
+	req := NewEventRequestSet(r.kind, r.policy)
+	for _, m := range r.mods {
+		req.WithMod(m.ModKind)
+		for _, v := range m.Values {
+			switch v := v.(type) {
+			case classRef:
+				id, err := v.resolve(c)
+				if err != nil {
+					return 0, err
+				}
+				req.WithReferenceTypeId(ReferenceTypeId(id))
+			case methodRef:
+				l, err := v.resolve(c)
+				if err != nil {
+					return 0, err
+				}
+				req.WithLocation(l)
+			default:
+				req.with(v)
+			}
+		}
+	}
+	return req.Set(c)
+}
+
+func (cr classRef) resolve(c Client) (ClassId, error) {
+	if cr.signature == "" {
+		return 0, nil
+	}
+	cs, err := ClassesBySignature(c, cr.signature)
+	if err != nil {
+		return 0, err
+	}
+	if len(cs) == 0 {
+		return 0, fmt.Errorf("class %s is not loaded", cr.signature)
+	}
+	return cs[0].ClassId, nil
+}
+
+func (mr methodRef) resolve(c Client) (Location, error) {
+	id, err := mr.class.resolve(c)
+	if err != nil {
+		return Location{}, err
+	}
+	ms, err := id.Methods(c)
+	if err != nil {
+		return Location{}, err
+	}
+	for _, m := range ms {
+		if m.Name == mr.name && m.Signature == mr.signature {
+			l := NewLocation(m.MethodId, int64(mr.index))
+			l.TypeTag = mr.tag
+			return l, nil
+		}
+	}
+	return Location{}, fmt.Errorf("method %s%s not found in %s", mr.name, mr.signature, mr.class.signature)
+}
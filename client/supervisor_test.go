@@ -0,0 +1,210 @@
+package client_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+// restartable stands in for a VM that can be restarted; dialling it fails while it is down.
+type restartable struct {
+	mu    sync.Mutex
+	agent *jdwptest.Agent
+}
+
+func (r *restartable) dial() (client.Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.agent == nil {
+		return nil, errors.New("connection refused")
+	}
+	return client.New(r.agent.Pipe())
+}
+
+func (r *restartable) restart(a *jdwptest.Agent) {
+	r.mu.Lock()
+	old := r.agent
+	r.agent = a
+	r.mu.Unlock()
+	if old != nil {
+		for _, s := range old.Sessions() {
+			s.Close()
+		}
+	}
+}
+
+// await returns the next notification of the given kind, skipping any others.
+func await(t *testing.T, s *client.Supervisor, kind client.LifecycleKind) client.Lifecycle {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case l := <-s.Lifecycle():
+			if l.Kind == kind {
+				return l
+			}
+		case <-timeout:
+			t.Fatalf("no %v notification", kind)
+		}
+	}
+}
+
+func TestSupervisorRearmsAfterRestart(t *testing.T) {
+	vm := &restartable{}
+	s := client.Supervise(vm.dial, client.Backoff{Min: time.Millisecond, Max: 10 * time.Millisecond})
+	defer s.Close()
+
+	failed := await(t, s, client.LifecycleDialFailed)
+	assert.Equal(t, 1, failed.Attempt)
+	p1 := newProgram(jdwptest.NewVM())
+	vm.restart(jdwptest.NewAgent(p1.vm))
+	await(t, s, client.LifecycleConnected)
+
+	c := s.Client()
+	cls, err := client.ClassesBySignature(c, "Lcom/example/Main;")
+	require.NoError(t, err)
+	run := method(t, c, cls[0].ClassId, "run")
+	r, err := s.Set(client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyEventThread).
+		WithMod(client.ModKindLocation).WithLocation(client.NewLocation(run.MethodId, 8)))
+	require.NoError(t, err)
+	assert.NotZero(t, r.Id())
+
+	// The restarted VM has other classes loaded first, so its IDs differ
+	vm2 := jdwptest.NewVM()
+	vm2.AddClass("Lcom/example/Loaded;")
+	p2 := newProgram(vm2)
+	require.NotEqual(t, p1.main.Id, p2.main.Id)
+	a2 := jdwptest.NewAgent(p2.vm)
+	vm.restart(a2)
+
+	lost := await(t, s, client.LifecycleLost)
+	assert.True(t, errors.Is(lost.Err, client.ErrDisconnected))
+	await(t, s, client.LifecycleConnected)
+	rearmed := await(t, s, client.LifecycleRearmed)
+	assert.Equal(t, r, rearmed.Request)
+
+	reqs := p2.vm.Requests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, r.Id(), int(reqs[0].Id))
+	assert.Equal(t, p2.main.Id, reqs[0].Location.ClassId)
+	assert.Equal(t, p2.run.Id, reqs[0].Location.MethodId.MethodId)
+
+	// Events from the new VM come through
+	assert.Equal(t, 1, a2.Breakpoint(p2.thread))
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-s.Events():
+			var comp client.Composite
+			require.NoError(t, client.Parse(e.Data, &comp))
+			if bp, ok := comp.Events[0].(*client.EventBreakpoint); ok {
+				assert.Equal(t, r.Id(), bp.RequestId)
+				assert.Equal(t, p2.main.Id, bp.Location.ClassId)
+				return
+			}
+		case <-timeout:
+			t.Fatal("no breakpoint event")
+		}
+	}
+}
+
+func TestSupervisorReportsUnresolvableRequests(t *testing.T) {
+	vm := &restartable{}
+	vm.restart(jdwptest.NewAgent(newProgram(jdwptest.NewVM()).vm))
+	s := client.Supervise(vm.dial, client.Backoff{Min: time.Millisecond, Max: time.Millisecond})
+	defer s.Close()
+	await(t, s, client.LifecycleConnected)
+
+	c := s.Client()
+	cls, err := client.ClassesBySignature(c, "Lcom/example/Main;")
+	require.NoError(t, err)
+	r, err := s.Set(client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyEventThread).
+		WithMod(client.ModKindLocation).WithLocation(client.NewLocation(method(t, c, cls[0].ClassId, "run").MethodId, 0)))
+	require.NoError(t, err)
+
+	// This VM has not loaded the class
+	vm.restart(jdwptest.NewAgent(jdwptest.NewVM()))
+	failed := await(t, s, client.LifecycleRearmFailed)
+	assert.Equal(t, r, failed.Request)
+	assert.Zero(t, r.Id())
+	assert.Error(t, s.Rearm())
+
+	assert.NoError(t, s.Clear(r))
+	assert.NoError(t, s.Rearm())
+}
+
+func TestSupervisorRearmsPatternsAndFlags(t *testing.T) {
+	vm := &restartable{}
+	vm.restart(jdwptest.NewAgent(newProgram(jdwptest.NewVM()).vm))
+	s := client.Supervise(vm.dial, client.Backoff{Min: time.Millisecond, Max: time.Millisecond})
+	defer s.Close()
+	await(t, s, client.LifecycleConnected)
+
+	c := s.Client()
+	main := classBySignature(t, c, "Lcom/example/Main;")
+	r, err := s.Set(client.NewEventRequestSet(client.EventKindEXCEPTION, client.SuspendPolicyAll).
+		WithMod(client.ModKindClassMatch).WithString("com.example.*").
+		WithMod(client.ModKindClassExclude).WithString("com.example.internal.*").
+		WithMod(client.ModKindException).WithReferenceTypeId(client.ReferenceTypeId(main)).WithBool(false).WithBool(true))
+	require.NoError(t, err)
+
+	// Threads do not survive a restart
+	_, err = s.Set(client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyAll).
+		WithMod(client.ModKindThreadOnly).WithThreadId(1))
+	assert.Error(t, err)
+
+	p2 := newProgram(jdwptest.NewVM())
+	vm.restart(jdwptest.NewAgent(p2.vm))
+	rearmed := await(t, s, client.LifecycleRearmed)
+	assert.Equal(t, r, rearmed.Request)
+	reqs := p2.vm.Requests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, []string{"com.example.*"}, reqs[0].ClassMatch)
+	assert.Equal(t, []string{"com.example.internal.*"}, reqs[0].ClassExclude)
+	assert.Equal(t, p2.main.Id, reqs[0].Exception)
+	assert.False(t, reqs[0].Caught)
+	assert.True(t, reqs[0].Uncaught)
+}
+
+func TestSupervisorUnlockedWhileSetting(t *testing.T) {
+	vm := &restartable{}
+	a := jdwptest.NewAgent(newProgram(jdwptest.NewVM()).vm)
+	vm.restart(a)
+	s := client.Supervise(vm.dial, client.Backoff{Min: time.Millisecond, Max: time.Millisecond})
+	defer s.Close()
+	await(t, s, client.LifecycleConnected)
+	c := s.Client()
+	main := classBySignature(t, c, "Lcom/example/Main;")
+
+	// Recording the request asks the VM for the class's signature, which is slow to come
+	entered, release := make(chan struct{}), make(chan struct{})
+	a.Handle(client.ReferenceType, client.ReferenceTypeSignature, func(*jdwptest.Session, *jdwptest.Packet) ([]byte, error) {
+		close(entered)
+		<-release
+		return nil, client.ErrInvalidClass
+	})
+	set := make(chan error)
+	go func() {
+		_, err := s.Set(client.NewEventRequestSet(client.EventKindCLASS_PREPARE, client.SuspendPolicyNone).
+			WithMod(client.ModKindClass).WithReferenceTypeId(client.ReferenceTypeId(main)))
+		set <- err
+	}()
+	<-entered
+
+	got := make(chan client.Client)
+	go func() { got <- s.Client() }()
+	select {
+	case cc := <-got:
+		assert.Equal(t, c, cc)
+	case <-time.After(time.Second):
+		t.Error("the supervisor stayed locked while the VM was asked")
+	}
+	close(release)
+	assert.Equal(t, client.ErrInvalidClass, <-set)
+}
@@ -0,0 +1,2075 @@
+/*
+ * An abridged copy of the JDWP specification, in the grammar of OpenJDK's jdwp.spec, from
+ * which jdwpgen generates the command model in spec-generated.go. It covers JDWP as of
+ * Java 17, but it is not the upstream file: it was written out by hand with most of the
+ * descriptive text shortened, so no JDK tag can be named as its source, and the generated
+ * model is only as faithful as this copy.
+ *
+ * It is to be replaced by the upstream jdwp.spec, copied verbatim from a JDK tag recorded
+ * here, and the model regenerated with go generate.
+ */
+JDWP "Java(tm) Debug Wire Protocol"
+(CommandSet VirtualMachine=1
+    (Command Version=1
+        "Returns the JDWP version implemented by the target VM. "
+        "The version string format is implementation dependent. "
+        (Out
+        )
+        (Reply
+            (string description "Text information on the VM version")
+            (int    jdwpMajor   "Major JDWP Version number")
+            (int    jdwpMinor   "Minor JDWP Version number")
+            (string vmVersion   "Target VM JRE version, as in the java.version property")
+            (string vmName      "Target VM name, as in the java.vm.name property")
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command ClassesBySignature=2
+        "Returns reference types for all the classes loaded by the target VM "
+        "which match the given signature. "
+        (Out
+            (string signature "JNI signature of the class to find "
+                              "(for example, \"Ljava/lang/String;\"). ")
+        )
+        (Reply
+            (Repeat classes "Number of reference types that follow."
+                (Group ClassInfo
+                    (byte refTypeTag "Kind of following reference type. ")
+                    (referenceType typeID "Matching loaded reference type")
+                    (int status "The current class status. ")
+                )
+            )
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command AllClasses=3
+        "Returns reference types for all classes currently loaded by the target VM."
+        (Out
+        )
+        (Reply
+            (Repeat classes "Number of reference types that follow."
+                (Group ClassInfo
+                    (byte refTypeTag "Kind of following reference type. ")
+                    (referenceType typeID "Loaded reference type")
+                    (string signature "The JNI signature of the loaded reference type")
+                    (int status "The current class status. ")
+                )
+            )
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command AllThreads=4
+        "Returns all threads currently running in the target VM. "
+        (Out
+        )
+        (Reply
+            (Repeat threads "Number of threads that follow."
+                (threadObject thread "A running thread")
+            )
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command TopLevelThreadGroups=5
+        "Returns all thread groups that do not have a parent. "
+        (Out
+        )
+        (Reply
+            (Repeat groups "Number of thread groups that follow."
+                (threadGroupObject group "A top level thread group")
+            )
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command Dispose=6
+        "Invalidates this virtual machine mirror. "
+        "The communication channel to the target VM is closed, and the target VM "
+        "prepares to accept another subsequent connection from this debugger or another debugger. "
+        (Out
+        )
+        (Reply
+        )
+        (ErrorSet
+        )
+    )
+    (Command IDSizes=7
+        "Returns the sizes of variably-sized data types in the target VM."
+        (Out
+        )
+        (Reply
+            (int fieldIDSize "fieldID size in bytes ")
+            (int methodIDSize "methodID size in bytes ")
+            (int objectIDSize "objectID size in bytes ")
+            (int referenceTypeIDSize "referenceTypeID size in bytes ")
+            (int frameIDSize "frameID size in bytes ")
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command Suspend=8
+        "Suspends the execution of the application running in the target VM. "
+        "All Java threads currently running will be suspended. "
+        (Out
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command Resume=9
+        "Resumes execution of the application after the suspend command or an event "
+        "has stopped it. "
+        (Out
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command Exit=10
+        "Terminates the target VM with the given exit code. "
+        (Out
+            (int exitCode "the exit code")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command CreateString=11
+        "Creates a new string object in the target VM and returns its id. "
+        (Out
+            (string utf "UTF-8 characters to use in the created string. ")
+        )
+        (Reply
+            (stringObject stringObject "Created string (instance of java.lang.String) ")
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command Capabilities=12
+        "Retrieve this VM's capabilities. "
+        (Out
+        )
+        (Reply
+            (boolean canWatchFieldModification "Can the VM watch field modification, and therefore "
+                                               "can it send the Modification Watchpoint Event?")
+            (boolean canWatchFieldAccess "Can the VM watch field access, and therefore "
+                                         "can it send the Access Watchpoint Event?")
+            (boolean canGetBytecodes "Can the VM get the bytecodes of a given method? ")
+            (boolean canGetSyntheticAttribute "Can the VM determine whether a field or method is "
+                                              "synthetic? ")
+            (boolean canGetOwnedMonitorInfo "Can the VM get the owned monitors information for "
+                                            "a thread?")
+            (boolean canGetCurrentContendedMonitor "Can the VM get the current contended monitor of a thread?")
+            (boolean canGetMonitorInfo "Can the VM get the monitor information for a given object? ")
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command ClassPaths=13
+        "Retrieve the classpath and bootclasspath of the target VM. "
+        (Out
+        )
+        (Reply
+            (string baseDir "Base directory used to resolve relative paths in either of the following lists.")
+            (Repeat classpaths "Number of paths in classpath."
+                (string path "One component of classpath")
+            )
+            (Repeat bootclasspaths "Number of paths in bootclasspath."
+                (string path "One component of bootclasspath")
+            )
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command DisposeObjects=14
+        "Releases a list of object IDs. "
+        (Out
+            (Repeat requests "Number of object dispose requests that follow"
+                (Group Request
+                    (object object "The object ID")
+                    (int refCnt "The number of times this object ID has been part of a packet "
+                                "received from the back-end.")
+                )
+            )
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command HoldEvents=15
+        "Tells the target VM to stop sending events. "
+        (Out
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command ReleaseEvents=16
+        "Tells the target VM to continue sending events. "
+        (Out
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command CapabilitiesNew=17
+        "Retrieve all of this VM's capabilities. "
+        (Out
+        )
+        (Reply
+            (boolean canWatchFieldModification "Can the VM watch field modification? ")
+            (boolean canWatchFieldAccess "Can the VM watch field access? ")
+            (boolean canGetBytecodes "Can the VM get the bytecodes of a given method? ")
+            (boolean canGetSyntheticAttribute "Can the VM determine whether a field or method is synthetic? ")
+            (boolean canGetOwnedMonitorInfo "Can the VM get the owned monitors information for a thread?")
+            (boolean canGetCurrentContendedMonitor "Can the VM get the current contended monitor of a thread?")
+            (boolean canGetMonitorInfo "Can the VM get the monitor information for a given object? ")
+            (boolean canRedefineClasses "Can the VM redefine classes?")
+            (boolean canAddMethod "Can the VM add methods when redefining classes?")
+            (boolean canUnrestrictedlyRedefineClasses "Can the VM redefine classes in ways that are normally restricted?")
+            (boolean canPopFrames "Can the VM pop stack frames?")
+            (boolean canUseInstanceFilters "Can the VM filter events by specific object?")
+            (boolean canGetSourceDebugExtension "Can the VM get the source debug extension?")
+            (boolean canRequestVMDeathEvent "Can the VM request VM death events?")
+            (boolean canSetDefaultStratum "Can the VM set a default stratum?")
+            (boolean canGetInstanceInfo "Can the VM return instances, counts of instances of classes and referring objects?")
+            (boolean canRequestMonitorEvents "Can the VM request monitor events?")
+            (boolean canGetMonitorFrameInfo "Can the VM get monitors with frame depth info?")
+            (boolean canUseSourceNameFilters "Can the VM filter class prepare events by source name?")
+            (boolean canGetConstantPool "Can the VM return the constant pool information?")
+            (boolean canForceEarlyReturn "Can the VM force early return from a method?")
+            (boolean reserved22 "Reserved for future capability")
+            (boolean reserved23 "Reserved for future capability")
+            (boolean reserved24 "Reserved for future capability")
+            (boolean reserved25 "Reserved for future capability")
+            (boolean reserved26 "Reserved for future capability")
+            (boolean reserved27 "Reserved for future capability")
+            (boolean reserved28 "Reserved for future capability")
+            (boolean reserved29 "Reserved for future capability")
+            (boolean reserved30 "Reserved for future capability")
+            (boolean reserved31 "Reserved for future capability")
+            (boolean reserved32 "Reserved for future capability")
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command RedefineClasses=18
+        "Installs new class definitions. "
+        (Out
+            (Repeat classes "Number of reference types that follow."
+                (Group ClassDef
+                    (referenceType refType "The reference type.")
+                    (Repeat classfile "Number of bytes defining class (below)"
+                        (byte classbyte "byte in JVM class file format.")
+                    )
+                )
+            )
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "One of the refTypes is not the ID of a reference type.")
+            (Error INVALID_OBJECT "One of the refTypes is not a known ID.")
+            (Error UNSUPPORTED_VERSION)
+            (Error INVALID_CLASS_FORMAT)
+            (Error CIRCULAR_CLASS_DEFINITION)
+            (Error FAILS_VERIFICATION)
+            (Error NAMES_DONT_MATCH)
+            (Error NOT_IMPLEMENTED "No aspect of this functionality is implemented (CapabilitiesNew.canRedefineClasses is false)")
+            (Error ADD_METHOD_NOT_IMPLEMENTED)
+            (Error SCHEMA_CHANGE_NOT_IMPLEMENTED)
+            (Error HIERARCHY_CHANGE_NOT_IMPLEMENTED)
+            (Error DELETE_METHOD_NOT_IMPLEMENTED)
+            (Error CLASS_MODIFIERS_CHANGE_NOT_IMPLEMENTED)
+            (Error METHOD_MODIFIERS_CHANGE_NOT_IMPLEMENTED)
+            (Error CLASS_ATTRIBUTE_CHANGE_NOT_IMPLEMENTED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command SetDefaultStratum=19
+        "Set the default stratum. "
+        (Out
+            (string stratumID "default stratum, or empty string to use reference type default.")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command AllClassesWithGeneric=20
+        "Returns reference types for all classes currently loaded by the target VM, "
+        "with their generic signatures. "
+        (Out
+        )
+        (Reply
+            (Repeat classes "Number of reference types that follow."
+                (Group ClassInfo
+                    (byte refTypeTag "Kind of following reference type. ")
+                    (referenceType typeID "Loaded reference type")
+                    (string signature "The JNI signature of the loaded reference type.")
+                    (string genericSignature "The generic signature of the loaded reference type "
+                                             "or an empty string if there is none.")
+                    (int status "The current class status. ")
+                )
+            )
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command InstanceCounts=21
+        "Returns the number of instances of each reference type in the input list. "
+        (Out
+            (Repeat refTypesCount "Number of reference types that follow.    Must be non-negative."
+                (referenceType refType "A reference type ID.")
+            )
+        )
+        (Reply
+            (Repeat counts "The number of counts that follow."
+                (long instanceCount "The number of instances for the corresponding "
+                                    "reference type in 'Out Data'.")
+            )
+        )
+        (ErrorSet
+            (Error ILLEGAL_ARGUMENT "refTypesCount is less than zero.")
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command AllModules=22
+        "Returns all modules in the target VM."
+        "<p>Since JDWP version 9."
+        (Out
+        )
+        (Reply
+            (Repeat modules "The number of the modules that follow."
+                (moduleID module "One of the modules.")
+            )
+        )
+        (ErrorSet
+            (Error NOT_IMPLEMENTED "The JDWP target VM does not support modules.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ReferenceType=2
+    (Command Signature=1
+        "Returns the type signature of a reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (string signature "The JNI signature for the reference type.")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command ClassLoader=2
+        "Returns the instance of java.lang.ClassLoader which loaded a given reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (classLoaderObject classLoader "The class loader for the reference type. ")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Modifiers=3
+        "Returns the modifiers (also known as access flags) for a reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (int modBits "Modifier bits as defined in Chapter 4 of The Java Virtual Machine Specification")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error CLASS_NOT_PREPARED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command Fields=4
+        "Returns information for each field in a reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (Repeat declared "Number of declared fields."
+                (Group FieldInfo
+                    (field fieldID "Field ID.")
+                    (string name "Name of field.")
+                    (string signature "JNI Signature of field.")
+                    (int modBits "The modifier bit flags (also known as access flags).")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error CLASS_NOT_PREPARED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command Methods=5
+        "Returns information for each method in a reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (Repeat declared "Number of declared methods."
+                (Group MethodInfo
+                    (method methodID "Method ID.")
+                    (string name "Name of method.")
+                    (string signature "JNI signature of method.")
+                    (int modBits "The modifier bit flags (also known as access flags).")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error CLASS_NOT_PREPARED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command GetValues=6
+        "Returns the value of one or more static fields of the reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+            (Repeat fields "The number of values to get"
+                (Group Field
+                    (field fieldID "A field to get")
+                )
+            )
+        )
+        (Reply
+            (Repeat values "The number of values returned, always equal to fields, "
+                           "the number of values to get."
+                (value value "The field value")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error INVALID_FIELDID)
+            (Error VM_DEAD)
+        )
+    )
+    (Command SourceFile=7
+        "Returns the name of source file in which a reference type was declared. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (string sourceFile "The source file name. No path information for the file is included")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error ABSENT_INFORMATION "The source file attribute is absent.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command NestedTypes=8
+        "Returns the classes and interfaces directly nested within this type."
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (Repeat classes "The number of nested classes and interfaces"
+                (Group TypeInfo
+                    (byte refTypeTag "Kind of following reference type. ")
+                    (referenceType typeID "The nested class or interface ID.")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Status=9
+        "Returns the current status of the reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (int status "Status bits: See JDWP.ClassStatus")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Interfaces=10
+        "Returns the interfaces declared as implemented by this class. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (Repeat interfaces "The number of implemented interfaces"
+                (interfaceType interfaceType "implemented interface.")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error CLASS_NOT_PREPARED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command ClassObject=11
+        "Returns the class object corresponding to this type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (classObject classObject "class object.")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command SourceDebugExtension=12
+        "Returns the value of the SourceDebugExtension attribute. "
+        (Out
+            (referenceType refType "The type.")
+        )
+        (Reply
+            (string extension "extension attribute")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error ABSENT_INFORMATION "If the extension is not specified.")
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command SignatureWithGeneric=13
+        "Returns the JNI signature of a reference type along with the generic signature if there is one. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (string signature "The JNI signature for the reference type.")
+            (string genericSignature "The generic signature for the reference type or an empty "
+                                     "string if there is none.")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command FieldsWithGeneric=14
+        "Returns information, including the generic signature if any, for each field in a reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (Repeat declared "Number of declared fields."
+                (Group FieldInfo
+                    (field fieldID "Field ID.")
+                    (string name "The name of the field.")
+                    (string signature "The JNI signature of the field.")
+                    (string genericSignature "The generic signature of the field, or an empty string if there is none.")
+                    (int modBits "The modifier bit flags (also known as access flags).")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error CLASS_NOT_PREPARED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command MethodsWithGeneric=15
+        "Returns information, including the generic signature if any, for each method in a reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+        )
+        (Reply
+            (Repeat declared "Number of declared methods."
+                (Group MethodInfo
+                    (method methodID "Method ID.")
+                    (string name "The name of the method.")
+                    (string signature "The JNI signature of the method.")
+                    (string genericSignature "The generic signature of the method, or an empty string if there is none.")
+                    (int modBits "The modifier bit flags (also known as access flags).")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error CLASS_NOT_PREPARED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command Instances=16
+        "Returns instances of this reference type. "
+        (Out
+            (referenceType refType "The reference type ID.")
+            (int maxInstances "Maximum number of instances to return.    Must be non-negative. "
+                              "If zero, all instances are returned.")
+        )
+        (Reply
+            (Repeat instances "The number of instances that follow."
+                (tagged-object instance "An instance of this reference type.")
+            )
+        )
+        (ErrorSet
+            (Error ILLEGAL_ARGUMENT "maxInstances is less than zero.")
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command ClassFileVersion=17
+        "Returns the class file major and minor version numbers, as defined in the class "
+        "file format of the Java Virtual Machine specification. "
+        (Out
+            (referenceType refType "The class.")
+        )
+        (Reply
+            (int majorVersion "Major version number")
+            (int minorVersion "Minor version number")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error ABSENT_INFORMATION "The class file version information is absent for primitive and array types.")
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command ConstantPool=18
+        "Return the raw bytes of the constant pool in the format of the constant_pool item "
+        "of the Class File Format in The Java Virtual Machine Specification. "
+        (Out
+            (referenceType refType "The class.")
+        )
+        (Reply
+            (int count "Total number of constant pool entries plus one. ")
+            (Repeat bytes "Number of bytes that follow."
+                (byte cpbytes "Raw bytes of constant pool")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error ABSENT_INFORMATION "The class file version information is absent for primitive and array types.")
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Module=19
+        "Returns the module that this reference type belongs to."
+        "<p>Since JDWP version 9."
+        (Out
+            (referenceType refType "The reference type.")
+        )
+        (Reply
+            (moduleID module "The module this reference type belongs to.")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error NOT_IMPLEMENTED "The JDWP target VM does not support modules.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ClassType=3
+    (Command Superclass=1
+        "Returns the immediate superclass of a class."
+        (Out
+            (classType clazz "The class type ID.")
+        )
+        (Reply
+            (classType superclass "The superclass (null if the class ID for java.lang.Object is specified).")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "clazz is not the ID of a class.")
+            (Error INVALID_OBJECT "clazz is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command SetValues=2
+        "Sets the value of one or more static fields. "
+        (Out
+            (classType clazz "The class type ID.")
+            (Repeat values "The number of fields to set."
+                (Group FieldValue "A Field/Value pair."
+                    (field fieldID "Field to set.")
+                    (untagged-value value "Value to put in the field.")
+                )
+            )
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "clazz is not the ID of a class.")
+            (Error CLASS_NOT_PREPARED)
+            (Error INVALID_OBJECT "clazz is not a known ID or a value of an object parameter is not a known ID.")
+            (Error INVALID_FIELDID)
+            (Error VM_DEAD)
+        )
+    )
+    (Command InvokeMethod=3
+        "Invokes a static method. "
+        (Out
+            (classType clazz "The class type ID.")
+            (threadObject thread "The thread in which to invoke.")
+            (method methodID "The method to invoke.")
+            (Repeat arguments "The number of arguments."
+                (value arg "The argument value.")
+            )
+            (int options "Invocation options")
+        )
+        (Reply
+            (value returnValue "The returned value.")
+            (tagged-object exception "The thrown exception.")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "clazz is not the ID of a class.")
+            (Error INVALID_OBJECT "clazz is not a known ID or a value of an object parameter is not a known ID.")
+            (Error INVALID_METHODID "methodID is not the ID of a static method in this class type or one of its superclasses.")
+            (Error INVALID_THREAD)
+            (Error THREAD_NOT_SUSPENDED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command NewInstance=4
+        "Creates a new object of this type, invoking the specified constructor. "
+        (Out
+            (classType clazz "The class type ID.")
+            (threadObject thread "The thread in which to invoke the constructor.")
+            (method methodID "The constructor to invoke.")
+            (Repeat arguments "The number of arguments."
+                (value arg "The argument value.")
+            )
+            (int options "Constructor invocation options")
+        )
+        (Reply
+            (tagged-object newObject "The newly created object, or null if the constructor threw an exception.")
+            (tagged-object exception "The thrown exception, if any; otherwise, null.")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "clazz is not the ID of a class.")
+            (Error INVALID_OBJECT "clazz is not a known ID or a value of an object parameter is not a known ID.")
+            (Error INVALID_METHODID "methodID is not the ID of a constructor.")
+            (Error INVALID_THREAD)
+            (Error THREAD_NOT_SUSPENDED)
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ArrayType=4
+    (Command NewInstance=1
+        "Creates a new array object of this type with a given length."
+        (Out
+            (arrayType arrType "The array type of the new instance.")
+            (int length "The length of the array.")
+        )
+        (Reply
+            (tagged-object newArray "The newly created array object.")
+        )
+        (ErrorSet
+            (Error INVALID_ARRAY "The array is invalid.")
+            (Error INVALID_OBJECT "arrType is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet InterfaceType=5
+    (Command InvokeMethod=1
+        "Invokes a static method. "
+        "<p>Since JDWP version 1.8"
+        (Out
+            (interfaceType clazz "The interface type ID.")
+            (threadObject thread "The thread in which to invoke.")
+            (method methodID "The method to invoke.")
+            (Repeat arguments "The number of arguments."
+                (value arg "The argument value.")
+            )
+            (int options "Invocation options")
+        )
+        (Reply
+            (value returnValue "The returned value.")
+            (tagged-object exception "The thrown exception.")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "clazz is not the ID of an interface.")
+            (Error INVALID_OBJECT "clazz is not a known ID or a value of an object parameter is not a known ID.")
+            (Error INVALID_METHODID "methodID is not the ID of a static method in this interface type or is the ID of a static initializer.")
+            (Error INVALID_THREAD)
+            (Error THREAD_NOT_SUSPENDED)
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet Method=6
+    (Command LineTable=1
+        "Returns line number information for the method, if present. "
+        (Out
+            (referenceType refType "The class.")
+            (method methodID "The method.")
+        )
+        (Reply
+            (long start "Lowest valid code index for the method, >=0, or -1 if the method is native ")
+            (long end "Highest valid code index for the method, >=0, or -1 if the method is native")
+            (Repeat lines "The number of entries in the line table for this method."
+                (Group LineInfo
+                    (long lineCodeIndex "Initial code index of the line, start <= lineCodeIndex < end")
+                    (int lineNumber "Line number.")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error INVALID_METHODID "methodID is not the ID of a method.")
+            (Error ABSENT_INFORMATION "there is no line number information for this method.")
+            (Error NATIVE_METHOD)
+            (Error VM_DEAD)
+        )
+    )
+    (Command VariableTable=2
+        "Returns variable information for the method. "
+        (Out
+            (referenceType refType "The class.")
+            (method methodID "The method.")
+        )
+        (Reply
+            (int argCnt "The number of words in the frame used by arguments. ")
+            (Repeat slots "The number of variables."
+                (Group SlotInfo
+                    (long codeIndex "First code index at which the variable is visible (unsigned). ")
+                    (string name "The variable's name.")
+                    (string signature "The variable type's JNI signature.")
+                    (int length "Unsigned value used in conjunction with codeIndex. ")
+                    (int slot "The local variable's index in its frame")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error INVALID_METHODID "methodID is not the ID of a method.")
+            (Error ABSENT_INFORMATION "there is no variable information for this method.")
+            (Error NATIVE_METHOD)
+            (Error VM_DEAD)
+        )
+    )
+    (Command Bytecodes=3
+        "Retrieve the method's bytecodes as defined in The Java Virtual Machine Specification. "
+        (Out
+            (referenceType refType "The class.")
+            (method methodID "The method.")
+        )
+        (Reply
+            (Repeat bytes "Number of bytes that follow"
+                (byte bytecode "A Java bytecode.")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error INVALID_METHODID "methodID is not the ID of a method.")
+            (Error NOT_IMPLEMENTED "If the target virtual machine does not support the retrieval of bytecodes.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command IsObsolete=4
+        "Determine if this method is obsolete. "
+        (Out
+            (referenceType refType "The class.")
+            (method methodID "The method ID.")
+        )
+        (Reply
+            (boolean isObsolete "true if this method has been replaced"
+                                "by a non-equivalent method using"
+                                "the RedefineClasses command.")
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error INVALID_METHODID "methodID is not the ID of a method.")
+            (Error NOT_IMPLEMENTED "If the target virtual machine does not support this query.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command VariableTableWithGeneric=5
+        "Returns variable information for the method, including generic signatures for the variables. "
+        (Out
+            (referenceType refType "The class.")
+            (method methodID "The method.")
+        )
+        (Reply
+            (int argCnt "The number of words in the frame used by arguments. ")
+            (Repeat slots "The number of variables."
+                (Group SlotInfo
+                    (long codeIndex "First code index at which the variable is visible (unsigned). ")
+                    (string name "The variable's name.")
+                    (string signature "The variable type's JNI signature.")
+                    (string genericSignature "The variable type's generic signature or an empty string if there is none.")
+                    (int length "Unsigned value used in conjunction with codeIndex. ")
+                    (int slot "The local variable's index in its frame")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS "refType is not the ID of a reference type.")
+            (Error INVALID_OBJECT "refType is not a known ID.")
+            (Error INVALID_METHODID "methodID is not the ID of a method.")
+            (Error ABSENT_INFORMATION "there is no variable information for this method.")
+            (Error NATIVE_METHOD)
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet Field=8
+)
+
+(CommandSet ObjectReference=9
+    (Command ReferenceType=1
+        "Returns the runtime type of the object. "
+        (Out
+            (object object "The object ID")
+        )
+        (Reply
+            (byte refTypeTag "Kind of following reference type. ")
+            (referenceType typeID "The runtime reference type.")
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT)
+            (Error VM_DEAD)
+        )
+    )
+    (Command GetValues=2
+        "Returns the value of one or more instance fields. "
+        (Out
+            (object object "The object ID")
+            (Repeat fields "The number of values to get"
+                (Group Field
+                    (field fieldID "Field to get.")
+                )
+            )
+        )
+        (Reply
+            (Repeat values "The number of values returned, always equal to 'fields', "
+                           "the number of values to get."
+                (value value "The field value")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT)
+            (Error INVALID_FIELDID)
+            (Error VM_DEAD)
+        )
+    )
+    (Command SetValues=3
+        "Sets the value of one or more instance fields. "
+        (Out
+            (object object "The object ID")
+            (Repeat values "The number of fields to set."
+                (Group FieldValue "A Field/Value pair."
+                    (field fieldID "Field to set.")
+                    (untagged-value value "Value to put in the field.")
+                )
+            )
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT)
+            (Error INVALID_FIELDID)
+            (Error VM_DEAD)
+        )
+    )
+    (Command MonitorInfo=5
+        "Returns monitor information for an object. "
+        (Out
+            (object object "The object ID")
+        )
+        (Reply
+            (threadObject owner "The monitor owner, or null if it is not currently owned.")
+            (int entryCount "The number of times the monitor has been entered.")
+            (Repeat waiters "The number of threads that are waiting for the monitor "
+                            "0 if there is no current owner"
+                (threadObject thread "A thread waiting for this monitor.")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT)
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command InvokeMethod=6
+        "Invokes a instance method. "
+        (Out
+            (object object "The object ID")
+            (threadObject thread "The thread in which to invoke.")
+            (classType clazz "The class type.")
+            (method methodID "The method to invoke.")
+            (Repeat arguments "The number of arguments."
+                (value arg "The argument value.")
+            )
+            (int options "Invocation options")
+        )
+        (Reply
+            (value returnValue "The returned value, or null if an exception is thrown.")
+            (tagged-object exception "The thrown exception, if any.")
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT)
+            (Error INVALID_CLASS "clazz is not the ID of a reference type.")
+            (Error INVALID_METHODID "methodID is not the ID of an instance method in this object's type or one of its superclasses, superinterfaces, or implemented interfaces.")
+            (Error INVALID_THREAD)
+            (Error THREAD_NOT_SUSPENDED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command DisableCollection=7
+        "Prevents garbage collection for the given object. "
+        (Out
+            (object object "The object ID")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT)
+            (Error VM_DEAD)
+        )
+    )
+    (Command EnableCollection=8
+        "Permits garbage collection for this object. "
+        (Out
+            (object object "The object ID")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+    (Command IsCollected=9
+        "Determines whether an object has been garbage collected in the target VM. "
+        (Out
+            (object object "The object ID")
+        )
+        (Reply
+            (boolean isCollected "true if the object has been collected; false otherwise")
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT)
+            (Error VM_DEAD)
+        )
+    )
+    (Command ReferringObjects=10
+        "Returns objects that directly reference this object. "
+        (Out
+            (object object "The object ID")
+            (int maxReferrers "Maximum number of referring objects to return. "
+                              "Must be non-negative. If zero, all referring objects are returned.")
+        )
+        (Reply
+            (Repeat referringObjects "The number of objects that follow."
+                (tagged-object instance "An object that references this object.")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT)
+            (Error ILLEGAL_ARGUMENT "maxReferrers is less than zero.")
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet StringReference=10
+    (Command Value=1
+        "Returns the characters contained in the string. "
+        (Out
+            (object stringObject "The String object ID. ")
+        )
+        (Reply
+            (string stringValue "UTF-8 representation of the string value.")
+        )
+        (ErrorSet
+            (Error INVALID_STRING "The string is invalid.")
+            (Error INVALID_OBJECT)
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ThreadReference=11
+    (Command Name=1
+        "Returns the thread name. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+            (string threadName "The thread name.")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Suspend=2
+        "Suspends the thread. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Resume=3
+        "Resumes the execution of a given thread. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Status=4
+        "Returns the current status of a thread. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+            (int threadStatus "One of the thread status codes See JDWP.ThreadStatus")
+            (int suspendStatus "One of the suspend status codes See JDWP.SuspendStatus")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command ThreadGroup=5
+        "Returns the thread group that contains a given thread. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+            (threadGroupObject group "The thread group of this thread. ")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Frames=6
+        "Returns the current call stack of a suspended thread. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+            (int startFrame "The index of the first frame to retrieve.")
+            (int length "The count of frames to retrieve (-1 means all remaining). ")
+        )
+        (Reply
+            (Repeat frames "The number of frames retreived"
+                (Group FrameInfo
+                    (frame frameID "The ID of this frame. ")
+                    (location location "The current location of this frame")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error THREAD_NOT_SUSPENDED)
+            (Error INVALID_INDEX)
+            (Error INVALID_LENGTH)
+            (Error VM_DEAD)
+        )
+    )
+    (Command FrameCount=7
+        "Returns the count of frames on this thread's stack. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+            (int frameCount "The count of frames on this thread's stack. ")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error THREAD_NOT_SUSPENDED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command OwnedMonitors=8
+        "Returns the objects whose monitors have been entered by this thread. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+            (Repeat owned "The number of owned monitors"
+                (tagged-object monitor "An owned monitor")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error THREAD_NOT_SUSPENDED)
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command CurrentContendedMonitor=9
+        "Returns the object, if any, for which this thread is waiting. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+            (tagged-object monitor "The contended monitor, or null if there is no current contended monitor. ")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error THREAD_NOT_SUSPENDED)
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Stop=10
+        "Stops the thread with an asynchronous exception. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+            (object throwable "Asynchronous exception. This object must be an instance of "
+                              "java.lang.Throwable or a subclass")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "If thread is not a known ID or the asynchronous exception has been garbage collected.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Interrupt=11
+        "Interrupt the thread, as if done by java.lang.Thread.interrupt"
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command SuspendCount=12
+        "Get the suspend count for this thread. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+            (int suspendCount "The number of outstanding suspends of this thread. ")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command OwnedMonitorsStackDepthInfo=13
+        "Returns monitor objects owned by the thread, along with stack depth at which the monitor was acquired. "
+        "<p>Since JDWP version 1.6. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+        )
+        (Reply
+            (Repeat owned "The number of owned monitors"
+                (Group monitor
+                    (tagged-object monitor "An owned monitor")
+                    (int stack_depth "Stack depth location where monitor was acquired")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "thread is not a known ID.")
+            (Error THREAD_NOT_SUSPENDED)
+            (Error NOT_IMPLEMENTED "The functionality is not implemented in this virtual machine.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command ForceEarlyReturn=14
+        "Force a method to return before it reaches a return statement. "
+        "<p>Since JDWP version 1.6. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+            (value value "The value to return. ")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT "Thread or value is not a known ID.")
+            (Error THREAD_NOT_SUSPENDED)
+            (Error THREAD_NOT_ALIVE)
+            (Error OPAQUE_FRAME)
+            (Error NO_MORE_FRAMES)
+            (Error NOT_IMPLEMENTED)
+            (Error TYPE_MISMATCH "Value is not an appropriate type for the return value of the method.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ThreadGroupReference=12
+    (Command Name=1
+        "Returns the thread group name. "
+        (Out
+            (threadGroupObject group "The thread group object ID. ")
+        )
+        (Reply
+            (string groupName "The thread group's name.")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD_GROUP)
+            (Error INVALID_OBJECT "group is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Parent=2
+        "Returns the thread group, if any, which contains a given thread group. "
+        (Out
+            (threadGroupObject group "The thread group object ID. ")
+        )
+        (Reply
+            (threadGroupObject parentGroup "The parent thread group object, or null if the given thread group is a top-level thread group")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD_GROUP)
+            (Error INVALID_OBJECT "group is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command Children=3
+        "Returns the live threads and active thread groups directly contained in this thread group. "
+        (Out
+            (threadGroupObject group "The thread group object ID. ")
+        )
+        (Reply
+            (Repeat childThreads "The number of live child threads. "
+                (threadObject childThread "A direct child thread ID. ")
+            )
+            (Repeat childGroups "The number of active child thread groups. "
+                (threadGroupObject childGroup "A direct child thread group ID. ")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_THREAD_GROUP)
+            (Error INVALID_OBJECT "group is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ArrayReference=13
+    (Command Length=1
+        "Returns the number of components in a given array. "
+        (Out
+            (arrayObject arrayObject "The array object ID. ")
+        )
+        (Reply
+            (int arrayLength "The length of the array.")
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT "arrayObject is not a known ID.")
+            (Error INVALID_ARRAY)
+            (Error VM_DEAD)
+        )
+    )
+    (Command GetValues=2
+        "Returns a range of array components. "
+        (Out
+            (arrayObject arrayObject "The array object ID. ")
+            (int firstIndex "The first index to retrieve.")
+            (int length "The number of components to retrieve.")
+        )
+        (Reply
+            (arrayregion values "The retrieved values. If the values "
+                                "are objects, they are tagged-values; "
+                                "otherwise, they are untagged-values")
+        )
+        (ErrorSet
+            (Error INVALID_LENGTH "If index is beyond the end of this array or if length is negative.")
+            (Error INVALID_OBJECT "arrayObject is not a known ID.")
+            (Error INVALID_ARRAY)
+            (Error VM_DEAD)
+        )
+    )
+    (Command SetValues=3
+        "Sets a range of array components. "
+        (Out
+            (arrayObject arrayObject "The array object ID. ")
+            (int firstIndex "The first index to set.")
+            (Repeat values "The number of values to set. "
+                (untagged-value value "A value to set. ")
+            )
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_LENGTH "If index is beyond the end of this array.")
+            (Error INVALID_OBJECT "arrayObject is not a known ID.")
+            (Error INVALID_ARRAY)
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ClassLoaderReference=14
+    (Command VisibleClasses=1
+        "Returns a list of all classes which this class loader has been requested to load by the Java Virtual Machine. "
+        (Out
+            (classLoaderObject classLoaderObject "The class loader object ID. ")
+        )
+        (Reply
+            (Repeat classes "The number of visible classes. "
+                (Group ClassInfo
+                    (byte refTypeTag "Kind of following reference type. ")
+                    (referenceType typeID "A class visible to this class loader.")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS_LOADER)
+            (Error INVALID_OBJECT "classLoaderObject is not a known ID.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet EventRequest=15
+    (Command Set=1
+        "Set an event request. When the event described by this request occurs, an event is sent "
+        "from the target VM. "
+        (Out
+            (byte eventKind "Event kind to request. See JDWP.EventKind for a complete list of events that can be requested.")
+            (byte suspendPolicy "What threads are suspended when this event occurs? ")
+            (Repeat modifiers "Constraints used to control the number of generated events."
+                (Select modKind "Modifier kind"
+                    (Alt Count=1 "Limit the requested event to be reported at most once after a "
+                                 "given number of occurrences. "
+                        (int count "Count before event. One for one-off.")
+                    )
+                    (Alt Conditional=2 "Conditional on expression"
+                        (int exprID "For the future")
+                    )
+                    (Alt ThreadOnly=3 "Restricts reported events to those in the given thread. "
+                        (threadObject thread "Required thread")
+                    )
+                    (Alt ClassOnly=4 "For class prepare events, restricts the events generated by this request "
+                                     "to be the preparation of the given reference type and any subtypes. "
+                        (referenceType clazz "Required class")
+                    )
+                    (Alt ClassMatch=5 "Restricts reported events to those for classes whose name matches the "
+                                      "given restricted regular expression. "
+                        (string classPattern "Required class pattern. ")
+                    )
+                    (Alt ClassExclude=6 "Restricts reported events to those for classes whose name does not match "
+                                        "the given restricted regular expression. "
+                        (string classPattern "Disallowed class pattern. ")
+                    )
+                    (Alt LocationOnly=7 "Restricts reported events to those that occur at the given location. "
+                        (location loc "Required location")
+                    )
+                    (Alt ExceptionOnly=8 "Restricts reported exceptions by their class and whether they are caught or uncaught. "
+                        (referenceType exceptionOrNull "Exception to report. Null (0) means report exceptions of all types. ")
+                        (boolean caught "Report caught exceptions")
+                        (boolean uncaught "Report uncaught exceptions. ")
+                    )
+                    (Alt FieldOnly=9 "Restricts reported events to those that occur for a given field. "
+                        (referenceType declaring "Type in which field is declared.")
+                        (field fieldID "Required field")
+                    )
+                    (Alt Step=10 "Restricts reported step events to those which satisfy depth and size constraints. "
+                        (threadObject thread "Thread to step")
+                        (int size "size of each step. See JDWP.StepSize")
+                        (int depth "relative call stack limit. See JDWP.StepDepth")
+                    )
+                    (Alt InstanceOnly=11 "Restricts reported events to those whose active 'this' object is the given object. "
+                        (object instance "Required 'this' object")
+                    )
+                    (Alt SourceNameMatch=12 "Restricts reported class prepare events to those for reference types "
+                                            "which have a source name which matches the given restricted regular expression. "
+                        (string sourceNamePattern "Required source name pattern. ")
+                    )
+                )
+            )
+        )
+        (Reply
+            (int requestID "ID of created request")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error THREAD_NOT_SUSPENDED)
+            (Error INVALID_OBJECT)
+            (Error INVALID_CLASS)
+            (Error INVALID_STRING)
+            (Error INVALID_COUNT)
+            (Error INVALID_FIELDID)
+            (Error INVALID_METHODID)
+            (Error INVALID_LOCATION)
+            (Error INVALID_EVENT_TYPE)
+            (Error NOT_IMPLEMENTED)
+            (Error VM_DEAD)
+        )
+    )
+    (Command Clear=2
+        "Clear an event request. "
+        (Out
+            (byte eventKind "Event kind to clear")
+            (int requestID "ID of request to clear")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_EVENT_TYPE)
+            (Error VM_DEAD)
+        )
+    )
+    (Command ClearAllBreakpoints=3
+        "Removes all set breakpoints, a no-op if there are no breakpoints set."
+        (Out
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet StackFrame=16
+    (Command GetValues=1
+        "Returns the value of one or more local variables in a given frame. "
+        (Out
+            (threadObject thread "The frame's thread. ")
+            (frame frame "The frame ID. ")
+            (Repeat slots "The number of values to get. "
+                (Group SlotInfo
+                    (int slot "The local variable's index in the frame. ")
+                    (byte sigbyte "A tag identifying the type of the variable ")
+                )
+            )
+        )
+        (Reply
+            (Repeat values "The number of values retrieved, always equal to slots, "
+                           "the number of values to get."
+                (value slotValue "The value of the local variable. ")
+            )
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT)
+            (Error INVALID_FRAMEID)
+            (Error INVALID_SLOT)
+            (Error VM_DEAD)
+        )
+    )
+    (Command SetValues=2
+        "Sets the value of one or more local variables. "
+        (Out
+            (threadObject thread "The frame's thread. ")
+            (frame frame "The frame ID. ")
+            (Repeat slotValues "The number of values to set. "
+                (Group SlotInfo
+                    (int slot "The slot ID. ")
+                    (value slotValue "The value to set. ")
+                )
+            )
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT)
+            (Error INVALID_FRAMEID)
+            (Error VM_DEAD)
+        )
+    )
+    (Command ThisObject=3
+        "Returns the value of the 'this' reference for this frame. "
+        (Out
+            (threadObject thread "The frame's thread. ")
+            (frame frame "The frame ID. ")
+        )
+        (Reply
+            (tagged-object objectThis "The 'this' object for this frame. ")
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT)
+            (Error INVALID_FRAMEID)
+            (Error VM_DEAD)
+        )
+    )
+    (Command PopFrames=4
+        "Pop the top-most stack frames of the thread stack, up to, and including 'frame'. "
+        (Out
+            (threadObject thread "The thread object ID. ")
+            (frame frame "The frame ID. ")
+        )
+        (Reply
+        )
+        (ErrorSet
+            (Error INVALID_THREAD)
+            (Error INVALID_OBJECT)
+            (Error INVALID_FRAMEID)
+            (Error THREAD_NOT_SUSPENDED)
+            (Error NO_MORE_FRAMES)
+            (Error NOT_IMPLEMENTED)
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ClassObjectReference=17
+    (Command ReflectedType=1
+        "Returns the reference type reflected by this class object."
+        (Out
+            (classObject classObject "The class object. ")
+        )
+        (Reply
+            (byte refTypeTag "Kind of following reference type. ")
+            (referenceType typeID "reflected reference type")
+        )
+        (ErrorSet
+            (Error INVALID_OBJECT "If this reference type has been unloaded and garbage collected.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet ModuleReference=18
+    (Command Name=1
+        "Returns the name of this module."
+        "<p>Since JDWP version 9."
+        (Out
+            (moduleID module "This module.")
+        )
+        (Reply
+            (string name "The module's name.")
+        )
+        (ErrorSet
+            (Error INVALID_MODULE)
+            (Error NOT_IMPLEMENTED "The JDWP target VM does not support modules.")
+            (Error VM_DEAD)
+        )
+    )
+    (Command ClassLoader=2
+        "Returns the class loader of this module."
+        "<p>Since JDWP version 9."
+        (Out
+            (moduleID module "This module.")
+        )
+        (Reply
+            (classLoaderObject classLoader "The module's class loader.")
+        )
+        (ErrorSet
+            (Error INVALID_MODULE)
+            (Error NOT_IMPLEMENTED "The JDWP target VM does not support modules.")
+            (Error VM_DEAD)
+        )
+    )
+)
+
+(CommandSet Event=64
+    (Command Composite=100
+        "Several events may occur at a given time in the target VM, and are sent together "
+        "in one composite command. "
+        (Event "Generated event"
+            (byte suspendPolicy "Which threads where suspended by this composite event?")
+            (Repeat events "Events in set."
+                (Select eventKind "Event kind selector"
+                    (Alt VMStart=JDWP.EventKind.VM_START
+                        "Notification of initialization of a target VM. "
+                        (int requestID "Request that generated event (or 0 if this event is automatically generated.")
+                        (threadObject thread "Initial thread")
+                    )
+                    (Alt SingleStep=JDWP.EventKind.SINGLE_STEP
+                        "Notification of step completion in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Stepped thread")
+                        (location location "Location stepped to")
+                    )
+                    (Alt Breakpoint=JDWP.EventKind.BREAKPOINT
+                        "Notification of a breakpoint in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread which hit breakpoint")
+                        (location location "Location hit")
+                    )
+                    (Alt MethodEntry=JDWP.EventKind.METHOD_ENTRY
+                        "Notification of a method invocation in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread which entered method")
+                        (location location "The initial executable location in the method.")
+                    )
+                    (Alt MethodExit=JDWP.EventKind.METHOD_EXIT
+                        "Notification of a method return in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread which exited method")
+                        (location location "Location of exit")
+                    )
+                    (Alt MethodExitWithReturnValue=JDWP.EventKind.METHOD_EXIT_WITH_RETURN_VALUE
+                        "Notification of a method return in the target VM, with the value returned. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread which exited method")
+                        (location location "Location of exit")
+                        (value value "Value that will be returned by the method")
+                    )
+                    (Alt MonitorContendedEnter=JDWP.EventKind.MONITOR_CONTENDED_ENTER
+                        "Notification that a thread in the target VM is attempting to enter a "
+                        "monitor that is already acquired by another thread. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread which is trying to enter the monitor")
+                        (tagged-object object "Monitor object reference")
+                        (location location "Location of contended monitor enter")
+                    )
+                    (Alt MonitorContendedEntered=JDWP.EventKind.MONITOR_CONTENDED_ENTERED
+                        "Notification of a thread in the target VM is entering a monitor "
+                        "after waiting for it to be released by another thread. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread which entered monitor")
+                        (tagged-object object "Monitor object reference")
+                        (location location "Location of contended monitor enter")
+                    )
+                    (Alt MonitorWait=JDWP.EventKind.MONITOR_WAIT
+                        "Notification of a thread about to wait on a monitor object. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread which is about to wait")
+                        (tagged-object object "Monitor object reference")
+                        (location location "Location at which the wait will occur")
+                        (long timeout "Thread wait time in milliseconds")
+                    )
+                    (Alt MonitorWaited=JDWP.EventKind.MONITOR_WAITED
+                        "Notification that a thread in the target VM has finished waiting on a monitor object. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread which waited")
+                        (tagged-object object "Monitor object reference")
+                        (location location "Location at which the wait occured")
+                        (boolean timed_out "True if timed out")
+                    )
+                    (Alt Exception=JDWP.EventKind.EXCEPTION
+                        "Notification of an exception in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Thread with exception")
+                        (location location "Location of exception throw "
+                                           "(or first non-native location after throw if thrown from a native method)")
+                        (tagged-object exception "Thrown exception")
+                        (location catchLocation "Location of catch, or 0 if not caught. ")
+                    )
+                    (Alt ThreadStart=JDWP.EventKind.THREAD_START
+                        "Notification of a new running thread in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Started thread")
+                    )
+                    (Alt ThreadDeath=JDWP.EventKind.THREAD_DEATH
+                        "Notification of a completed thread in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Ending thread")
+                    )
+                    (Alt ClassPrepare=JDWP.EventKind.CLASS_PREPARE
+                        "Notification of a class prepare in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Preparing thread. ")
+                        (byte refTypeTag "Kind of reference type. See JDWP.TypeTag")
+                        (referenceType typeID "Type being prepared")
+                        (string signature "Type signature")
+                        (int status "Status of type. See JDWP.ClassStatus")
+                    )
+                    (Alt ClassUnload=JDWP.EventKind.CLASS_UNLOAD
+                        "Notification of a class unload in the target VM. "
+                        (int requestID "Request that generated event")
+                        (string signature "Type signature")
+                    )
+                    (Alt FieldAccess=JDWP.EventKind.FIELD_ACCESS
+                        "Notification of a field access in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Accessing thread")
+                        (location location "Location of access")
+                        (byte refTypeTag "Kind of reference type. See JDWP.TypeTag")
+                        (referenceType typeID "Type of field")
+                        (field fieldID "Field being accessed")
+                        (tagged-object object "Object being accessed (null=0 for statics")
+                    )
+                    (Alt FieldModification=JDWP.EventKind.FIELD_MODIFICATION
+                        "Notification of a field modification in the target VM. "
+                        (int requestID "Request that generated event")
+                        (threadObject thread "Modifying thread")
+                        (location location "Location of modify")
+                        (byte refTypeTag "Kind of reference type. See JDWP.TypeTag")
+                        (referenceType typeID "Type of field")
+                        (field fieldID "Field being modified")
+                        (tagged-object object "Object being modified (null=0 for statics")
+                        (value valueToBe "Value to be assigned")
+                    )
+                    (Alt VMDeath=JDWP.EventKind.VM_DEATH
+                        "Notification of target VM termination."
+                        (int requestID "Request that generated event")
+                    )
+                )
+            )
+        )
+    )
+)
+
+(ConstantSet Error
+    (Constant NONE                   =0   "No error has occurred.")
+    (Constant INVALID_THREAD         =10  "Passed thread is null, is not a valid thread or has exited.")
+    (Constant INVALID_THREAD_GROUP   =11  "Thread group invalid.")
+    (Constant INVALID_PRIORITY       =12  "Invalid priority.")
+    (Constant THREAD_NOT_SUSPENDED   =13  "If the specified thread has not been suspended by an event.")
+    (Constant THREAD_SUSPENDED       =14  "Thread already suspended.")
+    (Constant THREAD_NOT_ALIVE       =15  "Thread has not been started or is now dead.")
+    (Constant INVALID_OBJECT         =20  "If this reference type has been unloaded and garbage collected.")
+    (Constant INVALID_CLASS          =21  "Invalid class.")
+    (Constant CLASS_NOT_PREPARED     =22  "Class has been loaded but not yet prepared.")
+    (Constant INVALID_METHODID       =23  "Invalid method.")
+    (Constant INVALID_LOCATION       =24  "Invalid location.")
+    (Constant INVALID_FIELDID        =25  "Invalid field.")
+    (Constant INVALID_FRAMEID        =30  "Invalid jframeID.")
+    (Constant NO_MORE_FRAMES         =31  "There are no more Java or JNI frames on the call stack.")
+    (Constant OPAQUE_FRAME           =32  "Information about the frame is not available.")
+    (Constant NOT_CURRENT_FRAME      =33  "Operation can only be performed on current frame.")
+    (Constant TYPE_MISMATCH          =34  "The variable is not an appropriate type for the function used.")
+    (Constant INVALID_SLOT           =35  "Invalid slot.")
+    (Constant DUPLICATE              =40  "Item already set.")
+    (Constant NOT_FOUND              =41  "Desired element not found.")
+    (Constant INVALID_MODULE         =42  "Invalid module.")
+    (Constant INVALID_MONITOR        =50  "Invalid monitor.")
+    (Constant NOT_MONITOR_OWNER      =51  "This thread doesn't own the monitor.")
+    (Constant INTERRUPT              =52  "The call has been interrupted before completion.")
+    (Constant INVALID_CLASS_FORMAT   =60  "The virtual machine attempted to read a class "
+                                          "file and determined that the file is malformed "
+                                          "or otherwise cannot be interpreted as a class file.")
+    (Constant CIRCULAR_CLASS_DEFINITION
+                                     =61  "A circularity has been detected while "
+                                          "initializing a class.")
+    (Constant FAILS_VERIFICATION     =62  "The verifier detected that a class file, "
+                                          "though well formed, contained some sort of "
+                                          "internal inconsistency or security problem.")
+    (Constant ADD_METHOD_NOT_IMPLEMENTED
+                                     =63  "Adding methods has not been implemented.")
+    (Constant SCHEMA_CHANGE_NOT_IMPLEMENTED
+                                     =64  "Schema change has not been implemented.")
+    (Constant INVALID_TYPESTATE      =65  "The state of the thread has been modified, "
+                                          "and is now inconsistent.")
+    (Constant HIERARCHY_CHANGE_NOT_IMPLEMENTED
+                                     =66  "A direct superclass is different for the new class "
+                                          "version, or the set of directly implemented "
+                                          "interfaces is different "
+                                          "and canUnrestrictedlyRedefineClasses is false.")
+    (Constant DELETE_METHOD_NOT_IMPLEMENTED
+                                     =67  "The new class version does not declare a method "
+                                          "declared in the old class version "
+                                          "and canUnrestrictedlyRedefineClasses is false.")
+    (Constant UNSUPPORTED_VERSION    =68  "A class file has a version number not supported "
+                                          "by this VM.")
+    (Constant NAMES_DONT_MATCH       =69  "The class name defined in the new class file is "
+                                          "different from the name in the old class object.")
+    (Constant CLASS_MODIFIERS_CHANGE_NOT_IMPLEMENTED
+                                     =70  "The new class version has different modifiers and "
+                                          "and canUnrestrictedlyRedefineClasses is false.")
+    (Constant METHOD_MODIFIERS_CHANGE_NOT_IMPLEMENTED
+                                     =71  "A method in the new class version has "
+                                          "different modifiers "
+                                          "than its counterpart in the old class version and "
+                                          "and canUnrestrictedlyRedefineClasses is false.")
+    (Constant CLASS_ATTRIBUTE_CHANGE_NOT_IMPLEMENTED
+                                     =72  "The new class version has a different NestHost, "
+                                          "NestMembers, PermittedSubclasses, or Record class attribute "
+                                          "and canUnrestrictedlyRedefineClasses is false.")
+    (Constant NOT_IMPLEMENTED        =99  "The functionality is not implemented in "
+                                          "this virtual machine.")
+    (Constant NULL_POINTER           =100 "Invalid pointer.")
+    (Constant ABSENT_INFORMATION     =101 "Desired information is not available.")
+    (Constant INVALID_EVENT_TYPE     =102 "The specified event type id is not recognized.")
+    (Constant ILLEGAL_ARGUMENT       =103 "Illegal argument.")
+    (Constant OUT_OF_MEMORY          =110 "The function needed to allocate memory and "
+                                          "no more memory was available for allocation.")
+    (Constant ACCESS_DENIED          =111 "Debugging has not been enabled in this "
+                                          "virtual machine. JVMTI cannot be used.")
+    (Constant VM_DEAD                =112 "The virtual machine is not running.")
+    (Constant INTERNAL               =113 "An unexpected internal error has occurred.")
+    (Constant UNATTACHED_THREAD      =115 "The thread being used to call this function "
+                                          "is not attached to the virtual machine. "
+                                          "Calls must be made from attached threads.")
+    (Constant INVALID_TAG            =500 "object type id or class tag.")
+    (Constant ALREADY_INVOKING       =502 "Previous invoke not complete.")
+    (Constant INVALID_INDEX          =503 "Index is invalid.")
+    (Constant INVALID_LENGTH         =504 "The length is invalid.")
+    (Constant INVALID_STRING         =506 "The string is invalid.")
+    (Constant INVALID_CLASS_LOADER   =507 "The class loader is invalid.")
+    (Constant INVALID_ARRAY          =508 "The array is invalid.")
+    (Constant TRANSPORT_LOAD         =509 "Unable to load the transport.")
+    (Constant TRANSPORT_INIT         =510 "Unable to initialize the transport.")
+    (Constant NATIVE_METHOD          =511 "")
+    (Constant INVALID_COUNT          =512 "The count is invalid.")
+)
+
+(ConstantSet EventKind
+    (Constant SINGLE_STEP            =1   "")
+    (Constant BREAKPOINT             =2   "")
+    (Constant FRAME_POP              =3   "")
+    (Constant EXCEPTION              =4   "")
+    (Constant USER_DEFINED           =5   "")
+    (Constant THREAD_START           =6   "")
+    (Constant THREAD_DEATH           =7   "")
+    (Constant THREAD_END             =7   "obsolete - was used in jvmdi")
+    (Constant CLASS_PREPARE          =8   "")
+    (Constant CLASS_UNLOAD           =9   "")
+    (Constant CLASS_LOAD             =10  "")
+    (Constant FIELD_ACCESS           =20  "")
+    (Constant FIELD_MODIFICATION     =21  "")
+    (Constant EXCEPTION_CATCH        =30  "")
+    (Constant METHOD_ENTRY           =40  "")
+    (Constant METHOD_EXIT            =41  "")
+    (Constant METHOD_EXIT_WITH_RETURN_VALUE =42  "")
+    (Constant MONITOR_CONTENDED_ENTER          =43  "")
+    (Constant MONITOR_CONTENDED_ENTERED        =44  "")
+    (Constant MONITOR_WAIT           =45  "")
+    (Constant MONITOR_WAITED         =46  "")
+    (Constant VM_START               =90  "")
+    (Constant VM_INIT                =90  "obsolete - was used in jvmdi")
+    (Constant VM_DEATH               =99  "")
+    (Constant VM_DISCONNECTED        =100 "Never sent across JDWP")
+)
+
+(ConstantSet ThreadStatus
+    (Constant ZOMBIE                 =0   "")
+    (Constant RUNNING                =1   "")
+    (Constant SLEEPING               =2   "")
+    (Constant MONITOR                =3   "")
+    (Constant WAIT                   =4   "")
+)
+
+(ConstantSet SuspendStatus
+    (Constant SUSPEND_STATUS_SUSPENDED = 0x1 "")
+)
+
+(ConstantSet ClassStatus
+    (Constant VERIFIED               =1   "")
+    (Constant PREPARED               =2   "")
+    (Constant INITIALIZED            =4   "")
+    (Constant ERROR                  =8   "")
+)
+
+(ConstantSet TypeTag
+    (Constant CLASS=1 "ReferenceType is a class. ")
+    (Constant INTERFACE=2 "ReferenceType is an interface. ")
+    (Constant ARRAY=3 "ReferenceType is an array. ")
+)
+
+(ConstantSet Tag
+    (Constant ARRAY = '[' "'[' - an array object (objectID size). ")
+    (Constant BYTE = 'B' "'B' - a byte value (1 byte).")
+    (Constant CHAR = 'C' "'C' - a character value (2 bytes).")
+    (Constant OBJECT = 'L' "'L' - an object (objectID size).")
+    (Constant FLOAT = 'F' "'F' - a float value (4 bytes).")
+    (Constant DOUBLE = 'D' "'D' - a double value (8 bytes).")
+    (Constant INT = 'I' "'I' - an int value (4 bytes).")
+    (Constant LONG = 'J' "'J' - a long value (8 bytes).")
+    (Constant SHORT = 'S' "'S' - a short value (2 bytes).")
+    (Constant VOID = 'V' "'V' - a void value (no bytes).")
+    (Constant BOOLEAN = 'Z' "'Z' - a boolean value (1 byte).")
+    (Constant STRING = 's' "'s' - a String object (objectID size). ")
+    (Constant THREAD = 't' "'t' - a Thread object (objectID size). ")
+    (Constant THREAD_GROUP = 'g' "'g' - a ThreadGroup object (objectID size). ")
+    (Constant CLASS_LOADER = 'l' "'l' - a ClassLoader object (objectID size). ")
+    (Constant CLASS_OBJECT = 'c' "'c' - a class object object (objectID size). ")
+)
+
+(ConstantSet StepDepth
+    (Constant INTO                   =0   "Step into any method calls that occur before the end of the step. ")
+    (Constant OVER                   =1   "Step over any method calls that occur before the end of the step. ")
+    (Constant OUT                    =2   "Step out of the current method. ")
+)
+
+(ConstantSet StepSize
+    (Constant MIN                    =0   "Step by the minimum possible amount (often a bytecode instruction). ")
+    (Constant LINE                   =1   "Step to the next source line unless there is no line number information in which case a MIN step is done instead.")
+)
+
+(ConstantSet SuspendPolicy
+    (Constant NONE                   =0   "Suspend no threads when this event is encountered. ")
+    (Constant EVENT_THREAD           =1   "Suspend the event thread when this event is encountered. ")
+    (Constant ALL                    =2   "Suspend all threads when this event is encountered. ")
+)
+
+(ConstantSet InvokeOptions
+    "The invoke options are a combination of zero or more of the following bit flags: "
+    (Constant INVOKE_SINGLE_THREADED =0x01 "otherwise, all threads started. ")
+    (Constant INVOKE_NONVIRTUAL      =0x02 "otherwise, normal virtual invoke (instance methods only) ")
+)
@@ -1,37 +1,31 @@
 package client
 
 import (
-	"fmt"
 	"io"
 	"reflect"
-
-	"github.com/sirupsen/logrus"
 )
 
+// The names by which the ThreadReference command set was known before it was generated.
 const (
-	Thread        = CommandSet(11)
-	ThreadName    = Command(1)
-	ThreadSuspend = Command(2)
-	ThreadResume  = Command(3)
-	ThreadFrames  = Command(6)
+	Thread        = ThreadReference
+	ThreadName    = ThreadReferenceName
+	ThreadSuspend = ThreadReferenceSuspend
+	ThreadResume  = ThreadReferenceResume
+	ThreadFrames  = ThreadReferenceFrames
 )
 
 type ThreadId ReferenceTypeId
 
 func (id ThreadId) Frames(c Client, startFrame int, length int) ([]Frame, error) {
-	res, err := c.Call(Thread, ThreadFrames,
-		Seq().ThreadId(id).Int(startFrame).Int(length).Marshal())
+	res, err := call(c, Thread, ThreadFrames, c.IDSizes().Seq().ThreadId(id).Int(startFrame).Int(length))
 	if err != nil {
 		return nil, err
 	}
-	if res.ErrCode != 0 {
-		return nil, lookupError(res.ErrCode)
-	}
 	ms := struct {
 		Count  int
 		Frames []Frame `jdwp:"counter:Count"`
 	}{}
-	err = Parse(res.Data, &ms)
+	err = c.IDSizes().Parse(res.Data, &ms)
 	if err != nil {
 		return nil, err
 	}
@@ -51,37 +45,30 @@ type Frame struct {
 	Location Location
 }
 
-const (
-	StackFrame          = CommandSet(16)
-	StackFrameGetValues = Command(1)
-)
-
 func (f Frame) GetValues(c Client, vars ...VariableDef) (map[string]TaggedValue, error) {
+	log := loggerOf(c)
 	valid := []VariableDef{}
 	for _, v := range vars {
 		if v.CodeIndex <= f.Location.Index && f.Location.Index < v.CodeIndex+uint64(v.Length) {
-			logrus.WithField("vName", v.Name).WithField("tag", v.Tag()).WithField("offset", v.Slot).Debug("requesting variable")
+			log.Debugf("requesting variable %s, tag %v at slot %d", v.Name, v.Tag(), v.Slot)
 			valid = append(valid, v)
 		} else {
-			logrus.WithField("vName", v.Name).Warn("skipping variable - not in legal scope")
+			log.Warnf("skipping variable %s - not in legal scope", v.Name)
 		}
 	}
-	s := Seq().FrameId(f).Int(len(valid))
+	s := c.IDSizes().Seq().FrameId(f).Int(len(valid))
 	for _, v := range valid {
 		s.Int(v.Slot).Octet(uint8(v.Tag()))
 	}
-	res, err := c.Call(StackFrame, StackFrameGetValues, s.Marshal())
+	res, err := call(c, StackFrame, StackFrameGetValues, s)
 	if err != nil {
 		return nil, err
 	}
-	if res.ErrCode != 0 {
-		return nil, lookupError(res.ErrCode)
-	}
 	ms := struct {
 		Count  int
 		Values []TaggedValue `jdwp:"counter:Count"`
 	}{}
-	err = Parse(res.Data, &ms)
+	err = c.IDSizes().Parse(res.Data, &ms)
 	if err != nil {
 		return nil, err
 	}
@@ -108,21 +95,11 @@ func TaggedValueFactory(buf io.Reader, into reflect.Value) error {
 	} else {
 		tag = Tag(t)
 	}
-	var val TaggedValue
-	switch tag {
-	case TagObject:
-		v := ObjectId(0)
-		val = &v
-	case TagString:
-		v := StringId(0)
-		val = &v
-	default:
-		return fmt.Errorf("unimplemented factory for tag %v", tag)
-	}
-	vv := reflect.ValueOf(val) //.Elem()
-	if err := ParseBuf(buf, vv, nil, nil); err != nil {
+	val, err := newValue(tag)
+	if err != nil {
 		return err
 	}
+	vv := reflect.ValueOf(val) //.Elem()
 	into.Set(vv)
-	return nil
+	return ParseBuf(buf, vv, nil, nil)
 }
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport opens the stream that carries a connection to a VM.
+type Transport interface {
+	Open() (io.ReadWriteCloser, error)
+}
+
+// deadliner and writeDeadliner are implemented by streams, such as a net.Conn, that can bound
+// a blocked read or write without being closed.
+type deadliner interface {
+	SetDeadline(t time.Time) error
+}
+
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// Open connects to a VM over the stream opened by t.
+func Open(t Transport, opts ...Option) (Client, error) {
+	conn, err := t.Open()
+	if err != nil {
+		return nil, err
+	}
+	return New(conn, opts...)
+}
+
+// NetTransport dials a socket: "tcp" to a host:port, or "unix" to a socket path.
+type NetTransport struct {
+	Network string
+	Address string
+	Timeout time.Duration // zero for no timeout beyond the operating system's
+}
+
+func (t *NetTransport) Open() (io.ReadWriteCloser, error) {
+	return net.DialTimeout(t.Network, t.Address, t.Timeout)
+}
+
+// StreamTransport is an already open stream, such as one end of an in-memory pipe. It can
+// only be opened once.
+type StreamTransport struct {
+	Stream io.ReadWriteCloser
+	once   sync.Once
+}
+
+func (t *StreamTransport) Open() (io.ReadWriteCloser, error) {
+	var s io.ReadWriteCloser
+	t.once.Do(func() {
+		s = t.Stream
+	})
+	if s == nil {
+		return nil, fmt.Errorf("stream transport has already been opened")
+	}
+	return s, nil
+}
+
+// ExecTransport runs a command and speaks to the VM over its standard input and output:
+// for instance, socat relaying to a remote socket. Its standard error is passed through.
+type ExecTransport struct {
+	Path string
+	Args []string
+	Env  []string // added to the environment the command inherits
+}
+
+func (t *ExecTransport) Open() (io.ReadWriteCloser, error) {
+	cmd := exec.Command(t.Path, t.Args...)
+	cmd.Env = append(os.Environ(), t.Env...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &cmdStream{cmd: cmd, stdin: stdin, stdout: stdout}, nil
+}
+
+// cmdStream is the standard input and output of a running command.
+type cmdStream struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    io.ReadCloser
+	closeOnce sync.Once
+}
+
+func (s *cmdStream) Read(b []byte) (int, error) {
+	return s.stdout.Read(b)
+}
+
+func (s *cmdStream) Write(b []byte) (int, error) {
+	return s.stdin.Write(b)
+}
+
+// Close ends the command: closing its input is not enough to stop every relay, so it is killed.
+func (s *cmdStream) Close() error {
+	s.closeOnce.Do(func() {
+		s.stdin.Close()
+		s.stdout.Close()
+		s.cmd.Process.Kill()
+		s.cmd.Wait()
+	})
+	return nil
+}
+
+// TransportFor maps a network name, as taken by the -net flag, onto a transport. Besides the
+// networks known to net.Dial, "exec" runs the command line given as the address.
+func TransportFor(network string, address string) (Transport, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return &NetTransport{Network: network, Address: address}, nil
+	case "exec":
+		args := strings.Fields(address)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("exec transport needs a command to run")
+		}
+		return &ExecTransport{Path: args[0], Args: args[1:]}, nil
+	}
+	return nil, fmt.Errorf("unknown transport %q", network)
+}
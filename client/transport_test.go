@@ -0,0 +1,124 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+func version(t *testing.T, c client.Client) string {
+	r, err := c.Call(client.VirtualMachine, client.VirtualMachineVersion, nil)
+	require.NoError(t, err)
+	var v client.VersionReply
+	require.NoError(t, c.IDSizes().Parse(r.Data, &v))
+	return v.VmName
+}
+
+func TestUnixTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jdwp")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "vm.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer l.Close()
+	go jdwptest.NewAgent(jdwptest.NewVM()).Serve(l)
+
+	tr, err := client.TransportFor("unix", path)
+	require.NoError(t, err)
+	c, err := client.Open(tr)
+	require.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, "jdwptest", version(t, c))
+}
+
+// stream hides everything about a connection but reading, writing and closing, so that it
+// has no deadlines.
+type stream struct {
+	io.ReadWriteCloser
+}
+
+func TestStreamWithoutDeadlines(t *testing.T) {
+	a := jdwptest.NewAgent(jdwptest.NewVM())
+	c, err := client.Open(&client.StreamTransport{Stream: stream{a.Pipe()}})
+	require.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, "jdwptest", version(t, c))
+}
+
+func TestStreamHandshakeTimeout(t *testing.T) {
+	here, _ := net.Pipe()
+	_, err := client.New(stream{here}, client.WithHandshakeTimeout(50*time.Millisecond))
+	var timeout *client.HandshakeTimeoutError
+	assert.True(t, errors.As(err, &timeout))
+}
+
+func TestStreamCancelledWrite(t *testing.T) {
+	// The agent stops reading after the handshake and IDSizes, so the next write blocks
+	a := jdwptest.NewAgent(jdwptest.NewVM())
+	a.Handle(client.VirtualMachine, client.VirtualMachineVersion, func(s *jdwptest.Session, p *jdwptest.Packet) ([]byte, error) {
+		select {}
+	})
+	c, err := client.New(stream{a.Pipe()})
+	require.NoError(t, err)
+	defer c.Close()
+	_, _, err = c.Send(client.VirtualMachine, client.VirtualMachineVersion, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.CallContext(ctx, client.VirtualMachine, client.VirtualMachineVersion, nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	select {
+	case <-c.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection not closed after abandoning a write")
+	}
+}
+
+// TestHelperProcess is not a test: run as a subprocess, it relays its standard input and output
+// to the address in JDWP_RELAY, as socat would.
+func TestHelperProcess(t *testing.T) {
+	addr := os.Getenv("JDWP_RELAY")
+	if addr == "" {
+		return
+	}
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		os.Exit(1)
+	}
+	go func() {
+		io.Copy(conn, os.Stdin)
+		conn.Close()
+	}()
+	io.Copy(os.Stdout, conn)
+	os.Exit(0)
+}
+
+func TestExecTransport(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go jdwptest.NewAgent(jdwptest.NewVM()).Serve(l)
+
+	c, err := client.Open(&client.ExecTransport{
+		Path: os.Args[0],
+		Args: []string{"-test.run=TestHelperProcess"},
+		Env:  []string{"JDWP_RELAY=" + l.Addr().String()},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "jdwptest", version(t, c))
+	assert.NoError(t, c.Close())
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// The primitive values that can be read as a TaggedValue.
+type (
+	BooleanValue bool
+	ByteValue    int8
+	CharValue    uint16
+	ShortValue   int16
+	IntValue     int32
+	LongValue    int64
+	FloatValue   float32
+	DoubleValue  float64
+)
+
+// VoidValue is the value returned by a method declared void.
+type VoidValue struct{}
+
+func (BooleanValue) Tag() Tag { return TagBoolean }
+func (ByteValue) Tag() Tag    { return TagByte }
+func (CharValue) Tag() Tag    { return TagChar }
+func (ShortValue) Tag() Tag   { return TagShort }
+func (IntValue) Tag() Tag     { return TagInt }
+func (LongValue) Tag() Tag    { return TagLong }
+func (FloatValue) Tag() Tag   { return TagFload }
+func (DoubleValue) Tag() Tag  { return TagDouble }
+func (VoidValue) Tag() Tag    { return TagVoid }
+
+func (v BooleanValue) RecoverValue(c Client) (interface{}, error) { return bool(v), nil }
+func (v ByteValue) RecoverValue(c Client) (interface{}, error)    { return int8(v), nil }
+func (v CharValue) RecoverValue(c Client) (interface{}, error)    { return rune(v), nil }
+func (v ShortValue) RecoverValue(c Client) (interface{}, error)   { return int16(v), nil }
+func (v IntValue) RecoverValue(c Client) (interface{}, error)     { return int32(v), nil }
+func (v LongValue) RecoverValue(c Client) (interface{}, error)    { return int64(v), nil }
+func (v FloatValue) RecoverValue(c Client) (interface{}, error)   { return float32(v), nil }
+func (v DoubleValue) RecoverValue(c Client) (interface{}, error)  { return float64(v), nil }
+func (v VoidValue) RecoverValue(c Client) (interface{}, error)    { return nil, nil }
+
+func (ThreadId) Tag() Tag      { return TagThread }
+func (ThreadGroupId) Tag() Tag { return TagThreadGroup }
+func (ClassLoaderId) Tag() Tag { return TagClassLoader }
+func (ClassObjectId) Tag() Tag { return TagClassObject }
+func (ArrayId) Tag() Tag       { return TagArray }
+
+func (id ThreadId) RecoverValue(c Client) (interface{}, error) {
+	return ObjectId(id).RecoverValue(c)
+}
+
+func (id ThreadGroupId) RecoverValue(c Client) (interface{}, error) {
+	return ObjectId(id).RecoverValue(c)
+}
+
+func (id ClassLoaderId) RecoverValue(c Client) (interface{}, error) {
+	return ObjectId(id).RecoverValue(c)
+}
+
+func (id ClassObjectId) RecoverValue(c Client) (interface{}, error) {
+	return ObjectId(id).RecoverValue(c)
+}
+
+func (id ArrayId) RecoverValue(c Client) (interface{}, error) {
+	return ObjectId(id).RecoverValue(c)
+}
+
+// valueTypes are the types of the values read for each tag.
+var valueTypes = map[Tag]func() TaggedValue{
+	TagObject:      func() TaggedValue { return new(ObjectId) },
+	TagString:      func() TaggedValue { return new(StringId) },
+	TagThread:      func() TaggedValue { return new(ThreadId) },
+	TagThreadGroup: func() TaggedValue { return new(ThreadGroupId) },
+	TagClassLoader: func() TaggedValue { return new(ClassLoaderId) },
+	TagClassObject: func() TaggedValue { return new(ClassObjectId) },
+	TagArray:       func() TaggedValue { return new(ArrayId) },
+	TagBoolean:     func() TaggedValue { return new(BooleanValue) },
+	TagByte:        func() TaggedValue { return new(ByteValue) },
+	TagChar:        func() TaggedValue { return new(CharValue) },
+	TagShort:       func() TaggedValue { return new(ShortValue) },
+	TagInt:         func() TaggedValue { return new(IntValue) },
+	TagLong:        func() TaggedValue { return new(LongValue) },
+	TagFload:       func() TaggedValue { return new(FloatValue) },
+	TagDouble:      func() TaggedValue { return new(DoubleValue) },
+	TagVoid:        func() TaggedValue { return &VoidValue{} },
+}
+
+func newValue(tag Tag) (TaggedValue, error) {
+	if f, ok := valueTypes[tag]; ok {
+		return f(), nil
+	}
+	return nil, fmt.Errorf("unimplemented factory for tag %v", tag)
+}
+
+// isObject says whether values of the tag's type are objects, which are tagged even where
+// the type is known.
+func (t Tag) isObject() bool {
+	switch t {
+	case TagArray, TagObject, TagString, TagThread, TagThreadGroup, TagClassLoader, TagClassObject:
+		return true
+	}
+	return false
+}
+
+// TaggedObjectId is an object preceded by the tag of its type.
+type TaggedObjectId struct {
+	Tag      Tag
+	ObjectId ObjectId
+}
+
+// UntaggedValue is a value written without its tag, where its type is already known, such
+// as that of the field being set. It cannot be read.
+type UntaggedValue struct {
+	Value TaggedValue
+}
+
+func (u *UntaggedValue) Marshal(out io.Writer) error {
+	if u.Value == nil {
+		return errors.New("cannot marshal an UntaggedValue without a value")
+	}
+	return MarshalBuf(out, reflect.ValueOf(u.Value), nil, nil)
+}
+
+func (u *UntaggedValue) Unmarshal(buf io.Reader) error {
+	return errors.New("cannot parse a value without its tag")
+}
+
+// ArrayRegion is a run of an array's components, which share the tag preceding them. Only
+// objects are tagged individually.
+type ArrayRegion struct {
+	Tag    Tag
+	Values []TaggedValue
+}
+
+func (a *ArrayRegion) Unmarshal(buf io.Reader) error {
+	tag, err := parseUint8(buf)
+	if err != nil {
+		return err
+	}
+	count, err := parseInt32(buf)
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("array region of %d values", count)
+	}
+	a.Tag, a.Values = Tag(tag), nil
+	if a.Tag == TagVoid {
+		// Each would take no bytes, so their count would be trusted after all
+		return errors.New("array region of void values")
+	}
+	// The count is not trusted with an allocation; a short buffer fails soon enough
+	for i := int32(0); i < count; i++ {
+		var v TaggedValue
+		if a.Tag.isObject() {
+			if err := TaggedValueFactory(buf, reflect.ValueOf(&v).Elem()); err != nil {
+				return prefixPath(err, fmt.Sprintf(".Values[%d]", i))
+			}
+		} else {
+			if v, err = newValue(a.Tag); err != nil {
+				return err
+			}
+			if err := ParseBuf(buf, reflect.ValueOf(v), nil, nil); err != nil {
+				return prefixPath(err, fmt.Sprintf(".Values[%d]", i))
+			}
+		}
+		a.Values = append(a.Values, v)
+	}
+	return nil
+}
+
+func (a *ArrayRegion) Marshal(out io.Writer) error {
+	if err := binary.Write(out, binary.BigEndian, a.Tag); err != nil {
+		return err
+	}
+	if err := binary.Write(out, binary.BigEndian, int32(len(a.Values))); err != nil {
+		return err
+	}
+	for _, v := range a.Values {
+		if a.Tag.isObject() {
+			if err := binary.Write(out, binary.BigEndian, v.Tag()); err != nil {
+				return err
+			}
+		}
+		if err := MarshalBuf(out, reflect.ValueOf(v), nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
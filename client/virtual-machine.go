@@ -1,30 +1,5 @@
 package client
 
-const (
-	VirtualMachine                      = CommandSet(1)
-	VirtualMachineVersion               = Command(1)
-	VirtualMachineClassesBySignature    = Command(2)
-	VirtualMachineAllClasses            = Command(3)
-	VirtualMachineAllThreads            = Command(4)
-	VirtualMachineTopLevelThreadGroups  = Command(5)
-	VirtualMachineDispose               = Command(6)
-	VirtualMachineIDSizes               = Command(7)
-	VirtualMachineSuspend               = Command(8)
-	VirtualMachineResume                = Command(9)
-	VirtualMachineExit                  = Command(10)
-	VirtualMachineCreateString          = Command(11)
-	VirtualMachineCapabilities          = Command(12)
-	VirtualMachineClassPaths            = Command(13)
-	VirtualMachineDisposeObjects        = Command(14)
-	VirtualMachineHoldEvents            = Command(15)
-	VirtualMachineReleaseEvents         = Command(16)
-	VirtualMachineCapabilitiesNew       = Command(17)
-	VirtualMachineRedefineClasses       = Command(18)
-	VirtualMachineSetDefaultStratum     = Command(19)
-	VirtualMachineAllClassesWithGeneric = Command(20)
-	VirtualMachineInstanceCounts        = Command(21)
-)
-
 type VersionReply struct {
 	Description string `jdwp:"Text information on the VM version"`
 	JdwpMajor   int    `jdwp:"Major JDWP Version number"`
@@ -45,3 +20,13 @@ type ClassDetails struct {
 	ClassId    ClassId // Kind of following reference type.
 	Status     int     //	The current class status.
 }
+
+func ClassesBySignature(c Client, signature string) ([]ClassDetails, error) {
+	res, err := call(c, VirtualMachine, VirtualMachineClassesBySignature, c.IDSizes().Seq().String(signature))
+	if err != nil {
+		return nil, err
+	}
+	var cs ClassesBySignatureReply
+	err = c.IDSizes().Parse(res.Data, &cs)
+	return cs.ClassDetails, err
+}
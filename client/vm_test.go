@@ -0,0 +1,184 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+// program is a small fake VM: Main.run has a thread stopped in it, with two locals.
+type program struct {
+	vm     *jdwptest.VM
+	main   *jdwptest.Class
+	run    *jdwptest.Method
+	thread *jdwptest.Thread
+	self   *jdwptest.Object
+}
+
+// newProgram builds the program in vm, after whatever it already holds.
+func newProgram(vm *jdwptest.VM) *program {
+	p := &program{vm: vm}
+	p.main = vm.AddClass("Lcom/example/Main;")
+	p.main.AddField("count", "I")
+	p.main.AddMethod("<init>", "()V", 4, client.LineEntry{LineCodeIndex: 0, LineNumber: 3})
+	p.run = p.main.AddMethod("run", "(Ljava/lang/String;)V", 20,
+		client.LineEntry{LineCodeIndex: 0, LineNumber: 10},
+		client.LineEntry{LineCodeIndex: 8, LineNumber: 11},
+		client.LineEntry{LineCodeIndex: 16, LineNumber: 12})
+	p.run.ArgCount = 2
+	p.run.AddVariable("this", "Lcom/example/Main;", 0, 0, 20).
+		AddVariable("name", "Ljava/lang/String;", 1, 0, 20)
+
+	p.self = vm.NewObject(p.main)
+	p.thread = vm.AddThread("main")
+	p.thread.Call(p.run, 8, map[int]jdwptest.Value{
+		0: jdwptest.ObjectValue(p.self),
+		1: jdwptest.StringValue(vm.NewString("world")),
+	})
+	return p
+}
+
+func connect(t *testing.T, a *jdwptest.Agent) client.Client {
+	c, err := client.New(a.Pipe())
+	require.NoError(t, err)
+	return c
+}
+
+func classBySignature(t *testing.T, c client.Client, sig string) client.ClassId {
+	r, err := c.Call(client.VirtualMachine, client.VirtualMachineClassesBySignature, c.IDSizes().Seq().String(sig).Marshal())
+	require.NoError(t, err)
+	var cs client.ClassesBySignatureReply
+	require.NoError(t, c.IDSizes().Parse(r.Data, &cs))
+	require.Equal(t, 1, cs.Classes)
+	return cs.ClassDetails[0].ClassId
+}
+
+func method(t *testing.T, c client.Client, cls client.ClassId, name string) client.MethodDef {
+	ms, err := cls.Methods(c)
+	require.NoError(t, err)
+	for _, m := range ms {
+		if m.Name == name {
+			return m
+		}
+	}
+	t.Fatalf("no method %s", name)
+	return client.MethodDef{}
+}
+
+func TestInspectStoppedThread(t *testing.T) {
+	for _, sizes := range []client.IDSizes{
+		client.DefaultIDSizes,
+		{FieldIDSize: 4, MethodIDSize: 4, ObjectIDSize: 4, ReferenceTypeIDSize: 4, FrameIDSize: 4},
+	} {
+		vm := jdwptest.NewVM()
+		vm.Sizes = sizes
+		p := newProgram(vm)
+		c := connect(t, jdwptest.NewAgent(p.vm))
+		defer c.Close()
+		assert.Equal(t, sizes, c.IDSizes())
+
+		cls := classBySignature(t, c, "Lcom/example/Main;")
+		assert.Equal(t, p.main.Id, cls)
+		sig, err := cls.Signature(c)
+		assert.NoError(t, err)
+		assert.Equal(t, "Lcom/example/Main;", sig)
+
+		fs, err := cls.Fields(c)
+		assert.NoError(t, err)
+		assert.Equal(t, []client.Field{{FieldId: p.main.Fields[0].Id, Name: "count", Signature: "I"}}, fs)
+
+		run := method(t, c, cls, "run")
+		lines, err := run.MethodId.LineTable(c)
+		assert.NoError(t, err)
+		assert.Equal(t, p.run.Lines, lines.LineEntries)
+
+		_, err = c.Call(client.Thread, client.ThreadSuspend, c.IDSizes().Seq().ThreadId(p.thread.Id).Marshal())
+		assert.NoError(t, err)
+		frames, err := p.thread.Id.Frames(c, 0, -1)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		assert.Equal(t, p.main.Id, frames[0].Location.ClassId)
+		assert.Equal(t, uint64(8), frames[0].Location.Index)
+
+		// The frame's method is usable as it comes
+		vars, err := frames[0].Location.MethodId.VariableTable(c)
+		require.NoError(t, err)
+		assert.Equal(t, p.run.Variables, vars.Variables)
+
+		values, err := frames[0].GetValues(c, vars.Variables...)
+		require.NoError(t, err)
+		name, err := values["name"].RecoverValue(c)
+		assert.NoError(t, err)
+		assert.Equal(t, "world", name)
+		self, err := values["this"].RecoverValue(c)
+		assert.NoError(t, err)
+		if assert.IsType(t, &client.Object{}, self) {
+			assert.Equal(t, p.self.Id, self.(*client.Object).ObjectId)
+			assert.Equal(t, "Lcom/example/Main;", self.(*client.Object).Class.Signature)
+		}
+	}
+}
+
+func TestFramesRequireSuspendedThread(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	c := connect(t, jdwptest.NewAgent(p.vm))
+	defer c.Close()
+	_, err := p.thread.Id.Frames(c, 0, -1)
+	assert.Equal(t, client.ErrThreadNotSuspended, err)
+}
+
+func TestBreakpoint(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(p.vm)
+	c := connect(t, a)
+	defer c.Close()
+
+	run := method(t, c, classBySignature(t, c, "Lcom/example/Main;"), "run")
+	location := client.NewLocation(run.MethodId, 8)
+	r, err := c.Call(client.EventRequest, client.Set,
+		client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyEventThread).
+			WithMod(client.ModKindLocation).WithLocation(location).
+			WithMod(client.ModKindCount).WithInt(1).
+			Marshal(c.IDSizes()))
+	require.NoError(t, err)
+	var req client.EventRequestSetReply
+	require.NoError(t, client.Parse(r.Data, &req))
+	assert.Len(t, p.vm.Requests(), 1)
+
+	assert.Equal(t, 1, a.Breakpoint(p.thread))
+	assert.Equal(t, 1, p.thread.SuspendCount())
+	select {
+	case e := <-c.Events():
+		var comp client.Composite
+		require.NoError(t, c.IDSizes().Parse(e.Data, &comp))
+		assert.Equal(t, client.SuspendPolicyEventThread, comp.SuspendPolicy)
+		assert.Equal(t, &client.EventBreakpoint{
+			RequestId: req.RequestId,
+			Thread:    p.thread.Id,
+			Location:  location,
+		}, comp.Events[0])
+	case <-time.After(time.Second):
+		t.Fatal("no breakpoint event")
+	}
+
+	// The request had a count of one, so it has expired
+	assert.Empty(t, p.vm.Requests())
+	assert.Equal(t, 0, a.Breakpoint(p.thread))
+
+	_, err = c.Call(client.Thread, client.ThreadResume, c.IDSizes().Seq().ThreadId(p.thread.Id).Marshal())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, p.thread.SuspendCount())
+}
+
+func TestUnknownCommandNotImplemented(t *testing.T) {
+	c := connect(t, jdwptest.NewAgent(jdwptest.NewVM()))
+	defer c.Close()
+	r, err := c.Call(client.VirtualMachine, client.VirtualMachineCapabilities, nil)
+	require.NoError(t, err)
+	assert.Equal(t, client.ErrNotImplemented.(client.JdwpError).Code, r.ErrCode)
+}
@@ -0,0 +1,286 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// ReleaseTimeout bounds how long Close spends resuming the VM and clearing event requests,
+// when the client has a suspend budget.
+const ReleaseTimeout = 5 * time.Second
+
+// WithSuspendBudget has the client keep track of the threads it has suspended, whether
+// through events or Suspend commands, and resume any that remain suspended for longer than
+// budget, reporting each with an EventSuspensionExpired. Close then resumes whatever is still
+// suspended and clears every event request that the client set, before disconnecting.
+//
+// Suspensions are counted as the VM counts them: each ThreadReference.Resume undoes the
+// oldest suspension of its thread, and each VirtualMachine.Resume undoes the oldest of the
+// whole VM and the oldest of each thread.
+func WithSuspendBudget(budget time.Duration) Option {
+	return func(c *client) {
+		c.watch = &watchdog{
+			c:          c,
+			budget:     budget,
+			stop:       make(chan struct{}),
+			stopped:    make(chan struct{}),
+			suspending: map[Id]ThreadId{},
+			setting:    map[Id]EventKind{},
+			requests:   map[int]EventKind{},
+		}
+	}
+}
+
+// EventSuspensionExpired is never sent by the VM. The client synthesises it when it resumes
+// a suspension that outlasted the budget set by WithSuspendBudget.
+type EventSuspensionExpired struct {
+	RequestId int           // always 0
+	Thread    ThreadId      // the thread resumed, or 0 if it was the whole VM
+	Held      time.Duration // how long the suspension had lasted
+}
+
+func (*EventSuspensionExpired) EventKind() EventKind {
+	return EventKindSUSPENSION_EXPIRED
+}
+
+// suspension is one level of suspension, of a thread or of the whole VM.
+type suspension struct {
+	thread ThreadId // 0 for the whole VM
+	since  time.Time
+}
+
+type watchdog struct {
+	c       *client
+	budget  time.Duration
+	stop    chan struct{} // closed to stop run
+	stopped chan struct{} // closed as run returns
+
+	mu         sync.Mutex
+	suspended  []suspension      // oldest first
+	suspending map[Id]ThreadId   // Suspend commands awaiting their reply, 0 for the whole VM
+	setting    map[Id]EventKind  // EventRequest.Set commands awaiting their reply
+	requests   map[int]EventKind // event requests set, by ID
+}
+
+// sending notes a command on its way to the VM. A suspension counts only once the VM has
+// replied without error, while a resumption counts at once.
+func (w *watchdog) sending(id Id, set CommandSet, cmd Command, data []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case set == VirtualMachine && cmd == VirtualMachineSuspend:
+		w.suspending[id] = 0
+	case set == VirtualMachine && cmd == VirtualMachineResume:
+		w.resume(0)
+		seen := map[ThreadId]bool{}
+		for _, s := range w.suspended {
+			if s.thread != 0 {
+				seen[s.thread] = true
+			}
+		}
+		for t := range seen {
+			w.resume(t)
+		}
+	case set == Thread && (cmd == ThreadSuspend || cmd == ThreadResume):
+		t, err := parseId(bytes.NewReader(data), w.c.sizes.ObjectIDSize)
+		if err != nil || t == 0 {
+			return
+		}
+		if cmd == ThreadSuspend {
+			w.suspending[id] = ThreadId(t)
+		} else {
+			w.resume(ThreadId(t))
+		}
+	case set == EventRequest && cmd == Set && len(data) > 0:
+		w.setting[id] = EventKind(data[0])
+	case set == EventRequest && cmd == Clear && len(data) >= 5:
+		delete(w.requests, int(int32(binary.BigEndian.Uint32(data[1:]))))
+	case set == EventRequest && cmd == ClearAllBreakPoints:
+		for id, kind := range w.requests {
+			if kind == EventKindBreakpoint {
+				delete(w.requests, id)
+			}
+		}
+	}
+}
+
+// replied notes the reply to a command, from which suspensions are confirmed and the IDs of
+// event requests are learned.
+func (w *watchdog) replied(r *Reply) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t, ok := w.suspending[r.Id]; ok {
+		delete(w.suspending, r.Id)
+		if r.ErrCode == 0 {
+			w.suspend(t)
+		}
+		return
+	}
+	kind, ok := w.setting[r.Id]
+	if !ok {
+		return
+	}
+	delete(w.setting, r.Id)
+	if r.ErrCode == 0 && len(r.Data) >= 4 {
+		w.requests[int(int32(binary.BigEndian.Uint32(r.Data)))] = kind
+	}
+}
+
+// event notes the suspension caused by a Composite event.
+func (w *watchdog) event(data []byte) {
+	if len(data) < 1 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch SuspendPolicy(data[0]) {
+	case SuspendPolicyAll:
+		w.suspend(0)
+	case SuspendPolicyEventThread:
+		// Every event in a set is for the same thread
+		_, events, err := w.c.reg.splitComposite(data, w.c.sizes)
+		if err != nil || len(events) == 0 {
+			return
+		}
+		if t, ok := w.c.reg.thread(events[0], w.c.sizes); ok {
+			w.suspend(t)
+		}
+	}
+}
+
+func (w *watchdog) suspend(t ThreadId) {
+	w.suspended = append(w.suspended, suspension{thread: t, since: time.Now()})
+}
+
+// resume forgets the oldest suspension of t.
+func (w *watchdog) resume(t ThreadId) {
+	for i, s := range w.suspended {
+		if s.thread == t {
+			w.suspended = append(w.suspended[:i], w.suspended[i+1:]...)
+			return
+		}
+	}
+}
+
+// expired returns the oldest suspension outlasting the budget, if there is one.
+func (w *watchdog) expired() (suspension, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.suspended) == 0 || time.Since(w.suspended[0].since) < w.budget {
+		return suspension{}, false
+	}
+	return w.suspended[0], true
+}
+
+// run resumes expired suspensions until the client is closed or the watchdog stopped.
+func (w *watchdog) run() {
+	defer w.c.wg.Done()
+	defer close(w.stopped)
+	tick := w.budget / 4
+	if tick < time.Millisecond {
+		tick = time.Millisecond
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.c.close:
+			return
+		case <-w.c.done:
+			return
+		case <-ticker.C:
+		}
+		for {
+			select {
+			case <-w.stop:
+				return
+			default:
+			}
+			s, ok := w.expired()
+			if !ok {
+				break
+			}
+			held := time.Since(s.since)
+			if err := w.release(context.Background(), s); err != nil {
+				w.c.log.Warnf("jdwp could not resume a suspension held for %v: %v", held, err)
+				break
+			}
+			w.c.log.Warnf("jdwp resumed thread %d (0 for the whole VM) after a suspension of %v", s.thread, held)
+			w.c.events.push(suspensionExpired(w.c.sizes, s.thread, held))
+		}
+	}
+}
+
+// release resumes one suspension. Sending the Resume command updates the bookkeeping.
+func (w *watchdog) release(ctx context.Context, s suspension) error {
+	var err error
+	if s.thread == 0 {
+		_, err = callContext(ctx, w.c, VirtualMachine, VirtualMachineResume, w.c.sizes.Seq())
+	} else {
+		_, err = callContext(ctx, w.c, Thread, ThreadResume, w.c.sizes.Seq().ThreadId(s.thread))
+	}
+	if err != nil {
+		// Forget the suspension anyway, rather than trying again and again
+		w.mu.Lock()
+		w.resume(s.thread)
+		w.mu.Unlock()
+	}
+	return err
+}
+
+// halt stops run, waiting for any suspension it is resuming to be resumed, so that nothing
+// else resumes it too.
+func (w *watchdog) halt() {
+	close(w.stop)
+	<-w.stopped
+}
+
+// releaseAll resumes every suspension and clears every event request, as best it can. The
+// watchdog must have been halted.
+func (w *watchdog) releaseAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), ReleaseTimeout)
+	defer cancel()
+	w.mu.Lock()
+	requests := make([]EventRequestClear, 0, len(w.requests))
+	for id, kind := range w.requests {
+		requests = append(requests, EventRequestClear{EventKind: kind, RequestId: id})
+	}
+	w.mu.Unlock()
+	// Clear first, so that no more events arrive to suspend threads again
+	for _, r := range requests {
+		if _, err := callContext(ctx, w.c, EventRequest, Clear, w.c.sizes.Seq().Value(r)); err != nil {
+			w.c.log.Warnf("jdwp could not clear event request %d on closing: %v", r.RequestId, err)
+		}
+	}
+	for ctx.Err() == nil {
+		w.mu.Lock()
+		if len(w.suspended) == 0 {
+			w.mu.Unlock()
+			return
+		}
+		s := w.suspended[0]
+		w.mu.Unlock()
+		if err := w.release(ctx, s); err != nil {
+			w.c.log.Warnf("jdwp could not resume thread %d (0 for the whole VM) on closing: %v", s.thread, err)
+		}
+	}
+}
+
+// suspensionExpired synthesises the Composite event reporting a suspension that was resumed.
+func suspensionExpired(sizes IDSizes, t ThreadId, held time.Duration) *Event {
+	data := sizes.Seq().Octet(uint8(SuspendPolicyNone)).Int(1).Octet(uint8(EventKindSUSPENSION_EXPIRED)).
+		Int(0).ThreadId(t).Marshal()
+	data = append(data, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(data[len(data)-8:], uint64(held))
+	return &Event{
+		Header:  Header{Length: uint32(HeaderLength + len(data))},
+		Set:     EventCommandSet,
+		Command: CompositeCommands,
+		Data:    data,
+	}
+}
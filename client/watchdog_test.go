@@ -0,0 +1,155 @@
+package client_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jan-g/jdwp-client/client"
+	"github.com/jan-g/jdwp-client/jdwptest"
+)
+
+func budgeted(t *testing.T, a *jdwptest.Agent, budget time.Duration) client.Client {
+	c, err := client.New(a.Pipe(), client.WithSuspendBudget(budget), client.WithLogger(client.NopLogger))
+	require.NoError(t, err)
+	return c
+}
+
+func expired(t *testing.T, c client.Client) *client.EventSuspensionExpired {
+	comp := composite(t, c)
+	require.Len(t, comp.Events, 1)
+	e, ok := comp.Events[0].(*client.EventSuspensionExpired)
+	require.True(t, ok, "%+v", comp.Events[0])
+	return e
+}
+
+func TestSuspendBudgetResumesEvents(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(p.vm)
+	c := budgeted(t, a, 100*time.Millisecond)
+	defer c.Close()
+
+	run := method(t, c, classBySignature(t, c, "Lcom/example/Main;"), "run")
+	_, err := client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyEventThread).
+		WithMod(client.ModKindLocation).WithLocation(client.NewLocation(run.MethodId, 8)).
+		Set(c)
+	require.NoError(t, err)
+
+	start := time.Now()
+	assert.Equal(t, 1, a.Breakpoint(p.thread))
+	assert.Equal(t, 1, p.thread.SuspendCount())
+	_, ok := composite(t, c).Events[0].(*client.EventBreakpoint)
+	assert.True(t, ok)
+
+	e := expired(t, c)
+	assert.Equal(t, p.thread.Id, e.Thread)
+	assert.True(t, e.Held >= 100*time.Millisecond, "held for %v", e.Held)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	assert.Equal(t, 0, p.thread.SuspendCount())
+}
+
+func TestSuspendBudgetResumesCommands(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	c := budgeted(t, jdwptest.NewAgent(p.vm), 100*time.Millisecond)
+	defer c.Close()
+
+	// A thread resumed in time is not reported
+	thread := c.IDSizes().Seq().ThreadId(p.thread.Id).Marshal()
+	_, err := c.Call(client.Thread, client.ThreadSuspend, thread)
+	require.NoError(t, err)
+	_, err = c.Call(client.Thread, client.ThreadResume, thread)
+	require.NoError(t, err)
+
+	_, err = c.Call(client.VirtualMachine, client.VirtualMachineSuspend, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, p.thread.SuspendCount())
+	e := expired(t, c)
+	assert.Equal(t, client.ThreadId(0), e.Thread)
+	assert.Equal(t, 0, p.thread.SuspendCount())
+	noEvent(t, c)
+}
+
+func TestFailedSuspendIsNotResumed(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(p.vm)
+
+	// Another debugger has suspended the VM, and it cannot be suspended again
+	other := connect(t, a)
+	defer other.Close()
+	_, err := other.Call(client.VirtualMachine, client.VirtualMachineSuspend, nil)
+	require.NoError(t, err)
+	a.Handle(client.VirtualMachine, client.VirtualMachineSuspend, func(s *jdwptest.Session, p *jdwptest.Packet) ([]byte, error) {
+		return nil, client.ErrNotImplemented
+	})
+
+	c := budgeted(t, a, time.Hour)
+	assert.Equal(t, client.ErrNotImplemented, client.VirtualMachineSuspendRequest{}.Call(c))
+	assert.Equal(t, client.ErrInvalidThread, client.ThreadReferenceSuspendRequest{Thread: 999}.Call(c))
+
+	// Closing resumes nothing, leaving the other debugger's suspension alone
+	require.NoError(t, c.Close())
+	assert.Equal(t, 1, p.thread.SuspendCount())
+}
+
+func TestCloseReleasesVM(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(p.vm)
+	c := budgeted(t, a, time.Hour)
+
+	run := method(t, c, classBySignature(t, c, "Lcom/example/Main;"), "run")
+	for _, policy := range []client.SuspendPolicy{client.SuspendPolicyEventThread, client.SuspendPolicyAll} {
+		_, err := client.NewEventRequestSet(client.EventKindBreakpoint, policy).
+			WithMod(client.ModKindLocation).WithLocation(client.NewLocation(run.MethodId, 8)).
+			Set(c)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, a.Breakpoint(p.thread))
+	_, err := c.Call(client.Thread, client.ThreadSuspend, c.IDSizes().Seq().ThreadId(p.thread.Id).Marshal())
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.thread.SuspendCount())
+	assert.Len(t, p.vm.Requests(), 2)
+
+	require.NoError(t, c.Close())
+	assert.Equal(t, 0, p.thread.SuspendCount())
+	assert.Empty(t, p.vm.Requests())
+}
+
+// slowResume holds up the first VirtualMachine.Resume, saying when it has started.
+type slowResume struct {
+	once    sync.Once
+	started chan struct{}
+}
+
+func (p *slowResume) Permit(set client.CommandSet, cmd client.Command, data []byte) error {
+	if set == client.VirtualMachine && cmd == client.VirtualMachineResume {
+		p.once.Do(func() {
+			close(p.started)
+			time.Sleep(100 * time.Millisecond)
+		})
+	}
+	return nil
+}
+
+func TestCloseWhileExpiring(t *testing.T) {
+	p := newProgram(jdwptest.NewVM())
+	a := jdwptest.NewAgent(p.vm)
+	other := connect(t, a)
+	defer other.Close()
+	_, err := other.Call(client.VirtualMachine, client.VirtualMachineSuspend, nil)
+	require.NoError(t, err)
+
+	slow := &slowResume{started: make(chan struct{})}
+	c, err := client.New(a.Pipe(), client.WithSuspendBudget(20*time.Millisecond),
+		client.WithPolicy(slow), client.WithLogger(client.NopLogger))
+	require.NoError(t, err)
+	require.NoError(t, client.VirtualMachineSuspendRequest{}.Call(c))
+	assert.Equal(t, 2, p.thread.SuspendCount())
+
+	// Closing as the suspension expires resumes it once, leaving the other debugger's
+	<-slow.started
+	require.NoError(t, c.Close())
+	assert.Equal(t, 1, p.thread.SuspendCount())
+}
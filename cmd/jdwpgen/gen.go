@@ -0,0 +1,676 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// fieldTypes are the Go types of the values in the specification.
+var fieldTypes = map[string]string{
+	"byte":              "uint8",
+	"boolean":           "bool",
+	"int":               "int",
+	"long":              "int64",
+	"string":            "string",
+	"object":            "ObjectId",
+	"threadObject":      "ThreadId",
+	"threadGroupObject": "ThreadGroupId",
+	"stringObject":      "StringId",
+	"classLoaderObject": "ClassLoaderId",
+	"classObject":       "ClassObjectId",
+	"arrayObject":       "ArrayId",
+	"moduleID":          "ModuleId",
+	"referenceType":     "ClassId",
+	"classType":         "ClassId",
+	"interfaceType":     "InterfaceId",
+	"arrayType":         "ArrayTypeId",
+	"field":             "FieldId",
+	"method":            "MethodId",
+	"frame":             "FrameId",
+	"location":          "Location",
+	"tagged-object":     "TaggedObjectId",
+	"value":             "TaggedValue",
+	"untagged-value":    "UntaggedValue",
+	"arrayregion":       "ArrayRegion",
+}
+
+// idTypes are the types of the values that a command can be sent as a method of.
+var idTypes = map[string]bool{
+	"object": true, "threadObject": true, "threadGroupObject": true, "stringObject": true,
+	"classLoaderObject": true, "classObject": true, "arrayObject": true, "moduleID": true,
+	"referenceType": true, "classType": true, "interfaceType": true, "arrayType": true,
+}
+
+// setReceivers override the type that the commands of a set are methods of, where the
+// specification declares it more loosely.
+var setReceivers = map[string]string{
+	"StringReference": "StringId",
+}
+
+// scope holds the names declared by the package's hand-written source, which the generated
+// code must not redeclare.
+type scope struct {
+	decls   map[string]bool
+	members map[string]map[string]bool // the methods and fields of each type
+}
+
+// scan reads the declarations of the package in dir, other than those of its tests and of
+// the file being generated.
+func scan(dir, generated string) (*scope, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sc := &scope{decls: map[string]bool{}, members: map[string]map[string]bool{}}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || filepath.Base(name) == filepath.Base(generated) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range f.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					sc.decls[d.Name.Name] = true
+					continue
+				}
+				t := d.Recv.List[0].Type
+				if star, ok := t.(*ast.StarExpr); ok {
+					t = star.X
+				}
+				if id, ok := t.(*ast.Ident); ok {
+					sc.member(id.Name, d.Name.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						sc.decls[spec.Name.Name] = true
+						if st, ok := spec.Type.(*ast.StructType); ok {
+							for _, field := range st.Fields.List {
+								for _, n := range field.Names {
+									sc.member(spec.Name.Name, n.Name)
+								}
+							}
+						}
+					case *ast.ValueSpec:
+						for _, n := range spec.Names {
+							sc.decls[n.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return sc, nil
+}
+
+func (sc *scope) member(typ, name string) {
+	if sc.members[typ] == nil {
+		sc.members[typ] = map[string]bool{}
+	}
+	sc.members[typ][name] = true
+}
+
+type generator struct {
+	spec    *Spec
+	scope   *scope
+	out     bytes.Buffer
+	names   map[string]bool
+	methods map[string]map[string]bool
+	sets    map[*CommandSet]string
+	cmds    map[*Command]string
+	types   map[*Item]string
+	selects []selectType
+}
+
+// selectType is an interface generated for a Select, to be registered with its alternatives.
+type selectType struct {
+	name  string
+	table string // the variable holding the alternatives
+	alts  map[int]string
+}
+
+// Generate writes the Go source of package pkg for spec, avoiding the names declared in sc.
+func Generate(spec *Spec, sc *scope, pkg string) ([]byte, error) {
+	g := &generator{
+		spec:    spec,
+		scope:   sc,
+		names:   map[string]bool{},
+		methods: map[string]map[string]bool{},
+		sets:    map[*CommandSet]string{},
+		cmds:    map[*Command]string{},
+		types:   map[*Item]string{},
+	}
+	g.printf("// Code generated by jdwpgen from the JDWP specification. DO NOT EDIT.\n\n")
+	g.printf("package %s\n", pkg)
+	if err := g.constants(); err != nil {
+		return nil, err
+	}
+	if err := g.errors(); err != nil {
+		return nil, err
+	}
+	// Reserve the names of the commands' own types before those of the types they contain
+	for _, cs := range spec.CommandSets {
+		for _, cmd := range cs.Commands {
+			suffixes := []string{"Request"}
+			if cmd.IsEvent {
+				suffixes = []string{"Event"}
+			} else if len(cmd.Reply) > 0 {
+				suffixes = append(suffixes, "Reply")
+			}
+			for _, suffix := range suffixes {
+				if err := g.reserve(cs.Name + cmd.Name + suffix); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, cs := range spec.CommandSets {
+		for _, cmd := range cs.Commands {
+			if err := g.command(cs, cmd); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", cs.Name, cmd.Name, err)
+			}
+		}
+	}
+	if err := g.replies(); err != nil {
+		return nil, err
+	}
+	g.register()
+	src, err := format.Source(g.out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, g.out.Bytes())
+	}
+	return src, nil
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.out, format, args...)
+}
+
+// declare returns name, or name with suffix if the package already declares name.
+func (g *generator) declare(name, suffix string) (string, error) {
+	if g.scope.decls[name] || g.names[name] {
+		name += suffix
+	}
+	return name, g.reserve(name)
+}
+
+func (g *generator) reserve(name string) error {
+	if g.scope.decls[name] || g.names[name] {
+		return fmt.Errorf("%s is already declared", name)
+	}
+	g.names[name] = true
+	return nil
+}
+
+func (g *generator) constants() error {
+	for _, cs := range g.spec.CommandSets {
+		name, err := g.declare(cs.Name, "CommandSet")
+		if err != nil {
+			return err
+		}
+		g.sets[cs] = name
+		g.printf("\n// The %s command set.\nconst (\n\t%s = CommandSet(%d)\n", cs.Name, name, cs.Id)
+		for _, cmd := range cs.Commands {
+			cname, err := g.declare(cs.Name+cmd.Name, "Command")
+			if err != nil {
+				return err
+			}
+			g.cmds[cmd] = cname
+			g.printf("\t%s = Command(%d)\n", cname, cmd.Id)
+		}
+		g.printf(")\n")
+	}
+	for _, name := range []string{"specCommands", "specEvents", "CommandName"} {
+		if err := g.reserve(name); err != nil {
+			return err
+		}
+	}
+	g.printf("\n// specCommands names every command that a debugger can send.\nvar specCommands = map[CommandKey]string{\n")
+	g.commandNames(false)
+	g.printf("}\n")
+	g.printf("\n// specEvents names the commands that the VM sends.\nvar specEvents = map[CommandKey]string{\n")
+	g.commandNames(true)
+	g.printf("}\n")
+	g.printf(`
+// CommandName is the specification's name for a command, such as "VirtualMachine.Version",
+// or "" if the specification has no such command.
+func CommandName(set CommandSet, cmd Command) string {
+	if name, ok := specCommands[CommandKey{set, cmd}]; ok {
+		return name
+	}
+	return specEvents[CommandKey{set, cmd}]
+}
+`)
+	return nil
+}
+
+// commandNames writes the entries naming the commands sent by the VM, or those sent to it.
+func (g *generator) commandNames(events bool) {
+	for _, cs := range g.spec.CommandSets {
+		for _, cmd := range cs.Commands {
+			if cmd.IsEvent == events {
+				g.printf("\t{%s, %s}: %q,\n", g.sets[cs], g.cmds[cmd], cs.Name+"."+cmd.Name)
+			}
+		}
+	}
+}
+
+// replies writes NewReply, which makes the value that each command's reply is parsed into.
+func (g *generator) replies() error {
+	for _, name := range []string{"specReplies", "NewReply"} {
+		if err := g.reserve(name); err != nil {
+			return err
+		}
+	}
+	g.printf("\n// specReplies make the values that the replies to commands are parsed into.\n")
+	g.printf("var specReplies = map[CommandKey]func() interface{}{\n")
+	for _, cs := range g.spec.CommandSets {
+		for _, cmd := range cs.Commands {
+			if !cmd.IsEvent && len(cmd.Reply) > 0 {
+				g.printf("\t{%s, %s}: func() interface{} { return &%sReply{} },\n", g.sets[cs], g.cmds[cmd], cs.Name+cmd.Name)
+			}
+		}
+	}
+	g.printf("}\n")
+	g.printf(`
+// NewReply returns a new value of the type that the reply to a command is parsed into, or nil
+// if the command has no reply body or the specification has no such command.
+func NewReply(set CommandSet, cmd Command) interface{} {
+	if f, ok := specReplies[CommandKey{set, cmd}]; ok {
+		return f()
+	}
+	return nil
+}
+`)
+	return nil
+}
+
+func (g *generator) errors() error {
+	var errs *ConstantSet
+	for _, cs := range g.spec.ConstantSets {
+		if cs.Name == "Error" {
+			errs = cs
+		}
+	}
+	if errs == nil {
+		return nil
+	}
+	g.printf("\n// The errors of the JDWP specification, returned for the error codes of replies.\nvar (\n")
+	for _, c := range errs.Constants {
+		if c.Value == 0 {
+			continue
+		}
+		name := "Err" + camel(c.Name)
+		if err := g.reserve(name); err != nil {
+			return err
+		}
+		if d := sentence(c.Doc); d != "" {
+			g.printf("%s", indent(comment(d)))
+		}
+		g.printf("\t%s = err(%d, %q)\n", name, c.Value, humanise(c.Name))
+	}
+	g.printf(")\n")
+	return nil
+}
+
+func (g *generator) command(cs *CommandSet, cmd *Command) error {
+	prefix := cs.Name + cmd.Name
+	if cmd.IsEvent {
+		doc := fmt.Sprintf("%sEvent is the %s.%s command, sent by the VM. %s", prefix, cs.Name, cmd.Name, cmd.Doc)
+		return g.structType(prefix+"Event", doc, cmd.Event, prefix)
+	}
+	doc := fmt.Sprintf("%sRequest is the %s.%s command. %s", prefix, cs.Name, cmd.Name, cmd.Doc)
+	if len(cmd.Errors) > 0 {
+		var errs []string
+		for _, e := range cmd.Errors {
+			errs = append(errs, "Err"+camel(e))
+		}
+		doc += " It can fail with " + strings.Join(errs, ", ") + "."
+	}
+	if err := g.structType(prefix+"Request", doc, cmd.Out, prefix); err != nil {
+		return err
+	}
+	hasReply := len(cmd.Reply) > 0
+	if hasReply {
+		doc := fmt.Sprintf("%sReply is the reply to %s.%s.", prefix, cs.Name, cmd.Name)
+		if err := g.structType(prefix+"Reply", doc, cmd.Reply, prefix); err != nil {
+			return err
+		}
+	}
+
+	set, command := g.sets[cs], g.cmds[cmd]
+	g.printf("\n// Call sends the command, returning its reply.\n")
+	if hasReply {
+		g.printf("func (r %[1]sRequest) Call(c Client) (*%[1]sReply, error) {\n", prefix)
+		g.printf("\tvar reply %sReply\n", prefix)
+		g.printf("\tif err := request(c, %s, %s, r, &reply); err != nil {\n\t\treturn nil, err\n\t}\n", set, command)
+		g.printf("\treturn &reply, nil\n}\n")
+	} else {
+		g.printf("func (r %sRequest) Call(c Client) error {\n", prefix)
+		g.printf("\treturn request(c, %s, %s, r, nil)\n}\n", set, command)
+	}
+	return g.method(cs, cmd)
+}
+
+// method sends a command as a method of the ID, or pair of IDs, that is its first argument.
+func (g *generator) method(cs *CommandSet, cmd *Command) error {
+	out := cmd.Out
+	if len(out) == 0 || out[0].Kind != Field || !idTypes[out[0].Type] {
+		return nil
+	}
+	var recv, name string
+	var args []string
+	switch {
+	case len(out) > 1 && out[0].Type == "referenceType" && out[1].Kind == Field && out[1].Type == "method":
+		recv, name = "MethodId", "m"
+		args = []string{"m.ref", "m"}
+	case len(out) > 1 && out[0].Type == "threadObject" && out[1].Kind == Field && out[1].Type == "frame":
+		recv, name = "Frame", "f"
+		args = []string{"f.thr", "f.FrameId"}
+	default:
+		recv, name = fieldTypes[out[0].Type], "id"
+		args = []string{"id"}
+		if r, ok := setReceivers[cs.Name]; ok {
+			args = []string{fmt.Sprintf("%s(id)", recv)}
+			recv = r
+		}
+	}
+
+	method := cmd.Name
+	if g.taken(recv, method) {
+		method = cs.Name + cmd.Name
+		if g.taken(recv, method) {
+			return fmt.Errorf("%s.%s is already declared", recv, method)
+		}
+	}
+	if g.methods[recv] == nil {
+		g.methods[recv] = map[string]bool{}
+	}
+	g.methods[recv][method] = true
+
+	var params, fields []string
+	for i, item := range out {
+		field := exported(item.Name)
+		if i < len(args) {
+			fields = append(fields, fmt.Sprintf("%s: %s", field, args[i]))
+			continue
+		}
+		param := unexported(field)
+		if token.IsKeyword(param) || param == "c" || param == name {
+			param += "Arg"
+		}
+		typ, err := g.goType(item, cs.Name+cmd.Name)
+		if err != nil {
+			return err
+		}
+		if item.Kind == Repeat {
+			typ = "[]" + typ
+		}
+		params = append(params, fmt.Sprintf("%s %s", param, typ))
+		fields = append(fields, fmt.Sprintf("%s: %s", field, param))
+	}
+	prefix := cs.Name + cmd.Name
+	g.printf("\n%s", comment(fmt.Sprintf("%s sends %s.%s. %s", method, cs.Name, cmd.Name, sentence(cmd.Doc))))
+	ret := "error"
+	if len(cmd.Reply) > 0 {
+		ret = fmt.Sprintf("(*%sReply, error)", prefix)
+	}
+	g.printf("func (%s %s) %s(%s) %s {\n", name, recv, method, strings.Join(append([]string{"c Client"}, params...), ", "), ret)
+	g.printf("\treturn %sRequest{%s}.Call(c)\n}\n", prefix, strings.Join(fields, ", "))
+	return nil
+}
+
+func (g *generator) taken(recv, method string) bool {
+	return g.scope.members[recv][method] || g.methods[recv][method]
+}
+
+// structType writes a struct of items, followed by the types that its fields need.
+func (g *generator) structType(name, doc string, items []*Item, prefix string) error {
+	var nested []func() error
+	if len(items) == 0 {
+		g.printf("\n%stype %s struct{}\n", comment(doc), name)
+		return nil
+	}
+	g.printf("\n%stype %s struct {\n", comment(doc), name)
+	for _, item := range items {
+		field := exported(item.Name)
+		typ, err := g.goType(item, prefix)
+		if err != nil {
+			return err
+		}
+		switch item.Kind {
+		case Repeat:
+			counter := "Num" + field
+			g.printf("\t%s int%s\n", counter, trailing(item.Doc))
+			g.printf("\t%s []%s `jdwp:\"counter:%s\"`\n", field, typ, counter)
+		default:
+			g.printf("\t%s %s%s\n", field, typ, trailing(item.Doc))
+		}
+		elem := item
+		if item.Kind == Repeat {
+			elem = item.Elem
+		}
+		switch elem.Kind {
+		case Group:
+			e := elem
+			nested = append(nested, func() error {
+				return g.structType(typ, fmt.Sprintf("%s is one of the %s of %s.", typ, field, name), e.Items, prefix)
+			})
+		case Select:
+			e := elem
+			nested = append(nested, func() error { return g.selectType(typ, name, field, e, prefix) })
+		}
+	}
+	g.printf("}\n")
+	for _, n := range nested {
+		if err := n(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) selectType(name, parent, field string, item *Item, prefix string) error {
+	g.printf("\n%s", comment(fmt.Sprintf("%s is one of the %s of %s, each preceded by the %s selecting it. %s",
+		name, field, parent, item.Name, item.Doc)))
+	g.printf("type %s interface {\n\talternative() uint8\n}\n", name)
+	st := selectType{name: name, table: unexported(name) + "Alternatives", alts: map[int]string{}}
+	if err := g.reserve(st.table); err != nil {
+		return err
+	}
+	for _, alt := range item.Alts {
+		altName := prefix + alt.Name
+		if err := g.reserve(altName); err != nil {
+			return err
+		}
+		st.alts[alt.Value] = altName
+		doc := fmt.Sprintf("%s is the %s of %s %d. %s", altName, name, item.Name, alt.Value, alt.Doc)
+		if err := g.structType(altName, doc, alt.Items, prefix); err != nil {
+			return err
+		}
+		g.printf("\nfunc (*%s) alternative() uint8 {\n\treturn %d\n}\n", altName, alt.Value)
+	}
+	g.selects = append(g.selects, st)
+	return nil
+}
+
+// goType returns the Go type of an item, or of the elements of a Repeat. The types of Groups
+// and Selects are reserved, to be written by structType.
+func (g *generator) goType(item *Item, prefix string) (string, error) {
+	switch item.Kind {
+	case Field:
+		t, ok := fieldTypes[item.Type]
+		if !ok {
+			return "", fmt.Errorf("%s has unknown type %s", item.Name, item.Type)
+		}
+		return t, nil
+	case Repeat:
+		t, err := g.goType(item.Elem, prefix)
+		if item.Elem.Kind == Repeat {
+			// Each inner Repeat is preceded by its own count
+			t = "[]" + t
+		}
+		return t, err
+	default:
+		if name, ok := g.types[item]; ok {
+			return name, nil
+		}
+		name := prefix + exported(item.Name)
+		if g.names[name] {
+			// Such as a Group named Request, in the command's Request
+			name += "Group"
+		}
+		if err := g.reserve(name); err != nil {
+			return "", err
+		}
+		g.types[item] = name
+		return name, nil
+	}
+}
+
+// register adds the factories of the Selects' interfaces.
+func (g *generator) register() {
+	if len(g.selects) == 0 {
+		return
+	}
+	for _, st := range g.selects {
+		g.printf("\n%s", comment(fmt.Sprintf("%s are the alternatives of %s, by the byte selecting each.", st.table, st.name)))
+		g.printf("var %s = map[uint8]func() interface{}{\n", st.table)
+		var values []int
+		for v := range st.alts {
+			values = append(values, v)
+		}
+		sort.Ints(values)
+		for _, v := range values {
+			g.printf("\t%d: func() interface{} { return &%s{} },\n", v, st.alts[v])
+		}
+		g.printf("}\n")
+	}
+	g.printf("\nfunc init() {\n")
+	for _, st := range g.selects {
+		g.printf("\tRegisterFactory([]%s{}, alternatives(%s))\n", st.name, st.table)
+	}
+	g.printf("}\n")
+}
+
+var (
+	markup      = regexp.MustCompile(`<[^>]*>`)
+	punctuation = regexp.MustCompile(` ([.,;:)])`)
+)
+
+// plain strips the HTML from doc, leaving its words.
+func plain(doc string) []string {
+	text := strings.Join(strings.Fields(markup.ReplaceAllString(doc, " ")), " ")
+	return strings.Fields(punctuation.ReplaceAllString(text, "$1"))
+}
+
+// sentence returns the first sentence of a description.
+func sentence(doc string) string {
+	doc = strings.Join(plain(doc), " ")
+	if i := strings.Index(doc, ". "); i >= 0 {
+		doc = doc[:i+1]
+	}
+	return doc
+}
+
+func trailing(doc string) string {
+	if d := sentence(doc); d != "" {
+		return " // " + d
+	}
+	return ""
+}
+
+// comment wraps text as a doc comment.
+func comment(text string) string {
+	words := plain(text)
+	var b strings.Builder
+	line := "//"
+	for _, w := range words {
+		if len(line)+1+len(w) > 96 && line != "//" {
+			b.WriteString(line + "\n")
+			line = "//"
+		}
+		line += " " + w
+	}
+	if !strings.HasSuffix(line, ".") {
+		line += "."
+	}
+	b.WriteString(line + "\n")
+	return b.String()
+}
+
+func indent(text string) string {
+	return "\t" + strings.Replace(strings.TrimSuffix(text, "\n"), "\n", "\n\t", -1) + "\n"
+}
+
+// camel converts a constant's name, such as INVALID_METHODID, to InvalidMethodId.
+func camel(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(strings.ToLower(name), "_") {
+		if strings.HasSuffix(word, "id") && len(word) > 2 && idWords[word[:len(word)-2]] {
+			b.WriteString(upper(word[:len(word)-2]) + "Id")
+			continue
+		}
+		b.WriteString(upper(word))
+	}
+	return b.String()
+}
+
+// idWords are those that an ID is run into in the names of constants.
+var idWords = map[string]bool{"method": true, "field": true, "frame": true}
+
+// humanise converts a constant's name, such as INVALID_METHODID, to "invalid method id".
+func humanise(name string) string {
+	c := camel(name)
+	var words []string
+	start := 0
+	for i := 1; i <= len(c); i++ {
+		if i == len(c) || (c[i] >= 'A' && c[i] <= 'Z') {
+			words = append(words, strings.ToLower(c[start:i]))
+			start = i
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// exported converts a name from the specification, such as typeID or timed_out, to the name
+// of a Go field, such as TypeId or TimedOut.
+func exported(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		b.WriteString(upper(word))
+	}
+	s := b.String()
+	if strings.HasSuffix(s, "ID") && len(s) > 2 {
+		s = s[:len(s)-2] + "Id"
+	}
+	return s
+}
+
+func upper(word string) string {
+	if word == "" {
+		return ""
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+func unexported(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
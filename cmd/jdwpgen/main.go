@@ -0,0 +1,54 @@
+// Command jdwpgen generates the client package's model of the JDWP commands from the
+// specification, in the grammar of OpenJDK's jdwp.spec. It is run by go generate in the
+// client package:
+//
+//	go run ../cmd/jdwpgen -spec testdata/jdwp.spec -out spec-generated.go
+//
+// The names that the package already declares by hand are avoided: a command set or command
+// whose constant would clash gets a CommandSet or Command suffix, and a command whose method
+// would clash is named after its command set too.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	specFile := flag.String("spec", "jdwp.spec", "the JDWP specification")
+	out := flag.String("out", "spec-generated.go", "the Go file to write, in the package to generate for")
+	pkg := flag.String("pkg", "client", "the name of the package")
+	flag.Parse()
+
+	if err := run(*specFile, *out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "jdwpgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(specFile, out, pkg string) error {
+	src, err := generate(specFile, out, pkg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0644)
+}
+
+// generate returns the contents of out, generated from the specification in specFile.
+func generate(specFile, out, pkg string) ([]byte, error) {
+	text, err := os.ReadFile(specFile)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", specFile, err)
+	}
+	sc, err := scan(filepath.Dir(out), out)
+	if err != nil {
+		return nil, err
+	}
+	return Generate(spec, sc, pkg)
+}
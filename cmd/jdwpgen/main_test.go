@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const snippet = `
+JDWP "Java(tm) Debug Wire Protocol"
+(CommandSet Example=3
+    (Command Things=1 "Returns the things."
+        (Out
+            (referenceType refType "The type.")
+        )
+        (Reply
+            (Repeat things "The things."
+                (Group Thing
+                    (field fieldID "A field.")
+                    (string name "Its name.")
+                )
+            )
+            (Select kind "The kind."
+                (Alt Start=JDWP.Kind.START "Started."
+                    (int requestID "The request.")
+                )
+            )
+        )
+        (ErrorSet
+            (Error INVALID_CLASS)
+        )
+    )
+)
+(ConstantSet Kind
+    (Constant START = 90 "Started.")
+    (Constant CHAR = 'C' "A char.")
+)
+`
+
+func TestParse(t *testing.T) {
+	spec, err := Parse(snippet)
+	require.NoError(t, err)
+
+	require.Len(t, spec.ConstantSets, 1)
+	assert.Equal(t, []*Constant{{"START", 90, "Started."}, {"CHAR", 'C', "A char."}}, spec.ConstantSets[0].Constants)
+
+	require.Len(t, spec.CommandSets, 1)
+	cs := spec.CommandSets[0]
+	assert.Equal(t, "Example", cs.Name)
+	assert.Equal(t, 3, cs.Id)
+	require.Len(t, cs.Commands, 1)
+	cmd := cs.Commands[0]
+	assert.Equal(t, "Things", cmd.Name)
+	assert.Equal(t, "Returns the things.", cmd.Doc)
+	assert.Equal(t, []*Item{{Kind: Field, Type: "referenceType", Name: "refType", Doc: "The type."}}, cmd.Out)
+	assert.Equal(t, []string{"INVALID_CLASS"}, cmd.Errors)
+
+	require.Len(t, cmd.Reply, 2)
+	things := cmd.Reply[0]
+	assert.Equal(t, Repeat, things.Kind)
+	assert.Equal(t, Group, things.Elem.Kind)
+	assert.Len(t, things.Elem.Items, 2)
+	kind := cmd.Reply[1]
+	assert.Equal(t, Select, kind.Kind)
+	require.Len(t, kind.Alts, 1)
+	assert.Equal(t, 90, kind.Alts[0].Value)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		`(CommandSet Example=3`,
+		`(ConstantSet Kind (Constant START = JDWP.Kind.STOP))`,
+		`(CommandSet Example=3 (Command Things=1 (Sideways)))`,
+		`(CommandSet Example=3 (Command Things=1 (Out (int "unnamed"))))`,
+	} {
+		_, err := Parse(src)
+		assert.Error(t, err, src)
+	}
+}
+
+func TestNames(t *testing.T) {
+	for in, out := range map[string]string{
+		"INVALID_THREAD":   "InvalidThread",
+		"INVALID_METHODID": "InvalidMethodId",
+		"VM_DEAD":          "VmDead",
+	} {
+		assert.Equal(t, out, camel(in), in)
+	}
+	for in, out := range map[string]string{
+		"refTypeID":   "RefTypeId",
+		"thread":      "Thread",
+		"class_count": "ClassCount",
+	} {
+		assert.Equal(t, out, exported(in), in)
+	}
+}
+
+func TestGeneratedUpToDate(t *testing.T) {
+	src, err := generate("../../client/testdata/jdwp.spec", "../../client/spec-generated.go", "client")
+	require.NoError(t, err)
+	old, err := os.ReadFile("../../client/spec-generated.go")
+	require.NoError(t, err)
+	if string(src) != string(old) {
+		t.Error("client/spec-generated.go is out of date; run go generate in client")
+	}
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Spec is a JDWP specification, as written in the grammar of OpenJDK's jdwp.spec. A
+// specification is a series of lists:
+//
+//	(CommandSet Name=id (Command Name=id "doc" (Out ...) (Reply ...) (ErrorSet ...)))
+//	(ConstantSet Name (Constant NAME=value "doc") ...)
+//
+// whose commands' arguments and replies are made of
+//
+//	(type name "doc")                   a single value
+//	(Repeat name "doc" element)         a count, followed by that many elements
+//	(Group Name element...)             a structure of several values
+//	(Select name "doc" (Alt Name=value "doc" element...)...)
+//	                                    a byte, followed by the alternative it selects
+type Spec struct {
+	CommandSets  []*CommandSet
+	ConstantSets []*ConstantSet
+}
+
+type CommandSet struct {
+	Name     string
+	Id       int
+	Commands []*Command
+}
+
+type Command struct {
+	Name    string
+	Id      int
+	Doc     string
+	Out     []*Item
+	Reply   []*Item
+	Event   []*Item // the body of a command sent by the VM, which has no Out or Reply
+	IsEvent bool
+	Errors  []string
+}
+
+// Item is one of the values making up a command or reply: a Field, Repeat, Group or Select.
+type Item struct {
+	Kind  ItemKind
+	Type  string // of a Field
+	Name  string
+	Doc   string
+	Elem  *Item   // repeated by a Repeat
+	Items []*Item // of a Group
+	Alts  []*Alt  // of a Select
+}
+
+type ItemKind int
+
+const (
+	Field ItemKind = iota
+	Repeat
+	Group
+	Select
+)
+
+type Alt struct {
+	Name  string
+	Value int
+	Doc   string
+	Items []*Item
+}
+
+type ConstantSet struct {
+	Name      string
+	Constants []*Constant
+}
+
+type Constant struct {
+	Name  string
+	Value int
+	Doc   string
+}
+
+// node is a parenthesised list, a word or a string of the specification.
+type node struct {
+	list  []*node
+	word  string
+	str   string
+	isStr bool
+	line  int
+}
+
+func (n *node) isList() bool {
+	return n.list != nil
+}
+
+func (n *node) head() string {
+	if len(n.list) == 0 {
+		return ""
+	}
+	return n.list[0].word
+}
+
+// Parse reads a specification.
+func Parse(src string) (*Spec, error) {
+	nodes, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &specParser{spec: &Spec{}}
+	// Constants are resolved first, since an Alt may refer to one defined later
+	for _, n := range nodes {
+		if n.isList() && n.head() == "ConstantSet" {
+			cs, err := p.constantSet(n)
+			if err != nil {
+				return nil, err
+			}
+			p.spec.ConstantSets = append(p.spec.ConstantSets, cs)
+		}
+	}
+	for _, n := range nodes {
+		switch {
+		case !n.isList():
+			// The title: JDWP "Java(tm) Debug Wire Protocol"
+		case n.head() == "CommandSet":
+			cs, err := p.commandSet(n)
+			if err != nil {
+				return nil, err
+			}
+			p.spec.CommandSets = append(p.spec.CommandSets, cs)
+		case n.head() == "ConstantSet":
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", n.line, n.head())
+		}
+	}
+	return p.spec, nil
+}
+
+func tokenize(src string) ([]*node, error) {
+	var stack [][]*node
+	var top []*node
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '(':
+			stack = append(stack, top)
+			top = nil
+			i++
+		case c == ')':
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced )", line)
+			}
+			list := top
+			if list == nil {
+				list = []*node{}
+			}
+			top = append(stack[len(stack)-1], &node{list: list, line: line})
+			stack = stack[:len(stack)-1]
+			i++
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				if src[j] == '\n' {
+					line++
+				}
+				b.WriteByte(src[j])
+			}
+			if j == len(src) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			top = append(top, &node{str: b.String(), isStr: true, line: line})
+			i = j + 1
+		case c == '\'':
+			if i+2 >= len(src) || src[i+2] != '\'' {
+				return nil, fmt.Errorf("line %d: bad character literal", line)
+			}
+			top = append(top, &node{word: strconv.Itoa(int(src[i+1])), line: line})
+			i += 3
+		case c == '=':
+			top = append(top, &node{word: "=", line: line})
+			i++
+		default:
+			j := i
+			for j < len(src) && !strings.ContainsRune(" \t\r\n()\"'=", rune(src[j])) {
+				j++
+			}
+			top = append(top, &node{word: src[i:j], line: line})
+			i = j
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced (", line)
+	}
+	return top, nil
+}
+
+type specParser struct {
+	spec *Spec
+}
+
+// definition reads Name=value from the start of args, returning the rest.
+func (p *specParser) definition(args []*node) (string, int, []*node, error) {
+	if len(args) < 3 || args[1].word != "=" || args[0].isStr || args[0].isList() {
+		line := 0
+		if len(args) > 0 {
+			line = args[0].line
+		}
+		return "", 0, nil, fmt.Errorf("line %d: expected Name=value", line)
+	}
+	v, err := p.value(args[2])
+	return args[0].word, v, args[3:], err
+}
+
+// value reads a number, a character or a reference such as JDWP.EventKind.VM_START.
+func (p *specParser) value(n *node) (int, error) {
+	if strings.HasPrefix(n.word, "JDWP.") {
+		parts := strings.Split(n.word, ".")
+		if len(parts) == 3 {
+			for _, cs := range p.spec.ConstantSets {
+				if cs.Name != parts[1] {
+					continue
+				}
+				for _, c := range cs.Constants {
+					if c.Name == parts[2] {
+						return c.Value, nil
+					}
+				}
+			}
+		}
+		return 0, fmt.Errorf("line %d: unknown constant %s", n.line, n.word)
+	}
+	v, err := strconv.ParseInt(n.word, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: bad value %q", n.line, n.word)
+	}
+	return int(v), nil
+}
+
+// doc joins the strings at the start of args, returning the rest.
+func doc(args []*node) (string, []*node) {
+	var parts []string
+	for len(args) > 0 && args[0].isStr {
+		parts = append(parts, args[0].str)
+		args = args[1:]
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " "), args
+}
+
+func (p *specParser) constantSet(n *node) (*ConstantSet, error) {
+	if len(n.list) < 2 {
+		return nil, fmt.Errorf("line %d: ConstantSet has no name", n.line)
+	}
+	cs := &ConstantSet{Name: n.list[1].word}
+	_, rest := doc(n.list[2:])
+	for _, c := range rest {
+		if c.head() != "Constant" {
+			return nil, fmt.Errorf("line %d: expected Constant", c.line)
+		}
+		name, v, args, err := p.definition(c.list[1:])
+		if err != nil {
+			return nil, err
+		}
+		d, _ := doc(args)
+		cs.Constants = append(cs.Constants, &Constant{Name: name, Value: v, Doc: d})
+	}
+	return cs, nil
+}
+
+func (p *specParser) commandSet(n *node) (*CommandSet, error) {
+	name, id, rest, err := p.definition(n.list[1:])
+	if err != nil {
+		return nil, err
+	}
+	cs := &CommandSet{Name: name, Id: id}
+	for _, c := range rest {
+		if c.head() != "Command" {
+			return nil, fmt.Errorf("line %d: expected Command", c.line)
+		}
+		cmd, err := p.command(c)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%w", name, err)
+		}
+		cs.Commands = append(cs.Commands, cmd)
+	}
+	return cs, nil
+}
+
+func (p *specParser) command(n *node) (*Command, error) {
+	name, id, rest, err := p.definition(n.list[1:])
+	if err != nil {
+		return nil, err
+	}
+	cmd := &Command{Name: name, Id: id}
+	cmd.Doc, rest = doc(rest)
+	for _, part := range rest {
+		var items []*Item
+		switch part.head() {
+		case "Out", "Reply", "Event":
+			args := part.list[1:]
+			if part.head() == "Event" {
+				_, args = doc(args)
+			}
+			if items, err = p.items(args); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		case "ErrorSet":
+			for _, e := range part.list[1:] {
+				if e.head() != "Error" || len(e.list) < 2 {
+					return nil, fmt.Errorf("%s: line %d: expected Error", name, e.line)
+				}
+				cmd.Errors = append(cmd.Errors, e.list[1].word)
+			}
+			continue
+		default:
+			return nil, fmt.Errorf("%s: line %d: unexpected %q", name, part.line, part.head())
+		}
+		switch part.head() {
+		case "Out":
+			cmd.Out = items
+		case "Reply":
+			cmd.Reply = items
+		case "Event":
+			cmd.Event, cmd.IsEvent = items, true
+		}
+	}
+	return cmd, nil
+}
+
+func (p *specParser) items(nodes []*node) ([]*Item, error) {
+	items := []*Item{}
+	for _, n := range nodes {
+		item, err := p.item(n)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (p *specParser) item(n *node) (*Item, error) {
+	if !n.isList() || len(n.list) < 2 {
+		return nil, fmt.Errorf("line %d: expected an item", n.line)
+	}
+	args := n.list[2:]
+	switch n.head() {
+	case "Repeat":
+		item := &Item{Kind: Repeat, Name: n.list[1].word}
+		item.Doc, args = doc(args)
+		if len(args) != 1 {
+			return nil, fmt.Errorf("line %d: Repeat %s should have one item", n.line, item.Name)
+		}
+		elem, err := p.item(args[0])
+		item.Elem = elem
+		return item, err
+	case "Group":
+		item := &Item{Kind: Group, Name: n.list[1].word}
+		item.Doc, args = doc(args)
+		items, err := p.items(args)
+		item.Items = items
+		return item, err
+	case "Select":
+		item := &Item{Kind: Select, Name: n.list[1].word}
+		item.Doc, args = doc(args)
+		for _, a := range args {
+			if a.head() != "Alt" {
+				return nil, fmt.Errorf("line %d: expected Alt", a.line)
+			}
+			name, v, rest, err := p.definition(a.list[1:])
+			if err != nil {
+				return nil, err
+			}
+			alt := &Alt{Name: name, Value: v}
+			alt.Doc, rest = doc(rest)
+			if alt.Items, err = p.items(rest); err != nil {
+				return nil, err
+			}
+			item.Alts = append(item.Alts, alt)
+		}
+		return item, nil
+	}
+	if n.list[0].isStr || !isIdent(n.list[1].word) {
+		return nil, fmt.Errorf("line %d: expected (type name \"doc\")", n.line)
+	}
+	item := &Item{Kind: Field, Type: n.list[0].word, Name: n.list[1].word}
+	item.Doc, _ = doc(args)
+	return item, nil
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/jan-g/jdwp-client/client"
+)
+
+// commandName names a command as the specification does, or by its numbers if it is not
+// in the specification.
+func commandName(k client.CommandKey) string {
+	if name := client.CommandName(k.Set, k.Command); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%d.%d", k.Set, k.Command)
+}
+
+// dump prints a capture written with -record, decoding the packets it understands.
+func dump(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sizes := client.DefaultIDSizes
+	sent := map[client.Id]client.CommandKey{}
+	r := client.NewCaptureReader(f)
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var key client.CommandKey
+		var v interface{}
+		if e.IsReply() {
+			key = sent[e.Id]
+			delete(sent, e.Id)
+			v = client.NewReply(key.Set, key.Command)
+			fmt.Printf("%s %-3s id=%d flags=%#x reply to %s errCode=%d\n",
+				e.Time.Format("15:04:05.000000"), e.Direction, e.Id, e.Flags, commandName(key), e.ErrCode)
+		} else {
+			key = client.CommandKey{Set: e.CommandSet, Command: e.Command}
+			if e.Direction == client.Outgoing {
+				sent[e.Id] = key
+			} else if key.Set == client.EventCommandSet && key.Command == client.CompositeCommands {
+				v = &client.Composite{}
+			}
+			fmt.Printf("%s %-3s id=%d flags=%#x %s\n",
+				e.Time.Format("15:04:05.000000"), e.Direction, e.Id, e.Flags, commandName(key))
+		}
+		fmt.Printf("    % x\n", e.Packet)
+
+		if v == nil || e.ErrCode != 0 {
+			continue
+		}
+		if err := sizes.Parse(e.Data(), v); err != nil {
+			fmt.Printf("    (could not decode: %v)\n", err)
+			continue
+		}
+		switch v := v.(type) {
+		case *client.Composite:
+			fmt.Printf("    SuspendPolicy:%d NumEvents:%d\n", v.SuspendPolicy, v.NumEvents)
+			for _, event := range v.Events {
+				fmt.Printf("    %T %+v\n", event, event)
+			}
+		case *client.VirtualMachineIDSizesReply:
+			sizes = client.IDSizes(*v)
+			fmt.Printf("    %+v\n", *v)
+		default:
+			fmt.Printf("    %+v\n", reflect.ValueOf(v).Elem().Interface())
+		}
+	}
+}
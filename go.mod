@@ -1,8 +1,16 @@
 module github.com/jan-g/jdwp-client
 
-go 1.12
+go 1.18
 
 require (
 	github.com/sirupsen/logrus v1.4.0
 	github.com/stretchr/testify v1.2.2
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/konsorten/go-windows-terminal-sequences v1.0.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.0.0-20180904163835-0709b304e793 // indirect
+	golang.org/x/sys v0.0.0-20180905080454-ebe1bf3edb33 // indirect
+)
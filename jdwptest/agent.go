@@ -0,0 +1,235 @@
+// Package jdwptest provides a fake JDWP agent, serving a small fake VM, for testing code
+// built on the client package without a real JVM.
+package jdwptest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/jan-g/jdwp-client/client"
+)
+
+// Packet is a command received from a debugger.
+type Packet struct {
+	Id      client.Id
+	Set     client.CommandSet
+	Command client.Command
+	Data    []byte
+}
+
+// Handler answers a command with a reply body. An error should be a client.JdwpError,
+// whose code is returned to the debugger; any other error is reported as ErrInternal.
+type Handler func(s *Session, p *Packet) ([]byte, error)
+
+type key struct {
+	set client.CommandSet
+	cmd client.Command
+}
+
+// Agent serves the VM's end of JDWP connections. Commands are answered by the handler
+// registered for them; by default, those answer from the fake VM.
+type Agent struct {
+	VM       *VM
+	mu       sync.Mutex
+	handlers map[key]Handler
+	sessions map[*Session]bool
+	attached func(s *Session)
+}
+
+func NewAgent(vm *VM) *Agent {
+	a := &Agent{
+		VM:       vm,
+		handlers: map[key]Handler{},
+		sessions: map[*Session]bool{},
+	}
+	for k, h := range vmHandlers {
+		a.handlers[k] = h
+	}
+	return a
+}
+
+// Handle replaces the handler for a command.
+func (a *Agent) Handle(set client.CommandSet, cmd client.Command, h Handler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.handlers[key{set, cmd}] = h
+}
+
+// OnAttach sets a function to be called as each debugger completes the handshake, before its
+// commands are answered: for instance, to send VMStart.
+func (a *Agent) OnAttach(f func(s *Session)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.attached = f
+}
+
+func (a *Agent) handler(set client.CommandSet, cmd client.Command) Handler {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.handlers[key{set, cmd}]
+}
+
+// Pipe returns the debugger's end of an in-memory connection to the agent.
+func (a *Agent) Pipe() net.Conn {
+	debugger, vm := net.Pipe()
+	go a.ServeConn(vm)
+	return debugger
+}
+
+// Serve answers every connection accepted by l, until it is closed.
+func (a *Agent) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go a.ServeConn(conn)
+	}
+}
+
+// Attach connects to a debugger that is listening, as a VM started with server=n would.
+func (a *Agent) Attach(network string, address string) error {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return err
+	}
+	go a.ServeConn(conn)
+	return nil
+}
+
+// ServeConn answers the handshake and then the commands arriving on conn, until it closes.
+func (a *Agent) ServeConn(conn net.Conn) error {
+	defer conn.Close()
+	hs := make([]byte, len(client.Handshake))
+	if _, err := io.ReadFull(conn, hs); err != nil {
+		return err
+	}
+	if string(hs) != client.Handshake {
+		return fmt.Errorf("jdwptest: unexpected handshake %q", hs)
+	}
+	if _, err := conn.Write(hs); err != nil {
+		return err
+	}
+
+	s := &Session{agent: a, conn: conn}
+	a.mu.Lock()
+	a.sessions[s] = true
+	attached := a.attached
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		delete(a.sessions, s)
+		a.mu.Unlock()
+	}()
+	if attached != nil {
+		attached(s)
+	}
+
+	for {
+		p, err := s.receive()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		h := a.handler(p.Set, p.Command)
+		if h == nil {
+			s.reply(p.Id, client.ErrNotImplemented, nil)
+			continue
+		}
+		data, err := h(s, p)
+		s.reply(p.Id, err, data)
+	}
+}
+
+// Sessions lists the connections currently being served.
+func (a *Agent) Sessions() []*Session {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ss := []*Session{}
+	for s := range a.sessions {
+		ss = append(ss, s)
+	}
+	return ss
+}
+
+// Session is one debugger's connection to the agent.
+type Session struct {
+	agent   *Agent
+	conn    net.Conn
+	writing sync.Mutex
+	nextId  uint32
+}
+
+func (s *Session) Agent() *Agent {
+	return s.agent
+}
+
+func (s *Session) Close() error {
+	return s.conn.Close()
+}
+
+func (s *Session) receive() (*Packet, error) {
+	var hdr [client.HeaderLength]byte
+	if _, err := io.ReadFull(s.conn, hdr[:]); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(hdr[0:])
+	if length < client.HeaderLength {
+		return nil, fmt.Errorf("jdwptest: packet length %d is shorter than its header", length)
+	}
+	p := &Packet{
+		Id:      client.Id(binary.BigEndian.Uint32(hdr[4:])),
+		Set:     client.CommandSet(hdr[9]),
+		Command: client.Command(hdr[10]),
+		Data:    make([]byte, length-client.HeaderLength),
+	}
+	if _, err := io.ReadFull(s.conn, p.Data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *Session) reply(id client.Id, err error, data []byte) error {
+	var code uint16
+	if err != nil {
+		if e, ok := err.(client.JdwpError); ok {
+			code = e.Code
+		} else {
+			code = client.ErrInternal.(client.JdwpError).Code
+		}
+		data = nil
+	}
+	return s.write(id, client.FlagReply, code, data)
+}
+
+// Command sends a command from the VM to the debugger, such as a Composite event.
+func (s *Session) Command(set client.CommandSet, cmd client.Command, data []byte) error {
+	s.writing.Lock()
+	s.nextId++
+	id := client.Id(s.nextId)
+	s.writing.Unlock()
+	return s.write(id, 0, uint16(set)<<8|uint16(cmd), data)
+}
+
+// Event sends a Composite event command.
+func (s *Session) Event(data []byte) error {
+	return s.Command(client.EventCommandSet, client.CompositeCommands, data)
+}
+
+func (s *Session) write(id client.Id, flags uint8, pair uint16, data []byte) error {
+	packet := make([]byte, client.HeaderLength, client.HeaderLength+len(data))
+	binary.BigEndian.PutUint32(packet[0:], uint32(client.HeaderLength+len(data)))
+	binary.BigEndian.PutUint32(packet[4:], uint32(id))
+	packet[8] = flags
+	binary.BigEndian.PutUint16(packet[9:], pair)
+	packet = append(packet, data...)
+	s.writing.Lock()
+	defer s.writing.Unlock()
+	_, err := s.conn.Write(packet)
+	return err
+}
@@ -0,0 +1,188 @@
+package jdwptest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/jan-g/jdwp-client/client"
+)
+
+// Encoder builds a reply body. It deliberately shares no code with the client's own
+// encoding, so that the two check one another.
+type Encoder struct {
+	buf   bytes.Buffer
+	sizes client.IDSizes
+}
+
+func NewEncoder(sizes client.IDSizes) *Encoder {
+	return &Encoder{sizes: sizes}
+}
+
+func (e *Encoder) Byte(b uint8) *Encoder {
+	e.buf.WriteByte(b)
+	return e
+}
+
+func (e *Encoder) Int(i int32) *Encoder {
+	return e.sized(uint64(uint32(i)), 4)
+}
+
+func (e *Encoder) Long(l int64) *Encoder {
+	return e.sized(uint64(l), 8)
+}
+
+func (e *Encoder) String(s string) *Encoder {
+	e.Int(int32(len(s)))
+	e.buf.WriteString(s)
+	return e
+}
+
+func (e *Encoder) ObjectId(id uint64) *Encoder {
+	return e.sized(id, e.sizes.ObjectIDSize)
+}
+
+func (e *Encoder) ReferenceTypeId(id uint64) *Encoder {
+	return e.sized(id, e.sizes.ReferenceTypeIDSize)
+}
+
+func (e *Encoder) MethodId(id uint64) *Encoder {
+	return e.sized(id, e.sizes.MethodIDSize)
+}
+
+func (e *Encoder) FieldId(id uint64) *Encoder {
+	return e.sized(id, e.sizes.FieldIDSize)
+}
+
+func (e *Encoder) FrameId(id uint64) *Encoder {
+	return e.sized(id, e.sizes.FrameIDSize)
+}
+
+func (e *Encoder) Location(l client.Location) *Encoder {
+	return e.Byte(uint8(l.TypeTag)).
+		ReferenceTypeId(uint64(l.ClassId)).
+		MethodId(l.MethodId.MethodId).
+		Long(int64(l.Index))
+}
+
+// Value writes a tagged value.
+func (e *Encoder) Value(v Value) *Encoder {
+	e.Byte(uint8(v.Tag))
+	switch v.Tag {
+	case client.TagVoid:
+	case client.TagByte, client.TagBoolean:
+		e.sized(v.Bits, 1)
+	case client.TagChar, client.TagShort:
+		e.sized(v.Bits, 2)
+	case client.TagInt, client.TagFload:
+		e.sized(v.Bits, 4)
+	case client.TagLong, client.TagDouble:
+		e.sized(v.Bits, 8)
+	default:
+		e.ObjectId(v.Bits)
+	}
+	return e
+}
+
+func (e *Encoder) sized(v uint64, size int) *Encoder {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	e.buf.Write(b[8-size:])
+	return e
+}
+
+func (e *Encoder) Bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// Decoder reads a command body. The first error encountered sticks, and is reported by Err.
+type Decoder struct {
+	r     *bytes.Reader
+	sizes client.IDSizes
+	err   error
+}
+
+func NewDecoder(sizes client.IDSizes, data []byte) *Decoder {
+	return &Decoder{r: bytes.NewReader(data), sizes: sizes}
+}
+
+var errTrailing = errors.New("unread bytes at the end of the command")
+
+// Err reports the first error encountered, or any bytes left unread.
+func (d *Decoder) Err() error {
+	if d.err == nil && d.r.Len() > 0 {
+		return errTrailing
+	}
+	return d.err
+}
+
+func (d *Decoder) Byte() uint8 {
+	return uint8(d.sized(1))
+}
+
+func (d *Decoder) Int() int32 {
+	return int32(uint32(d.sized(4)))
+}
+
+func (d *Decoder) Long() int64 {
+	return int64(d.sized(8))
+}
+
+func (d *Decoder) String() string {
+	n := d.Int()
+	if d.err != nil || n < 0 || int(n) > d.r.Len() {
+		d.fail(io.ErrUnexpectedEOF)
+		return ""
+	}
+	b := make([]byte, n)
+	d.r.Read(b)
+	return string(b)
+}
+
+func (d *Decoder) ObjectId() uint64 {
+	return d.sized(d.sizes.ObjectIDSize)
+}
+
+func (d *Decoder) ReferenceTypeId() uint64 {
+	return d.sized(d.sizes.ReferenceTypeIDSize)
+}
+
+func (d *Decoder) MethodId() uint64 {
+	return d.sized(d.sizes.MethodIDSize)
+}
+
+func (d *Decoder) FieldId() uint64 {
+	return d.sized(d.sizes.FieldIDSize)
+}
+
+func (d *Decoder) FrameId() uint64 {
+	return d.sized(d.sizes.FrameIDSize)
+}
+
+func (d *Decoder) Location() client.Location {
+	var l client.Location
+	l.TypeTag = client.TypeTag(d.Byte())
+	l.ClassId = client.ClassId(d.ReferenceTypeId())
+	l.MethodId.MethodId = d.MethodId()
+	l.Index = uint64(d.Long())
+	return l
+}
+
+func (d *Decoder) sized(size int) uint64 {
+	if d.err != nil {
+		return 0
+	}
+	var b [8]byte
+	if _, err := io.ReadFull(d.r, b[8-size:]); err != nil {
+		d.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+func (d *Decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
@@ -0,0 +1,88 @@
+package jdwptest
+
+import (
+	"github.com/jan-g/jdwp-client/client"
+)
+
+// Breakpoint has t reach the location of its innermost frame. Each session with a
+// breakpoint request there is sent a Composite event, and the thread is suspended as
+// the strongest of those requests asks. It returns the number of events sent.
+func (a *Agent) Breakpoint(t *Thread) int {
+	vm := a.VM
+	vm.mu.Lock()
+	if len(t.Frames) == 0 {
+		vm.mu.Unlock()
+		return 0
+	}
+	loc := t.Frames[0].Location
+
+	hits := map[*Session][]*Request{}
+	all := []*Request{}
+	for id, r := range vm.requests {
+		if !r.matches(client.EventKindBreakpoint, t, loc) {
+			continue
+		}
+		if r.Count > 0 {
+			r.Count--
+			if r.Count > 0 {
+				continue
+			}
+			delete(vm.requests, id)
+		}
+		hits[r.session] = append(hits[r.session], r)
+		all = append(all, r)
+	}
+	vm.suspend(t, strongest(all))
+
+	events := map[*Session][]byte{}
+	for s, rs := range hits {
+		e := s.encoder().Byte(uint8(strongest(rs))).Int(int32(len(rs)))
+		for _, r := range rs {
+			e.Byte(uint8(client.EventKindBreakpoint)).Int(r.Id).ObjectId(uint64(t.Id)).Location(loc)
+		}
+		events[s] = e.Bytes()
+	}
+	vm.mu.Unlock()
+
+	// Send without the VM locked, so that the debugger can call back while handling them
+	sent := 0
+	for s, data := range events {
+		if s.Event(data) == nil {
+			sent += len(hits[s])
+		}
+	}
+	return sent
+}
+
+func (r *Request) matches(kind client.EventKind, t *Thread, loc client.Location) bool {
+	if r.Kind != kind || (r.Thread != 0 && r.Thread != t.Id) {
+		return false
+	}
+	if r.Location == nil {
+		return true
+	}
+	return r.Location.ClassId == loc.ClassId &&
+		r.Location.MethodId.MethodId == loc.MethodId.MethodId &&
+		r.Location.Index == loc.Index
+}
+
+func strongest(rs []*Request) client.SuspendPolicy {
+	policy := client.SuspendPolicyNone
+	for _, r := range rs {
+		if r.SuspendPolicy > policy {
+			policy = r.SuspendPolicy
+		}
+	}
+	return policy
+}
+
+// VMStart sends the event with which a VM started with suspend=y greets a debugger: the whole
+// VM is suspended, with t as its initial thread.
+func (s *Session) VMStart(t *Thread) error {
+	vm := s.agent.VM
+	vm.mu.Lock()
+	vm.suspends++
+	vm.mu.Unlock()
+	return s.Event(s.encoder().Byte(uint8(client.SuspendPolicyAll)).Int(1).
+		Byte(uint8(client.EventKindVM_START)).Int(0).ObjectId(uint64(t.Id)).Bytes())
+}
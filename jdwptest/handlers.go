@@ -0,0 +1,449 @@
+package jdwptest
+
+import (
+	"github.com/jan-g/jdwp-client/client"
+)
+
+// vmHandlers answer commands from the fake VM.
+var vmHandlers = map[key]Handler{
+	{client.VirtualMachine, client.VirtualMachineVersion}:            version,
+	{client.VirtualMachine, client.VirtualMachineClassesBySignature}: classesBySignature,
+	{client.VirtualMachine, client.VirtualMachineAllThreads}:         allThreads,
+	{client.VirtualMachine, client.VirtualMachineDispose}:            dispose,
+	{client.VirtualMachine, client.VirtualMachineIDSizes}:            idSizes,
+	{client.VirtualMachine, client.VirtualMachineSuspend}:            suspendVM,
+	{client.VirtualMachine, client.VirtualMachineResume}:             resumeVM,
+	{client.ReferenceType, client.ReferenceTypeSignature}:            signature,
+	{client.ReferenceType, client.ReferenceTypeFields}:               fields,
+	{client.ReferenceType, client.ReferenceTypeMethods}:              methods,
+	{client.ReferenceType, client.ReferenceTypeClassObject}:          classObject,
+	{client.Method, client.MethodLineTable}:                          lineTable,
+	{client.Method, client.MethodVariableTable}:                      variableTable,
+	{client.ObjectReference, client.ObjectReferenceReferenceType}:    referenceType,
+	{client.StringReference, client.StringReferenceValue}:            stringValue,
+	{client.Thread, client.ThreadName}:                               threadName,
+	{client.Thread, client.ThreadSuspend}:                            suspendThread,
+	{client.Thread, client.ThreadResume}:                             resumeThread,
+	{client.Thread, client.ThreadFrames}:                             frames,
+	{client.StackFrame, client.StackFrameGetValues}:                  getValues,
+	{client.EventRequest, client.Set}:                                setRequest,
+	{client.EventRequest, client.Clear}:                              clearRequest,
+	{client.EventRequest, client.ClearAllBreakPoints}:                clearAllBreakpoints,
+}
+
+func (s *Session) decoder(p *Packet) *Decoder {
+	return NewDecoder(s.agent.VM.Sizes, p.Data)
+}
+
+func (s *Session) encoder() *Encoder {
+	return NewEncoder(s.agent.VM.Sizes)
+}
+
+// lock gives a handler exclusive use of the VM, and a decoder for its command.
+func (s *Session) lock(p *Packet) (*VM, *Decoder) {
+	vm := s.agent.VM
+	vm.mu.Lock()
+	return vm, s.decoder(p)
+}
+
+func version(s *Session, p *Packet) ([]byte, error) {
+	vm, _ := s.lock(p)
+	defer vm.mu.Unlock()
+	v := vm.Version
+	return s.encoder().String(v.Description).Int(int32(v.JdwpMajor)).Int(int32(v.JdwpMinor)).
+		String(v.VmVersion).String(v.VmName).Bytes(), nil
+}
+
+func idSizes(s *Session, p *Packet) ([]byte, error) {
+	vm, _ := s.lock(p)
+	defer vm.mu.Unlock()
+	sz := vm.Sizes
+	return s.encoder().Int(int32(sz.FieldIDSize)).Int(int32(sz.MethodIDSize)).Int(int32(sz.ObjectIDSize)).
+		Int(int32(sz.ReferenceTypeIDSize)).Int(int32(sz.FrameIDSize)).Bytes(), nil
+}
+
+func classesBySignature(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	sig := d.String()
+	if err := d.Err(); err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	matches := []*Class{}
+	for _, c := range vm.classes {
+		if c.Signature == sig {
+			matches = append(matches, c)
+		}
+	}
+	e := s.encoder().Int(int32(len(matches)))
+	for _, c := range matches {
+		e.Byte(uint8(c.Tag)).ReferenceTypeId(uint64(c.Id)).Int(c.Status)
+	}
+	return e.Bytes(), nil
+}
+
+func allThreads(s *Session, p *Packet) ([]byte, error) {
+	vm, _ := s.lock(p)
+	defer vm.mu.Unlock()
+	e := s.encoder().Int(int32(len(vm.threads)))
+	for _, t := range vm.threads {
+		e.ObjectId(uint64(t.Id))
+	}
+	return e.Bytes(), nil
+}
+
+// dispose drops the session's event requests, and undoes its suspensions.
+func dispose(s *Session, p *Packet) ([]byte, error) {
+	vm, _ := s.lock(p)
+	defer vm.mu.Unlock()
+	for id, r := range vm.requests {
+		if r.session == s {
+			delete(vm.requests, id)
+		}
+	}
+	vm.suspends = 0
+	for _, t := range vm.threads {
+		t.suspended = 0
+	}
+	return nil, nil
+}
+
+func suspendVM(s *Session, p *Packet) ([]byte, error) {
+	vm, _ := s.lock(p)
+	defer vm.mu.Unlock()
+	vm.suspends++
+	return nil, nil
+}
+
+func resumeVM(s *Session, p *Packet) ([]byte, error) {
+	vm, _ := s.lock(p)
+	defer vm.mu.Unlock()
+	if vm.suspends > 0 {
+		vm.suspends--
+	}
+	for _, t := range vm.threads {
+		if t.suspended > 0 {
+			t.suspended--
+		}
+	}
+	return nil, nil
+}
+
+// classArg reads a referenceTypeID argument.
+func classArg(vm *VM, d *Decoder) (*Class, error) {
+	c := vm.class(d.ReferenceTypeId())
+	if d.err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	if c == nil {
+		return nil, client.ErrInvalidClass
+	}
+	return c, nil
+}
+
+func signature(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	c, err := classArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	return s.encoder().String(c.Signature).Bytes(), nil
+}
+
+func classObject(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	c, err := classArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	return s.encoder().ObjectId(uint64(c.Object)).Bytes(), nil
+}
+
+func fields(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	c, err := classArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	e := s.encoder().Int(int32(len(c.Fields)))
+	for _, f := range c.Fields {
+		e.FieldId(uint64(f.Id)).String(f.Name).String(f.Signature).Int(int32(f.ModBits))
+	}
+	return e.Bytes(), nil
+}
+
+func methods(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	c, err := classArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	e := s.encoder().Int(int32(len(c.Methods)))
+	for _, m := range c.Methods {
+		e.MethodId(m.Id).String(m.Name).String(m.Signature).Int(int32(m.ModBits))
+	}
+	return e.Bytes(), nil
+}
+
+// methodArg reads a referenceTypeID and methodID pair.
+func methodArg(vm *VM, d *Decoder) (*Method, error) {
+	c, err := classArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	m := c.method(d.MethodId())
+	if err := d.Err(); err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	if m == nil {
+		return nil, client.ErrInvalidMethodId
+	}
+	return m, nil
+}
+
+func lineTable(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	m, err := methodArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	e := s.encoder().Long(m.Start).Long(m.End).Int(int32(len(m.Lines)))
+	for _, l := range m.Lines {
+		e.Long(l.LineCodeIndex).Int(int32(l.LineNumber))
+	}
+	return e.Bytes(), nil
+}
+
+func variableTable(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	m, err := methodArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	if m.Variables == nil {
+		return nil, client.ErrAbsentInformation
+	}
+	e := s.encoder().Int(m.ArgCount).Int(int32(len(m.Variables)))
+	for _, v := range m.Variables {
+		e.Long(int64(v.CodeIndex)).String(v.Name).String(v.Signature).Int(int32(v.Length)).Int(int32(v.Slot))
+	}
+	return e.Bytes(), nil
+}
+
+func referenceType(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	id := d.ObjectId()
+	if err := d.Err(); err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	o, ok := vm.objects[id]
+	if !ok {
+		return nil, client.ErrInvalidObject
+	}
+	return s.encoder().Byte(uint8(o.Class.Tag)).ReferenceTypeId(uint64(o.Class.Id)).Bytes(), nil
+}
+
+func stringValue(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	id := d.ObjectId()
+	if err := d.Err(); err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	str, ok := vm.strings[id]
+	if !ok {
+		return nil, client.ErrInvalidString
+	}
+	return s.encoder().String(str).Bytes(), nil
+}
+
+// threadArg reads a threadID argument.
+func threadArg(vm *VM, d *Decoder) (*Thread, error) {
+	t := vm.thread(d.ObjectId())
+	if d.err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	if t == nil {
+		return nil, client.ErrInvalidThread
+	}
+	return t, nil
+}
+
+func threadName(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	t, err := threadArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	return s.encoder().String(t.Name).Bytes(), nil
+}
+
+func suspendThread(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	t, err := threadArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	t.suspended++
+	return nil, nil
+}
+
+func resumeThread(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	t, err := threadArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	if t.suspended > 0 {
+		t.suspended--
+	}
+	return nil, nil
+}
+
+func frames(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	t, err := threadArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	start, length := int(d.Int()), int(d.Int())
+	if err := d.Err(); err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	if t.suspended+vm.suspends == 0 {
+		return nil, client.ErrThreadNotSuspended
+	}
+	if length == -1 {
+		length = len(t.Frames) - start
+	}
+	if start < 0 || length < 0 || start+length > len(t.Frames) {
+		return nil, client.ErrInvalidIndex
+	}
+	e := s.encoder().Int(int32(length))
+	for _, f := range t.Frames[start : start+length] {
+		e.FrameId(uint64(f.Id)).Location(f.Location)
+	}
+	return e.Bytes(), nil
+}
+
+func getValues(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	t, err := threadArg(vm, d)
+	if err != nil {
+		return nil, err
+	}
+	frameId := d.FrameId()
+	var frame *Frame
+	for _, f := range t.Frames {
+		if uint64(f.Id) == frameId {
+			frame = f
+		}
+	}
+	count := d.Int()
+	values := []Value{}
+	for i := int32(0); i < count && d.err == nil; i++ {
+		slot, _ := d.Int(), d.Byte()
+		if v, ok := frame.local(int(slot)); ok {
+			values = append(values, v)
+		} else if frame != nil {
+			return nil, client.ErrInvalidSlot
+		}
+	}
+	if err := d.Err(); err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	if frame == nil {
+		return nil, client.ErrInvalidFrameId
+	}
+	e := s.encoder().Int(int32(len(values)))
+	for _, v := range values {
+		e.Value(v)
+	}
+	return e.Bytes(), nil
+}
+
+func (f *Frame) local(slot int) (Value, bool) {
+	if f == nil {
+		return Value{}, false
+	}
+	v, ok := f.Locals[slot]
+	return v, ok
+}
+
+func setRequest(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	r := &Request{
+		Kind:          client.EventKind(d.Byte()),
+		SuspendPolicy: client.SuspendPolicy(d.Byte()),
+		session:       s,
+	}
+	mods := d.Int()
+	for i := int32(0); i < mods && d.err == nil; i++ {
+		switch client.ModKind(d.Byte()) {
+		case client.ModKindCount:
+			r.Count = d.Int()
+		case client.ModKindThreadOnly:
+			r.Thread = client.ThreadId(d.ObjectId())
+		case client.ModKindLocation:
+			l := d.Location()
+			r.Location = &l
+		case client.ModKindClassMatch:
+			r.ClassMatch = append(r.ClassMatch, d.String())
+		case client.ModKindClassExclude:
+			r.ClassExclude = append(r.ClassExclude, d.String())
+		case client.ModKindException:
+			r.Exception = client.ClassId(d.ReferenceTypeId())
+			r.Caught = d.Byte() != 0
+			r.Uncaught = d.Byte() != 0
+		default:
+			return nil, client.ErrNotImplemented
+		}
+	}
+	if err := d.Err(); err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	if r.Kind == client.EventKindBreakpoint && r.Location == nil {
+		return nil, client.ErrIllegalArgument
+	}
+	vm.nextReq++
+	r.Id = vm.nextReq
+	vm.requests[r.Id] = r
+	return s.encoder().Int(r.Id).Bytes(), nil
+}
+
+func clearRequest(s *Session, p *Packet) ([]byte, error) {
+	vm, d := s.lock(p)
+	defer vm.mu.Unlock()
+	kind, id := client.EventKind(d.Byte()), d.Int()
+	if err := d.Err(); err != nil {
+		return nil, client.ErrIllegalArgument
+	}
+	if r, ok := vm.requests[id]; ok && r.Kind == kind {
+		delete(vm.requests, id)
+	}
+	return nil, nil
+}
+
+func clearAllBreakpoints(s *Session, p *Packet) ([]byte, error) {
+	vm, _ := s.lock(p)
+	defer vm.mu.Unlock()
+	for id, r := range vm.requests {
+		if r.Kind == client.EventKindBreakpoint {
+			delete(vm.requests, id)
+		}
+	}
+	return nil, nil
+}
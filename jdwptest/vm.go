@@ -0,0 +1,302 @@
+package jdwptest
+
+import (
+	"math"
+	"sync"
+
+	"github.com/jan-g/jdwp-client/client"
+)
+
+// VM is the state of a small fake virtual machine: just enough classes, threads and
+// objects for the client's commands to have something to look at.
+//
+// Build it up before serving connections; the agent locks it while answering commands.
+type VM struct {
+	mu       sync.Mutex
+	Sizes    client.IDSizes
+	Version  client.VersionReply
+	nextId   uint64
+	classes  []*Class
+	threads  []*Thread
+	objects  map[uint64]*Object
+	strings  map[uint64]string
+	requests map[int32]*Request
+	nextReq  int32
+	suspends int // VM-wide suspensions outstanding
+}
+
+func NewVM() *VM {
+	return &VM{
+		Sizes: client.DefaultIDSizes,
+		Version: client.VersionReply{
+			Description: "jdwptest fake VM",
+			JdwpMajor:   1,
+			JdwpMinor:   8,
+			VmVersion:   "1.8.0",
+			VmName:      "jdwptest",
+		},
+		objects:  map[uint64]*Object{},
+		strings:  map[uint64]string{},
+		requests: map[int32]*Request{},
+	}
+}
+
+// id hands out identifiers from a single space, small enough to fit any ID size.
+func (vm *VM) id() uint64 {
+	vm.nextId++
+	return vm.nextId
+}
+
+type Class struct {
+	Id        client.ClassId
+	Object    client.ClassObjectId // the java.lang.Class instance for the class
+	Tag       client.TypeTag
+	Signature string
+	Status    int32
+	Methods   []*Method
+	Fields    []*Field
+	vm        *VM
+}
+
+type Method struct {
+	Id        uint64
+	Class     *Class
+	Name      string
+	Signature string
+	ModBits   uint32
+	ArgCount  int32
+	Start     int64
+	End       int64
+	Lines     []client.LineEntry
+	Variables []client.VariableDef
+}
+
+type Field struct {
+	Id        client.FieldId
+	Name      string
+	Signature string
+	ModBits   uint32
+}
+
+type Thread struct {
+	Id        client.ThreadId
+	Name      string
+	Frames    []*Frame // the innermost frame first
+	suspended int
+	vm        *VM
+}
+
+type Frame struct {
+	Id       client.FrameId
+	Location client.Location
+	Locals   map[int]Value // by slot
+}
+
+type Object struct {
+	Id    client.ObjectId
+	Class *Class
+}
+
+// Value is a tagged value: an object ID, or the bits of a primitive.
+type Value struct {
+	Tag  client.Tag
+	Bits uint64
+}
+
+func ObjectValue(o *Object) Value {
+	return Value{Tag: client.TagObject, Bits: uint64(o.Id)}
+}
+
+func StringValue(id client.StringId) Value {
+	return Value{Tag: client.TagString, Bits: uint64(id)}
+}
+
+func IntValue(i int32) Value {
+	return Value{Tag: client.TagInt, Bits: uint64(uint32(i))}
+}
+
+func DoubleValue(d float64) Value {
+	return Value{Tag: client.TagDouble, Bits: math.Float64bits(d)}
+}
+
+// Request is an event request set by a client.
+type Request struct {
+	Id            int32
+	Kind          client.EventKind
+	SuspendPolicy client.SuspendPolicy
+	Count         int32            // events left before the request expires; 0 for no limit
+	Location      *client.Location // for breakpoints
+	Thread        client.ThreadId  // if non-zero, only events on this thread
+	ClassMatch    []string         // class patterns, recorded but not applied
+	ClassExclude  []string         // class patterns, recorded but not applied
+	Exception     client.ClassId   // for exceptions, the type reported, or 0 for all
+	Caught        bool             // for exceptions, whether caught ones are reported
+	Uncaught      bool             // for exceptions, whether uncaught ones are reported
+	session       *Session
+}
+
+// Class status bits, as reported by ClassesBySignature.
+const (
+	ClassStatusVerified    = 1
+	ClassStatusPrepared    = 2
+	ClassStatusInitialized = 4
+)
+
+func (vm *VM) AddClass(signature string) *Class {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	c := &Class{
+		Id:        client.ClassId(vm.id()),
+		Object:    client.ClassObjectId(vm.id()),
+		Tag:       client.TypeTagClass,
+		Signature: signature,
+		Status:    ClassStatusVerified | ClassStatusPrepared | ClassStatusInitialized,
+		vm:        vm,
+	}
+	vm.classes = append(vm.classes, c)
+	return c
+}
+
+// AddMethod adds a method whose code runs from 0 to end. Lines map code indices to line numbers.
+func (c *Class) AddMethod(name string, signature string, end int64, lines ...client.LineEntry) *Method {
+	c.vm.mu.Lock()
+	defer c.vm.mu.Unlock()
+	m := &Method{
+		Id:        c.vm.id(),
+		Class:     c,
+		Name:      name,
+		Signature: signature,
+		End:       end,
+		Lines:     lines,
+	}
+	c.Methods = append(c.Methods, m)
+	return m
+}
+
+func (c *Class) AddField(name string, signature string) *Field {
+	c.vm.mu.Lock()
+	defer c.vm.mu.Unlock()
+	f := &Field{Id: client.FieldId(c.vm.id()), Name: name, Signature: signature}
+	c.Fields = append(c.Fields, f)
+	return f
+}
+
+// AddVariable declares a local variable in slot, live for length code indices from start.
+func (m *Method) AddVariable(name string, signature string, slot int, start uint64, length uint32) *Method {
+	m.Variables = append(m.Variables, client.VariableDef{
+		CodeIndex: start,
+		Name:      name,
+		Signature: signature,
+		Length:    length,
+		Slot:      slot,
+	})
+	return m
+}
+
+// Location is the point in m at the given code index.
+func (m *Method) Location(index int64) client.Location {
+	return client.Location{
+		TypeTag:  m.Class.Tag,
+		ClassId:  m.Class.Id,
+		MethodId: client.MethodId{MethodId: m.Id},
+		Index:    uint64(index),
+	}
+}
+
+// LineLocation is the start of the given source line in m.
+func (m *Method) LineLocation(line int) client.Location {
+	for _, l := range m.Lines {
+		if l.LineNumber == line {
+			return m.Location(l.LineCodeIndex)
+		}
+	}
+	panic("no such line")
+}
+
+func (vm *VM) AddThread(name string) *Thread {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	t := &Thread{Id: client.ThreadId(vm.id()), Name: name, vm: vm}
+	vm.threads = append(vm.threads, t)
+	return t
+}
+
+// Call pushes a new innermost frame onto the thread's stack.
+func (t *Thread) Call(m *Method, index int64, locals map[int]Value) *Frame {
+	t.vm.mu.Lock()
+	defer t.vm.mu.Unlock()
+	f := &Frame{Id: client.FrameId(t.vm.id()), Location: m.Location(index), Locals: locals}
+	t.Frames = append([]*Frame{f}, t.Frames...)
+	return f
+}
+
+// SuspendCount is the number of times the thread has been suspended and not yet resumed,
+// whether individually or along with the whole VM.
+func (t *Thread) SuspendCount() int {
+	t.vm.mu.Lock()
+	defer t.vm.mu.Unlock()
+	return t.suspended + t.vm.suspends
+}
+
+func (vm *VM) NewObject(c *Class) *Object {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	o := &Object{Id: client.ObjectId(vm.id()), Class: c}
+	vm.objects[uint64(o.Id)] = o
+	return o
+}
+
+func (vm *VM) NewString(s string) client.StringId {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	id := vm.id()
+	vm.strings[id] = s
+	return client.StringId(id)
+}
+
+// Requests lists the event requests currently set.
+func (vm *VM) Requests() []*Request {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	rs := []*Request{}
+	for _, r := range vm.requests {
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+func (vm *VM) class(id uint64) *Class {
+	for _, c := range vm.classes {
+		if uint64(c.Id) == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (vm *VM) thread(id uint64) *Thread {
+	for _, t := range vm.threads {
+		if uint64(t.Id) == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (c *Class) method(id uint64) *Method {
+	for _, m := range c.Methods {
+		if m.Id == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func (vm *VM) suspend(t *Thread, policy client.SuspendPolicy) {
+	switch policy {
+	case client.SuspendPolicyEventThread:
+		t.suspended++
+	case client.SuspendPolicyAll:
+		vm.suspends++
+	}
+}
@@ -4,8 +4,13 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/sirupsen/logrus"
 
@@ -14,8 +19,14 @@ import (
 
 var (
 	level   = flag.String("log", "debug", "log level")
-	net     = flag.String("net", "tcp", "network type")
-	address = flag.String("addr", "localhost:59999", "address to connect to")
+	net     = flag.String("net", "tcp", "transport: tcp, unix, or exec to speak over the stdin and stdout of a command")
+	address = flag.String("addr", "localhost:59999", "address to connect to; for -net exec, the command line to run")
+	listen  = flag.Bool("listen", false, "listen on the address for VMs started with server=n to attach")
+	record  = flag.String("record", "", "file to capture every packet to; read it back with the dump command")
+	metrics = flag.String("metrics", "", "address to serve Prometheus metrics on, such as :9100")
+	safe    = flag.Bool("read-only", false, "refuse to send commands that could change the state of the VM")
+	budget  = flag.Duration("suspend-budget", 0, "resume any thread left suspended for longer than this; 0 for no limit")
+	allow   = flag.String("allow", "", "with -read-only, commands to send regardless, as a comma-separated list of set.command")
 
 	cls        = flag.String("class", "Lorg/ioctl/debug/app/WebServer$Handler;", "class to break on")
 	methodName = flag.String("method", "handle", "method to break on")
@@ -31,17 +42,161 @@ func main() {
 		panic(err)
 	}
 	logrus.SetLevel(log)
-	c, err := client.Dial(*net, *address)
+
+	switch flag.Arg(0) {
+	case "", "launch", "proxy":
+	case "dump":
+		if err := dump(flag.Arg(1)); err != nil {
+			panic(err)
+		}
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		os.Exit(2)
+	}
+
+	var opts []client.Option
+	if *record != "" {
+		f, err := os.Create(*record)
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		opts = append(opts, client.WithCapture(f))
+	}
+	if *safe {
+		allowed, err := commandKeys(*allow)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "-allow:", err)
+			os.Exit(2)
+		}
+		opts = append(opts, client.WithPolicy(client.ReadOnly(allowed...)))
+	}
+	if *budget > 0 {
+		opts = append(opts, client.WithSuspendBudget(*budget))
+	}
+	if *metrics != "" {
+		m := client.NewMetrics()
+		opts = append(opts, client.WithObserver(m))
+		go func() {
+			logrus.WithError(http.ListenAndServe(*metrics, m)).Error("metrics server stopped")
+		}()
+	}
+
+	if flag.Arg(0) == "launch" {
+		// launch -- java ...
+		args := flag.Args()[1:]
+		if len(args) > 0 && args[0] == "--" {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "launch needs a java command to run")
+			os.Exit(2)
+		}
+		p, err := client.Launch(args[0], args[1:], client.WithClientOptions(opts...))
+		if err != nil {
+			panic(err)
+		}
+		closeOnSignal(p)
+		session(p)
+		return
+	}
+
+	if flag.Arg(0) == "proxy" {
+		// proxy ADDRESS: share the VM with debuggers attaching there
+		if flag.NArg() != 2 {
+			fmt.Fprintln(os.Stderr, "proxy needs an address to listen on for debuggers")
+			os.Exit(2)
+		}
+		t, err := client.TransportFor(*net, *address)
+		if err != nil {
+			panic(err)
+		}
+		c, err := client.Open(t, opts...)
+		if err != nil {
+			panic(err)
+		}
+		defer c.Close()
+		closeOnSignal(c)
+		fmt.Println("Proxying for debuggers on", flag.Arg(1))
+		if err := client.NewProxy(c).ListenAndServe("tcp", flag.Arg(1)); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if *listen {
+		l, err := client.Listen(*net, *address, opts...)
+		if err != nil {
+			panic(err)
+		}
+		defer l.Close()
+		fmt.Println("Listening for VMs on", l.Addr())
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				panic(err)
+			}
+			session(c)
+		}
+	}
+
+	t, err := client.TransportFor(*net, *address)
 	if err != nil {
 		panic(err)
 	}
+	c, err := client.Open(t, opts...)
+	if err != nil {
+		panic(err)
+	}
+	closeOnSignal(c)
+	session(c)
+}
+
+// closeOnSignal closes c when the program is interrupted, so that a client with a suspend
+// budget resumes whatever it has suspended before the program exits.
+func closeOnSignal(c io.Closer) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		c.Close()
+		os.Exit(1)
+	}()
+}
+
+// commandKeys parses a list of commands such as "1.8,16.2".
+func commandKeys(list string) ([]client.CommandKey, error) {
+	var keys []client.CommandKey
+	for _, item := range strings.Split(list, ",") {
+		if item == "" {
+			continue
+		}
+		var set, cmd uint8
+		if _, err := fmt.Sscanf(item, "%d.%d", &set, &cmd); err != nil {
+			return nil, fmt.Errorf("%q is not a command set and command: %v", item, err)
+		}
+		keys = append(keys, client.CommandKey{Set: client.CommandSet(set), Command: client.Command(cmd)})
+	}
+	return keys, nil
+}
+
+func session(c client.Client) {
 	r, err := c.Call(client.VirtualMachine, client.VirtualMachineVersion, []byte{})
+	if err != nil {
+		fmt.Println("Version failed:", err)
+		return
+	}
 	var v client.VersionReply
 	client.Parse(r.Data, &v)
-	fmt.Printf("Vesion: %+v\n", v)
+	fmt.Printf("Version: %+v\n", v)
 
 	_, _ = referenceType(c, "Ljava/lang/String;")
-	myClasses, _ := referenceType(c, *cls)
+	myClasses, err := referenceType(c, *cls)
+	if err != nil || len(myClasses) == 0 {
+		fmt.Println("class not found:", *cls, err)
+		return
+	}
 	myClass := myClasses[0]
 
 	sig, err := myClass.Signature(c)
@@ -50,6 +205,9 @@ func main() {
 	// Get methods
 	ms, err := myClass.Methods(c)
 	fmt.Println("class methods are", err, ms)
+	if err != nil || len(ms) == 0 {
+		return
+	}
 
 	m := ms[0]
 	for _, mm := range ms {
@@ -62,6 +220,9 @@ func main() {
 	// Get line map
 	lines, err := m.MethodId.LineTable(c)
 	fmt.Println("line table:", err, lines)
+	if err != nil || len(lines.LineEntries) == 0 {
+		return
+	}
 
 	l := lines.LineEntries[0]
 	for _, ll := range lines.LineEntries {
@@ -77,10 +238,16 @@ func main() {
 		client.NewEventRequestSet(client.EventKindBreakpoint, client.SuspendPolicyEventThread).
 			WithMod(client.ModKindLocation).WithLocation(location).
 			WithMod(client.ModKindCount).WithInt(1).
-			Marshal())
+			Marshal(c.IDSizes()))
 	var bp client.EventRequestSetReply
-	err = client.Parse(r.Data, &bp)
-	fmt.Printf("breakpoint response received: %v, %+v -> %+v\n", err, *r, bp)
+	if err == nil {
+		err = client.Parse(r.Data, &bp)
+	}
+	if err != nil {
+		fmt.Println("breakpoint not set:", err)
+	} else {
+		fmt.Printf("breakpoint response received: %+v -> %+v\n", *r, bp)
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -95,52 +262,65 @@ func main() {
 				}
 				logrus.Debugf("event received: %+v\n", *e)
 				var comp client.Composite
-				err := client.Parse(e.Data, &comp)
+				err := client.Decode(c, e.Data, &comp)
+				if err != nil || len(comp.Events) == 0 {
+					fmt.Printf("composite received: %v, %+v (connection: %v)\n", err, comp, c.Err())
+					continue
+				}
 
-				bp := comp.Events[0].(*client.EventBreakpoint)
+				bp, isBreakpoint := comp.Events[0].(*client.EventBreakpoint)
+				if !isBreakpoint {
+					fmt.Printf("composite received: %v, %+v (connection: %v)\n", err, comp, c.Err())
+					continue
+				}
 				fmt.Printf("composite received: %v, %+v %+v\n", err, comp, bp)
 
 				frames, err := bp.Thread.Frames(c, 0, 3)
 				fmt.Printf("frames %v %+v", err, frames)
+				if err == nil && len(frames) > 0 {
+					for _, f := range frames {
+						sig, err := f.Location.ClassId.Signature(c)
+						fmt.Println("Frame: ", f.FrameId, sig, lookupMethod(c, f.Location.ClassId, f.Location.MethodId), err)
+						fmt.Println(" Variables in frame:", lookupVars(c, f.Location.ClassId, f.Location.MethodId))
+					}
+					fmt.Println()
 
-				for _, f := range frames {
-					sig, err := f.Location.ClassId.Signature(c)
-					fmt.Println("Frame: ", f.FrameId, sig, lookupMethod(c, f.Location.ClassId, f.Location.MethodId), err)
-					fmt.Println(" Variables in frame:", lookupVars(c, f.Location.ClassId, f.Location.MethodId))
-				}
-				fmt.Println()
-
-				// Work out the variable to get
-				vars := lookupVars(c, frames[0].Location.ClassId, frames[0].Location.MethodId)
+					// Work out the variable to get
+					vars := lookupVars(c, frames[0].Location.ClassId, frames[0].Location.MethodId)
 
-				vds := []client.VariableDef{}
-				for _, vdef := range vars {
-					logrus.Debugf("Variable is at: %+v\n", vdef)
-					vds = append(vds, vdef)
-				}
-				if vs, err := frames[0].GetValues(c, vds...); err == nil {
-					for vname, valRef := range vs {
-						// Get the value
-						logrus.Debugf("Variable %v is %v %+v\n", vname, err, valRef)
-						// Recover the referent
-						value, err := vs[vname].RecoverValue(c)
-						if err != nil {
-							logrus.WithError(err).Error("problem recovering value")
-						} else {
-							fmt.Printf("*** %s = %+v\n", vname, value)
-							switch o := value.(type) {
-							case *client.Object:
-								fmt.Printf("    class = %+v\n", *o.Class)
+					vds := []client.VariableDef{}
+					for _, vdef := range vars {
+						logrus.Debugf("Variable is at: %+v\n", vdef)
+						vds = append(vds, vdef)
+					}
+					if vs, err := frames[0].GetValues(c, vds...); err == nil {
+						for vname, valRef := range vs {
+							// Get the value
+							logrus.Debugf("Variable %v is %v %+v\n", vname, err, valRef)
+							// Recover the referent
+							value, err := vs[vname].RecoverValue(c)
+							if err != nil {
+								logrus.WithError(err).Error("problem recovering value")
+							} else {
+								fmt.Printf("*** %s = %+v\n", vname, value)
+								switch o := value.(type) {
+								case *client.Object:
+									fmt.Printf("    class = %+v\n", *o.Class)
+								}
 							}
 						}
+					} else {
+						logrus.Error("Problem getting variables: ", err)
 					}
-				} else {
-					logrus.Error("Problem getting variables: ", err)
 				}
 
 				r, err := c.Call(client.Thread, client.ThreadResume,
-					client.Seq().ThreadId(bp.Thread).Marshal())
-				logrus.Debugf("response received to Resume: %v %+v\n", err, *r)
+					c.IDSizes().Seq().ThreadId(bp.Thread).Marshal())
+				if err != nil {
+					logrus.WithError(err).Error("problem resuming thread")
+				} else {
+					logrus.Debugf("response received to Resume: %+v\n", *r)
+				}
 
 			}
 		}
@@ -150,30 +330,42 @@ func main() {
 	fmt.Println(foo)
 
 	r, err = c.Call(client.EventRequest, client.Clear,
-		client.Seq().
+		c.IDSizes().Seq().
 			Octet(uint8(client.EventKindBreakpoint)).
 			Int(bp.RequestId).
 			Marshal())
-	fmt.Printf("response received to Clear: %+v\n", *r)
+	if err != nil {
+		fmt.Println("Clear failed:", err)
+	} else {
+		fmt.Printf("response received to Clear: %+v\n", *r)
+	}
 
 	r, err = c.Call(client.EventRequest, client.ClearAllBreakPoints, []byte{})
-	fmt.Printf("response received to ClearAllBreakpoints: %+v\n", *r)
+	if err != nil {
+		fmt.Println("ClearAllBreakpoints failed:", err)
+	} else {
+		fmt.Printf("response received to ClearAllBreakpoints: %+v\n", *r)
+	}
 
 	r, err = c.Call(client.VirtualMachine, client.VirtualMachineDispose, []byte{})
-	fmt.Printf("response received to Dispose: %+v\n", *r)
+	if err != nil {
+		fmt.Println("Dispose failed:", err)
+	} else {
+		fmt.Printf("response received to Dispose: %+v\n", *r)
+	}
 
 	c.Close()
 	wg.Wait()
 }
 
 func referenceType(c client.Client, class string) ([]client.ClassId, error) {
-	r, err := c.Call(client.VirtualMachine, client.VirtualMachineClassesBySignature, client.Seq().String(class).Marshal())
+	r, err := c.Call(client.VirtualMachine, client.VirtualMachineClassesBySignature, c.IDSizes().Seq().String(class).Marshal())
 	if err != nil {
 		return nil, err
 	}
 	//logrus.Debugf("response received to ClassesBySignature: %+v\n", *r)
 	var cws client.ClassesBySignatureReply
-	if err := client.Parse(r.Data, &cws); err == nil {
+	if err := c.IDSizes().Parse(r.Data, &cws); err == nil {
 		//logrus.Debugf("response received to ClassesBySignature: %+v\n", cws)
 		rts := []client.ClassId{}
 		for _, cd := range cws.ClassDetails {
@@ -181,7 +373,7 @@ func referenceType(c client.Client, class string) ([]client.ClassId, error) {
 		}
 		return rts, nil
 	} else {
-		logrus.Errorf("response received to ClassesBySignature unmarshaling:", err)
+		logrus.Errorf("response received to ClassesBySignature unmarshaling: %v", err)
 		return nil, err
 	}
 }